@@ -0,0 +1,116 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// AttributionResult 收益归因结果
+type AttributionResult struct {
+	Periods []PeriodAttribution // 每个月度区间的归因
+	Symbols []SymbolAttribution // 按累计盈亏从高到低排序的股票贡献
+	Costs   decimal.Decimal     // 累计融资利息和融券费用
+}
+
+// AnalyzeAttribution 将每个区间的组合收益拆分为各股票贡献、现金拖累和费用
+// 区间 k 的股票贡献 = 期初持股 × (期末价格 - 期初价格) / 期初总价值，
+// 期末价格取期末持仓的当前价格，已清仓股票取当月卖出或回补的成交价
+func AnalyzeAttribution(reports []*MonthlyReport, initialCapital decimal.Decimal) *AttributionResult {
+	result := &AttributionResult{Costs: decimal.Zero}
+	if len(reports) == 0 {
+		return result
+	}
+
+	pnl := make(map[string]decimal.Decimal)
+	weightSum := make(map[string]decimal.Decimal)
+	periodsHeld := make(map[string]int)
+
+	for k := 1; k < len(reports); k++ {
+		previous := reports[k-1]
+		current := reports[k]
+
+		period := PeriodAttribution{
+			Date:              current.Date,
+			PortfolioReturn:   current.MonthlyReturn,
+			Contributions:     make(map[string]decimal.Decimal),
+			StockContribution: decimal.Zero,
+		}
+
+		costs := current.InterestCharged.Add(current.BorrowFee)
+		result.Costs = result.Costs.Add(costs)
+
+		if previous.TotalValue.IsPositive() {
+			stockPnL := decimal.Zero
+			for symbol, position := range previous.Positions {
+				endPrice, ok := periodEndPrice(current, symbol)
+				if !ok {
+					continue
+				}
+				positionPnL := position.Shares.Mul(endPrice.Sub(position.CurrentPrice))
+				contribution := positionPnL.Div(previous.TotalValue)
+
+				period.Contributions[symbol] = contribution
+				period.StockContribution = period.StockContribution.Add(contribution)
+				stockPnL = stockPnL.Add(positionPnL)
+
+				pnl[symbol] = pnl[symbol].Add(positionPnL)
+				weightSum[symbol] = weightSum[symbol].Add(position.MarketValue.Div(previous.TotalValue))
+				periodsHeld[symbol]++
+			}
+
+			period.CashWeight = previous.Cash.Sub(previous.Borrowed).Div(previous.TotalValue)
+			period.Costs = costs.Neg().Div(previous.TotalValue)
+
+			// 现金拖累：现金部分若按持仓收益率投资所能获得的收益
+			stockValue := previous.TotalValue.Sub(previous.Cash).Add(previous.Borrowed)
+			if stockValue.IsPositive() {
+				investedReturn := stockPnL.Div(stockValue)
+				period.CashDrag = period.CashWeight.Mul(investedReturn).Neg()
+			}
+
+			period.Residual = period.PortfolioReturn.Sub(period.StockContribution).Sub(period.Costs)
+		}
+
+		result.Periods = append(result.Periods, period)
+	}
+
+	lastReport := reports[len(reports)-1]
+	for symbol, symbolPnL := range pnl {
+		attribution := SymbolAttribution{
+			Symbol:      symbol,
+			PnL:         symbolPnL,
+			PeriodsHeld: periodsHeld[symbol],
+		}
+		if initialCapital.IsPositive() {
+			attribution.Contribution = symbolPnL.Div(initialCapital)
+		}
+		if periodsHeld[symbol] > 0 {
+			attribution.AverageWeight = weightSum[symbol].Div(decimal.NewFromInt(int64(periodsHeld[symbol])))
+		}
+		_, attribution.Open = lastReport.Positions[symbol]
+		result.Symbols = append(result.Symbols, attribution)
+	}
+
+	sort.Slice(result.Symbols, func(i, j int) bool {
+		if result.Symbols[i].PnL.Equal(result.Symbols[j].PnL) {
+			return result.Symbols[i].Symbol < result.Symbols[j].Symbol
+		}
+		return result.Symbols[i].PnL.GreaterThan(result.Symbols[j].PnL)
+	})
+
+	return result
+}
+
+// periodEndPrice 返回股票在区间结束时的价格
+func periodEndPrice(report *MonthlyReport, symbol string) (decimal.Decimal, bool) {
+	if position, exists := report.Positions[symbol]; exists {
+		return position.CurrentPrice, true
+	}
+	for _, action := range report.TradingActions {
+		if action.Symbol == symbol && (action.Action == "SELL" || action.Action == "COVER") {
+			return action.Price, true
+		}
+	}
+	return decimal.Zero, false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// BenchmarkCurve 与月度报告日期对齐的基准指数净值曲线
+type BenchmarkCurve struct {
+	Symbol  string           // 基准指数代码
+	Reports []*MonthlyReport // 按初始资金换算的基准净值，与策略月度报告一一对应，便于复用绩效指标计算
+}
+
+// LoadBenchmarkCurve 加载基准指数，取每个报告月份首个交易日的收盘价（与策略估值时点一致）并按初始资金换算
+// 某月没有数据时沿用上月收盘价
+func LoadBenchmarkCurve(config *Config, reports []*MonthlyReport) (*BenchmarkCurve, error) {
+	if len(reports) == 0 {
+		return nil, errors.New(T("没有报告数据"))
+	}
+
+	dataLoader := NewStockDataLoader(config.IndexPriceDir, config.HistoryDir)
+	indexSource, err := newIndexPriceSource(config)
+	if err != nil {
+		return nil, err
+	}
+	dataLoader.SetPriceSource(indexSource)
+	prices, err := dataLoader.LoadStockPrice(config.Benchmark)
+	if err != nil {
+		return nil, fmt.Errorf(T("加载基准 %s 数据失败: %v"), config.Benchmark, err)
+	}
+
+	initialCapital := decimal.NewFromFloat(config.InitialCapital)
+	curve := &BenchmarkCurve{Symbol: config.Benchmark}
+	var baseClose, lastClose, lastValue decimal.Decimal
+
+	for _, report := range reports {
+		tradingDay, err := dataLoader.GetFirstTradingDay(report.Date.Year(), int(report.Date.Month()), prices)
+		if err == nil {
+			lastClose = prices[tradingDay.Format("20060102")].Close
+		}
+		if lastClose.IsZero() {
+			return nil, fmt.Errorf(T("基准 %s 缺少 %s 的价格数据"), config.Benchmark, report.Date.Format("2006-01"))
+		}
+		if baseClose.IsZero() {
+			baseClose = lastClose
+		}
+
+		value := initialCapital.Mul(lastClose).Div(baseClose)
+		benchmarkReport := &MonthlyReport{
+			Date:             report.Date,
+			TotalValue:       value,
+			StockValue:       value,
+			CumulativeReturn: value.Div(initialCapital).Sub(decimal.NewFromInt(1)),
+		}
+		if lastValue.IsPositive() {
+			benchmarkReport.MonthlyReturn = value.Div(lastValue).Sub(decimal.NewFromInt(1))
+		}
+		curve.Reports = append(curve.Reports, benchmarkReport)
+		lastValue = value
+	}
+
+	return curve, nil
+}
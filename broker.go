@@ -0,0 +1,610 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 券商订单文件格式
+const (
+	BrokerFormatIBBasket   = "ib-basket"   // Interactive Brokers TWS BasketTrader CSV
+	BrokerFormatFIX42      = "fix42"       // FIX 4.2 NewOrderSingle（35=D）消息，每行一条
+	BrokerFormatAlpacaJSON = "alpaca-json" // Alpaca 下单接口请求体组成的 JSON 数组
+)
+
+// 订单方向
+const (
+	BrokerSideBuy       = "buy"
+	BrokerSideSell      = "sell"
+	BrokerSideSellShort = "sell_short"
+)
+
+// 开平仓方向
+const (
+	BrokerPositionOpen  = "open"  // 开仓：买入、卖空
+	BrokerPositionClose = "close" // 平仓：卖出、回补
+)
+
+// 订单类型
+const (
+	BrokerOrderMarket = "market"
+	BrokerOrderLimit  = "limit"
+)
+
+// 订单有效期
+const (
+	BrokerTIFDay = "day"
+	BrokerTIFGTC = "gtc"
+)
+
+// BrokerOrder 导出到券商的订单，各格式的解析器也返回该结构
+type BrokerOrder struct {
+	ClientOrderID  string          // 客户订单号
+	Symbol         string          // 股票代码
+	Side           string          // 方向：buy、sell 或 sell_short
+	PositionEffect string          // 开平仓：open 或 close，回补空头为 buy + close
+	Quantity       decimal.Decimal // 股数
+	OrderType      string          // 订单类型：market 或 limit
+	LimitPrice     decimal.Decimal // 限价，市价单为 0
+	TimeInForce    string          // 有效期：day 或 gtc
+}
+
+// BrokerOptions 生成券商订单的参数
+type BrokerOptions struct {
+	OrderType    string // 订单类型，为空时为 market
+	TimeInForce  string // 有效期，为空时为 day
+	Account      string // 账户，写入 IB 篮子和 FIX 消息，为空时不写
+	OrderPrefix  string // 客户订单号前缀，为空时为 tt
+	SenderCompID string // FIX SenderCompID，为空时为 TECHTITANS
+	TargetCompID string // FIX TargetCompID，为空时为 BROKER
+}
+
+// NewBrokerOrders 将交易转换为券商订单：BUY 为买入开仓，COVER 为买入平仓，SELL 为卖出平仓，SHORT 为卖空开仓；
+// 限价单以交易中的（估算）价格保留两位小数作为限价，客户订单号为 前缀-交易日-序号
+func NewBrokerOrders(actions []TradingAction, options BrokerOptions) ([]BrokerOrder, error) {
+	orderType := options.OrderType
+	if orderType == "" {
+		orderType = BrokerOrderMarket
+	}
+	if orderType != BrokerOrderMarket && orderType != BrokerOrderLimit {
+		return nil, fmt.Errorf(T("不支持的订单类型: %s"), orderType)
+	}
+	tif := options.TimeInForce
+	if tif == "" {
+		tif = BrokerTIFDay
+	}
+	if tif != BrokerTIFDay && tif != BrokerTIFGTC {
+		return nil, fmt.Errorf(T("不支持的订单有效期: %s"), tif)
+	}
+	prefix := options.OrderPrefix
+	if prefix == "" {
+		prefix = "tt"
+	}
+
+	orders := make([]BrokerOrder, 0, len(actions))
+	for i, action := range actions {
+		order := BrokerOrder{
+			ClientOrderID: fmt.Sprintf("%s-%s-%03d", prefix, action.Date.Format("20060102"), i+1),
+			Symbol:        action.Symbol,
+			Quantity:      action.Shares,
+			OrderType:     orderType,
+			TimeInForce:   tif,
+		}
+		switch action.Action {
+		case "BUY":
+			order.Side, order.PositionEffect = BrokerSideBuy, BrokerPositionOpen
+		case "COVER":
+			order.Side, order.PositionEffect = BrokerSideBuy, BrokerPositionClose
+		case "SELL":
+			order.Side, order.PositionEffect = BrokerSideSell, BrokerPositionClose
+		case "SHORT":
+			order.Side, order.PositionEffect = BrokerSideSellShort, BrokerPositionOpen
+		default:
+			return nil, fmt.Errorf(T("未知的交易方向: %s"), action.Action)
+		}
+		if orderType == BrokerOrderLimit {
+			order.LimitPrice = action.Price.Round(2)
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// BrokerExporter 券商订单文件的写入、校验和解析
+type BrokerExporter interface {
+	// Validate 检查订单能否以该格式提交
+	Validate(orders []BrokerOrder) error
+	// Write 写入订单文件
+	Write(w io.Writer, orders []BrokerOrder) error
+	// Parse 解析订单文件，用于回读检查
+	Parse(r io.Reader) ([]BrokerOrder, error)
+}
+
+// NewBrokerExporter 根据格式创建券商订单导出器
+func NewBrokerExporter(format string, options BrokerOptions) (BrokerExporter, error) {
+	switch format {
+	case BrokerFormatIBBasket:
+		return &ibBasketExporter{account: options.Account}, nil
+	case BrokerFormatFIX42:
+		sender, target := options.SenderCompID, options.TargetCompID
+		if sender == "" {
+			sender = "TECHTITANS"
+		}
+		if target == "" {
+			target = "BROKER"
+		}
+		return &fixExporter{sender: sender, target: target, account: options.Account, now: time.Now}, nil
+	case BrokerFormatAlpacaJSON:
+		return &alpacaExporter{}, nil
+	default:
+		return nil, fmt.Errorf(T("不支持的券商订单格式: %s"), format)
+	}
+}
+
+// IsBrokerFormat 判断是否为券商订单文件格式
+func IsBrokerFormat(format string) bool {
+	return format == BrokerFormatIBBasket || format == BrokerFormatFIX42 || format == BrokerFormatAlpacaJSON
+}
+
+// ExportBrokerOrders 校验并写入订单，写入前先在内存中回读，确认解析结果与订单一致
+func ExportBrokerOrders(w io.Writer, exporter BrokerExporter, orders []BrokerOrder) error {
+	if err := exporter.Validate(orders); err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if err := exporter.Write(&buffer, orders); err != nil {
+		return err
+	}
+	parsed, err := exporter.Parse(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		return fmt.Errorf(T("回读订单文件失败: %v"), err)
+	}
+	if err := CompareBrokerOrders(orders, parsed); err != nil {
+		return fmt.Errorf(T("回读订单文件失败: %v"), err)
+	}
+	_, err = w.Write(buffer.Bytes())
+	return err
+}
+
+// CompareBrokerOrders 比较两组订单是否一致，返回第一处差异
+func CompareBrokerOrders(expected, actual []BrokerOrder) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf(T("订单数量不一致: %d != %d"), len(expected), len(actual))
+	}
+	for i := range expected {
+		a, b := expected[i], actual[i]
+		if a.ClientOrderID != b.ClientOrderID || a.Symbol != b.Symbol || a.Side != b.Side || a.PositionEffect != b.PositionEffect ||
+			!a.Quantity.Equal(b.Quantity) || a.OrderType != b.OrderType ||
+			!a.LimitPrice.Equal(b.LimitPrice) || a.TimeInForce != b.TimeInForce {
+			return fmt.Errorf(T("第 %d 个订单不一致: %+v != %+v"), i+1, a, b)
+		}
+	}
+	return nil
+}
+
+// validateBrokerOrder 各格式共同的检查：代码、方向、开平仓、股数、订单类型、限价和有效期
+func validateBrokerOrder(order BrokerOrder) error {
+	if strings.TrimSpace(order.Symbol) == "" {
+		return fmt.Errorf(T("订单 %s 缺少股票代码"), order.ClientOrderID)
+	}
+	if order.ClientOrderID == "" {
+		return fmt.Errorf(T("订单 %s 缺少客户订单号"), order.Symbol)
+	}
+	switch order.Side {
+	case BrokerSideBuy, BrokerSideSell, BrokerSideSellShort:
+	default:
+		return fmt.Errorf(T("订单 %s 的方向无效: %s"), order.ClientOrderID, order.Side)
+	}
+	// 卖出只能平仓、卖空只能开仓，买入可以开仓或回补空头
+	switch {
+	case order.PositionEffect != BrokerPositionOpen && order.PositionEffect != BrokerPositionClose,
+		order.Side == BrokerSideSell && order.PositionEffect != BrokerPositionClose,
+		order.Side == BrokerSideSellShort && order.PositionEffect != BrokerPositionOpen:
+		return fmt.Errorf(T("订单 %s 的开平仓方向无效: %s %s"), order.ClientOrderID, order.Side, order.PositionEffect)
+	}
+	if !order.Quantity.IsPositive() {
+		return fmt.Errorf(T("订单 %s 的股数必须大于 0"), order.ClientOrderID)
+	}
+	switch order.OrderType {
+	case BrokerOrderMarket:
+		if !order.LimitPrice.IsZero() {
+			return fmt.Errorf(T("市价单 %s 不能设置限价"), order.ClientOrderID)
+		}
+	case BrokerOrderLimit:
+		if !order.LimitPrice.IsPositive() {
+			return fmt.Errorf(T("限价单 %s 的限价必须大于 0"), order.ClientOrderID)
+		}
+	default:
+		return fmt.Errorf(T("订单 %s 的类型无效: %s"), order.ClientOrderID, order.OrderType)
+	}
+	if order.TimeInForce != BrokerTIFDay && order.TimeInForce != BrokerTIFGTC {
+		return fmt.Errorf(T("订单 %s 的有效期无效: %s"), order.ClientOrderID, order.TimeInForce)
+	}
+	return nil
+}
+
+// ibBasketExporter Interactive Brokers TWS BasketTrader CSV
+type ibBasketExporter struct {
+	account string
+}
+
+// ibBasketHeader IB 篮子文件的列
+var ibBasketHeader = []string{"Action", "Quantity", "Symbol", "SecType", "Exchange", "Currency",
+	"TimeInForce", "OrderType", "LmtPrice", "OpenClose", "Account", "OrderRef"}
+
+// ibOpenClose IB 篮子 OpenClose 列的取值
+var ibOpenClose = map[string]string{BrokerPositionOpen: "O", BrokerPositionClose: "C"}
+
+// Validate 实现 BrokerExporter：IB 篮子只接受整数股
+func (exporter *ibBasketExporter) Validate(orders []BrokerOrder) error {
+	for _, order := range orders {
+		if err := validateBrokerOrder(order); err != nil {
+			return err
+		}
+		if !order.Quantity.Equal(order.Quantity.Truncate(0)) {
+			return fmt.Errorf(T("IB 篮子订单 %s 的股数必须为整数: %s"), order.ClientOrderID, order.Quantity)
+		}
+	}
+	return nil
+}
+
+// Write 实现 BrokerExporter
+func (exporter *ibBasketExporter) Write(w io.Writer, orders []BrokerOrder) error {
+	sides := map[string]string{BrokerSideBuy: "BUY", BrokerSideSell: "SELL", BrokerSideSellShort: "SSHORT"}
+	types := map[string]string{BrokerOrderMarket: "MKT", BrokerOrderLimit: "LMT"}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ibBasketHeader); err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+	for _, order := range orders {
+		limit := ""
+		if order.OrderType == BrokerOrderLimit {
+			limit = order.LimitPrice.String()
+		}
+		err := writer.Write([]string{
+			sides[order.Side], order.Quantity.String(), order.Symbol, "STK", "SMART", "USD",
+			strings.ToUpper(order.TimeInForce), types[order.OrderType], limit, ibOpenClose[order.PositionEffect],
+			exporter.account, order.ClientOrderID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Parse 实现 BrokerExporter
+func (exporter *ibBasketExporter) Parse(r io.Reader) ([]BrokerOrder, error) {
+	sides := map[string]string{"BUY": BrokerSideBuy, "SELL": BrokerSideSell, "SSHORT": BrokerSideSellShort}
+	types := map[string]string{"MKT": BrokerOrderMarket, "LMT": BrokerOrderLimit}
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New(T("缺少标题行"))
+	}
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"Action", "Quantity", "Symbol", "TimeInForce", "OrderType", "LmtPrice", "OpenClose", "OrderRef"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf(T("缺少 %s 列"), name)
+		}
+	}
+
+	var orders []BrokerOrder
+	for line, record := range records[1:] {
+		field := func(name string) string { return strings.TrimSpace(record[columns[name]]) }
+		order := BrokerOrder{
+			ClientOrderID: field("OrderRef"),
+			Symbol:        field("Symbol"),
+			Side:          sides[field("Action")],
+			OrderType:     types[field("OrderType")],
+			TimeInForce:   strings.ToLower(field("TimeInForce")),
+		}
+		if order.Side == "" {
+			return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line+2, "Action", field("Action"))
+		}
+		for effect, code := range ibOpenClose {
+			if field("OpenClose") == code {
+				order.PositionEffect = effect
+			}
+		}
+		if order.PositionEffect == "" {
+			return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line+2, "OpenClose", field("OpenClose"))
+		}
+		if order.OrderType == "" {
+			return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line+2, "OrderType", field("OrderType"))
+		}
+		if order.Quantity, err = decimal.NewFromString(field("Quantity")); err != nil {
+			return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line+2, "Quantity", field("Quantity"))
+		}
+		if limit := field("LmtPrice"); limit != "" {
+			if order.LimitPrice, err = decimal.NewFromString(limit); err != nil {
+				return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line+2, "LmtPrice", limit)
+			}
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// fixSOH FIX 字段分隔符
+const fixSOH = "\x01"
+
+// fixExporter FIX 4.2 NewOrderSingle 消息文件，每行一条消息，字段以 SOH 分隔；
+// 开平仓写入 OpenClose（77=O/C，即 FIX 4.3 起的 PositionEffect），回补空头为 54=1、77=C
+type fixExporter struct {
+	sender  string
+	target  string
+	account string
+	now     func() time.Time
+}
+
+// Validate 实现 BrokerExporter：字段值不能包含分隔符
+func (exporter *fixExporter) Validate(orders []BrokerOrder) error {
+	for _, order := range orders {
+		if err := validateBrokerOrder(order); err != nil {
+			return err
+		}
+		for _, value := range []string{order.ClientOrderID, order.Symbol, exporter.account, exporter.sender, exporter.target} {
+			if strings.ContainsAny(value, fixSOH+"|=\n") {
+				return fmt.Errorf(T("FIX 订单 %s 的字段包含非法字符: %q"), order.ClientOrderID, value)
+			}
+		}
+	}
+	return nil
+}
+
+// Write 实现 BrokerExporter
+func (exporter *fixExporter) Write(w io.Writer, orders []BrokerOrder) error {
+	sides := map[string]string{BrokerSideBuy: "1", BrokerSideSell: "2", BrokerSideSellShort: "5"}
+	effects := map[string]string{BrokerPositionOpen: "O", BrokerPositionClose: "C"}
+	types := map[string]string{BrokerOrderMarket: "1", BrokerOrderLimit: "2"}
+	tifs := map[string]string{BrokerTIFDay: "0", BrokerTIFGTC: "1"}
+	timestamp := exporter.now().UTC().Format("20060102-15:04:05")
+
+	for i, order := range orders {
+		fields := [][2]string{
+			{"35", "D"}, {"49", exporter.sender}, {"56", exporter.target}, {"34", strconv.Itoa(i + 1)},
+			{"52", timestamp}, {"11", order.ClientOrderID}, {"21", "1"}, {"55", order.Symbol},
+			{"54", sides[order.Side]}, {"77", effects[order.PositionEffect]}, {"60", timestamp}, {"38", order.Quantity.String()},
+			{"40", types[order.OrderType]},
+		}
+		if order.OrderType == BrokerOrderLimit {
+			fields = append(fields, [2]string{"44", order.LimitPrice.String()})
+		}
+		fields = append(fields, [2]string{"59", tifs[order.TimeInForce]})
+		if exporter.account != "" {
+			fields = append(fields, [2]string{"1", exporter.account})
+		}
+
+		var body strings.Builder
+		for _, field := range fields {
+			body.WriteString(field[0] + "=" + field[1] + fixSOH)
+		}
+		message := "8=FIX.4.2" + fixSOH + "9=" + strconv.Itoa(body.Len()) + fixSOH + body.String()
+		message += "10=" + fixChecksum(message) + fixSOH
+		if _, err := io.WriteString(w, message+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixChecksum FIX 校验和：CheckSum 字段之前所有字节之和对 256 取模，三位数字
+func fixChecksum(message string) string {
+	sum := 0
+	for i := 0; i < len(message); i++ {
+		sum += int(message[i])
+	}
+	return fmt.Sprintf("%03d", sum%256)
+}
+
+// Parse 实现 BrokerExporter：检查 BeginString、BodyLength、MsgType 和 CheckSum，
+// 字段分隔符可以是 SOH 或便于阅读的 |
+func (exporter *fixExporter) Parse(r io.Reader) ([]BrokerOrder, error) {
+	sides := map[string]string{"1": BrokerSideBuy, "2": BrokerSideSell, "5": BrokerSideSellShort}
+	effects := map[string]string{"O": BrokerPositionOpen, "C": BrokerPositionClose}
+	types := map[string]string{"1": BrokerOrderMarket, "2": BrokerOrderLimit}
+	tifs := map[string]string{"0": BrokerTIFDay, "1": BrokerTIFGTC}
+
+	var orders []BrokerOrder
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		message := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(message) == "" {
+			continue
+		}
+		if !strings.Contains(message, fixSOH) {
+			message = strings.ReplaceAll(message, "|", fixSOH)
+		}
+
+		end := strings.LastIndex(strings.TrimSuffix(message, fixSOH), fixSOH+"10=")
+		if end < 0 || !strings.HasPrefix(message, "8=FIX.4.2"+fixSOH+"9=") {
+			return nil, fmt.Errorf(T("第 %d 行不是 FIX 4.2 消息"), line)
+		}
+		end += len(fixSOH)
+		checksum := strings.TrimSuffix(message[end+len("10="):], fixSOH)
+		if checksum != fixChecksum(message[:end]) {
+			return nil, fmt.Errorf(T("第 %d 行的 CheckSum 不正确: %s"), line, checksum)
+		}
+
+		fields := make(map[string]string)
+		for _, field := range strings.Split(strings.TrimSuffix(message[:end], fixSOH), fixSOH) {
+			tag, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf(T("第 %d 行的字段无效: %s"), line, field)
+			}
+			fields[tag] = value
+		}
+		bodyStart := len("8=FIX.4.2"+fixSOH+"9=") + len(fields["9"]) + len(fixSOH)
+		if strconv.Itoa(end-bodyStart) != fields["9"] {
+			return nil, fmt.Errorf(T("第 %d 行的 BodyLength 不正确: %s"), line, fields["9"])
+		}
+		if fields["35"] != "D" {
+			return nil, fmt.Errorf(T("第 %d 行不是 NewOrderSingle 消息: 35=%s"), line, fields["35"])
+		}
+
+		order := BrokerOrder{
+			ClientOrderID:  fields["11"],
+			Symbol:         fields["55"],
+			Side:           sides[fields["54"]],
+			PositionEffect: effects[fields["77"]],
+			OrderType:      types[fields["40"]],
+			TimeInForce:    tifs[fields["59"]],
+		}
+		if order.Side == "" || order.PositionEffect == "" || order.OrderType == "" || order.TimeInForce == "" {
+			return nil, fmt.Errorf(T("第 %d 行的 Side、OpenClose、OrdType 或 TimeInForce 无效"), line)
+		}
+		var err error
+		if order.Quantity, err = decimal.NewFromString(fields["38"]); err != nil {
+			return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line, "OrderQty", fields["38"])
+		}
+		if price, ok := fields["44"]; ok {
+			if order.LimitPrice, err = decimal.NewFromString(price); err != nil {
+				return nil, fmt.Errorf(T("第 %d 行的 %s 无效: %s"), line, "Price", price)
+			}
+		}
+		orders = append(orders, order)
+	}
+	return orders, scanner.Err()
+}
+
+// alpacaExporter Alpaca 下单接口（POST /v2/orders）请求体组成的 JSON 数组
+type alpacaExporter struct{}
+
+// alpacaOrder Alpaca 下单请求体，数量和价格为字符串
+type alpacaOrder struct {
+	Symbol         string `json:"symbol"`
+	Qty            string `json:"qty"`
+	Side           string `json:"side"`
+	Type           string `json:"type"`
+	TimeInForce    string `json:"time_in_force"`
+	LimitPrice     string `json:"limit_price,omitempty"`
+	ClientOrderID  string `json:"client_order_id"`
+	PositionIntent string `json:"position_intent"`
+}
+
+// Validate 实现 BrokerExporter：碎股只能是当日有效的市价单，客户订单号不超过 128 个字符
+func (exporter *alpacaExporter) Validate(orders []BrokerOrder) error {
+	for _, order := range orders {
+		if err := validateBrokerOrder(order); err != nil {
+			return err
+		}
+		fractional := !order.Quantity.Equal(order.Quantity.Truncate(0))
+		if fractional && (order.OrderType != BrokerOrderMarket || order.TimeInForce != BrokerTIFDay) {
+			return fmt.Errorf(T("Alpaca 碎股订单 %s 只能是当日有效的市价单"), order.ClientOrderID)
+		}
+		if fractional && order.Side == BrokerSideSellShort {
+			return fmt.Errorf(T("Alpaca 不支持碎股卖空: %s"), order.ClientOrderID)
+		}
+		if len(order.ClientOrderID) > 128 {
+			return fmt.Errorf(T("Alpaca 客户订单号超过 128 个字符: %s"), order.ClientOrderID)
+		}
+	}
+	return nil
+}
+
+// alpacaIntents 方向和开平仓对应的 Alpaca side 与 position_intent
+var alpacaIntents = map[[2]string][2]string{
+	{BrokerSideBuy, BrokerPositionOpen}:       {BrokerSideBuy, "buy_to_open"},
+	{BrokerSideBuy, BrokerPositionClose}:      {BrokerSideBuy, "buy_to_close"},
+	{BrokerSideSell, BrokerPositionClose}:     {BrokerSideSell, "sell_to_close"},
+	{BrokerSideSellShort, BrokerPositionOpen}: {BrokerSideSell, "sell_to_open"},
+}
+
+// Write 实现 BrokerExporter：卖空以 sell + sell_to_open 表示，回补空头以 buy + buy_to_close 表示
+func (exporter *alpacaExporter) Write(w io.Writer, orders []BrokerOrder) error {
+	payload := make([]alpacaOrder, 0, len(orders))
+	for _, order := range orders {
+		intent := alpacaIntents[[2]string{order.Side, order.PositionEffect}]
+		item := alpacaOrder{
+			Symbol:         order.Symbol,
+			Qty:            order.Quantity.String(),
+			Side:           intent[0],
+			Type:           order.OrderType,
+			TimeInForce:    order.TimeInForce,
+			ClientOrderID:  order.ClientOrderID,
+			PositionIntent: intent[1],
+		}
+		if order.OrderType == BrokerOrderLimit {
+			item.LimitPrice = order.LimitPrice.String()
+		}
+		payload = append(payload, item)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+// Parse 实现 BrokerExporter：side 和 position_intent 必须是 Write 输出的组合之一
+func (exporter *alpacaExporter) Parse(r io.Reader) ([]BrokerOrder, error) {
+	var payload []alpacaOrder
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	orders := make([]BrokerOrder, 0, len(payload))
+	for i, item := range payload {
+		order := BrokerOrder{
+			ClientOrderID: item.ClientOrderID,
+			Symbol:        item.Symbol,
+			OrderType:     item.Type,
+			TimeInForce:   item.TimeInForce,
+		}
+		for key, intent := range alpacaIntents {
+			if intent == [2]string{item.Side, item.PositionIntent} {
+				order.Side, order.PositionEffect = key[0], key[1]
+			}
+		}
+		if order.Side == "" {
+			return nil, fmt.Errorf(T("第 %d 个订单的 %s 无效: %s"), i+1, "position_intent", item.Side+" "+item.PositionIntent)
+		}
+		var err error
+		if order.Quantity, err = decimal.NewFromString(item.Qty); err != nil {
+			return nil, fmt.Errorf(T("第 %d 个订单的 %s 无效: %s"), i+1, "qty", item.Qty)
+		}
+		if item.LimitPrice != "" {
+			if order.LimitPrice, err = decimal.NewFromString(item.LimitPrice); err != nil {
+				return nil, fmt.Errorf(T("第 %d 个订单的 %s 无效: %s"), i+1, "limit_price", item.LimitPrice)
+			}
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// PrintBrokerOrders 以表格输出券商订单
+func PrintBrokerOrders(w io.Writer, orders []BrokerOrder) {
+	fmt.Fprintf(w, "%-20s %-8s %-10s %-6s %12s %-8s %10s %-4s\n",
+		T("Order ID"), T("Symbol"), T("Side"), T("Open/Close"), T("Shares"), T("Type"), T("Limit"), T("TIF"))
+	for _, order := range orders {
+		limit := "-"
+		if order.OrderType == BrokerOrderLimit {
+			limit = order.LimitPrice.String()
+		}
+		fmt.Fprintf(w, "%-20s %-8s %-10s %-6s %12s %-8s %10s %-4s\n",
+			order.ClientOrderID, order.Symbol, order.Side, order.PositionEffect, order.Quantity.String(), order.OrderType, limit, order.TimeInForce)
+	}
+}
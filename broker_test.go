@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// brokerTestAction 构造一笔交易
+func brokerTestAction(action, symbol, shares, price string) TradingAction {
+	return TradingAction{
+		Date:   time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC),
+		Symbol: symbol,
+		Action: action,
+		Shares: decimal.RequireFromString(shares),
+		Price:  decimal.RequireFromString(price),
+		Amount: decimal.RequireFromString(shares).Mul(decimal.RequireFromString(price)),
+	}
+}
+
+// TestBrokerOrdersRoundTrip 各格式写入后解析回来的订单与原订单一致
+func TestBrokerOrdersRoundTrip(t *testing.T) {
+	formats := []string{BrokerFormatIBBasket, BrokerFormatFIX42, BrokerFormatAlpacaJSON}
+	tests := []struct {
+		name    string
+		action  TradingAction
+		options BrokerOptions
+		side    string
+		effect  string
+		invalid map[string]bool // Validate 应当拒绝的格式
+	}{
+		{name: "buy", action: brokerTestAction("BUY", "AAPL", "10", "230.15"),
+			side: BrokerSideBuy, effect: BrokerPositionOpen},
+		{name: "sell", action: brokerTestAction("SELL", "MSFT", "5", "505.12"),
+			side: BrokerSideSell, effect: BrokerPositionClose},
+		{name: "short", action: brokerTestAction("SHORT", "INTC", "40", "24.49"),
+			side: BrokerSideSellShort, effect: BrokerPositionOpen},
+		{name: "cover", action: brokerTestAction("COVER", "INTC", "40", "24.49"),
+			side: BrokerSideBuy, effect: BrokerPositionClose},
+		{name: "fractional buy", action: brokerTestAction("BUY", "NVDA", "1.2345", "170.62"),
+			side: BrokerSideBuy, effect: BrokerPositionOpen,
+			invalid: map[string]bool{BrokerFormatIBBasket: true}},
+		{name: "limit sell gtc", action: brokerTestAction("SELL", "AMD", "12", "162.634"),
+			options: BrokerOptions{OrderType: BrokerOrderLimit, TimeInForce: BrokerTIFGTC, Account: "U1234567"},
+			side:    BrokerSideSell, effect: BrokerPositionClose},
+		{name: "limit cover", action: brokerTestAction("COVER", "TSLA", "3", "333.87"),
+			options: BrokerOptions{OrderType: BrokerOrderLimit},
+			side:    BrokerSideBuy, effect: BrokerPositionClose},
+		{name: "fractional limit buy", action: brokerTestAction("BUY", "NVDA", "0.5", "170.62"),
+			options: BrokerOptions{OrderType: BrokerOrderLimit},
+			side:    BrokerSideBuy, effect: BrokerPositionOpen,
+			invalid: map[string]bool{BrokerFormatIBBasket: true, BrokerFormatAlpacaJSON: true}},
+	}
+
+	for _, test := range tests {
+		for _, format := range formats {
+			t.Run(test.name+"/"+format, func(t *testing.T) {
+				orders, err := NewBrokerOrders([]TradingAction{test.action}, test.options)
+				if err != nil {
+					t.Fatalf("NewBrokerOrders: %v", err)
+				}
+				if orders[0].Side != test.side || orders[0].PositionEffect != test.effect {
+					t.Fatalf("side = %s %s, want %s %s", orders[0].Side, orders[0].PositionEffect, test.side, test.effect)
+				}
+				if test.options.OrderType == BrokerOrderLimit && !orders[0].LimitPrice.Equal(test.action.Price.Round(2)) {
+					t.Fatalf("limit price = %s, want %s", orders[0].LimitPrice, test.action.Price.Round(2))
+				}
+
+				exporter, err := NewBrokerExporter(format, test.options)
+				if err != nil {
+					t.Fatalf("NewBrokerExporter: %v", err)
+				}
+				var buffer bytes.Buffer
+				err = ExportBrokerOrders(&buffer, exporter, orders)
+				if test.invalid[format] {
+					if err == nil {
+						t.Fatalf("ExportBrokerOrders accepted an order %s cannot take", format)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("ExportBrokerOrders: %v", err)
+				}
+
+				parsed, err := exporter.Parse(bytes.NewReader(buffer.Bytes()))
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				if err := CompareBrokerOrders(orders, parsed); err != nil {
+					t.Fatalf("CompareBrokerOrders: %v", err)
+				}
+			})
+		}
+	}
+}
+
+// TestBrokerOrdersCoverIntent 回补空头在各格式中写为买入平仓
+func TestBrokerOrdersCoverIntent(t *testing.T) {
+	orders, err := NewBrokerOrders([]TradingAction{brokerTestAction("COVER", "INTC", "40", "24.49")}, BrokerOptions{})
+	if err != nil {
+		t.Fatalf("NewBrokerOrders: %v", err)
+	}
+
+	tests := []struct {
+		format string
+		check  func(t *testing.T, output string)
+	}{
+		{BrokerFormatIBBasket, func(t *testing.T, output string) {
+			if !strings.Contains(output, "BUY,40,INTC,STK,SMART,USD,DAY,MKT,,C,") {
+				t.Errorf("IB basket cover row missing OpenClose=C:\n%s", output)
+			}
+		}},
+		{BrokerFormatFIX42, func(t *testing.T, output string) {
+			if !strings.Contains(output, fixSOH+"54=1"+fixSOH+"77=C"+fixSOH) {
+				t.Errorf("FIX cover message missing 54=1 and 77=C: %q", output)
+			}
+		}},
+		{BrokerFormatAlpacaJSON, func(t *testing.T, output string) {
+			var payload []alpacaOrder
+			if err := json.Unmarshal([]byte(output), &payload); err != nil {
+				t.Fatalf("json: %v", err)
+			}
+			if payload[0].Side != "buy" || payload[0].PositionIntent != "buy_to_close" {
+				t.Errorf("Alpaca cover = %s %s, want buy buy_to_close", payload[0].Side, payload[0].PositionIntent)
+			}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			exporter, err := NewBrokerExporter(test.format, BrokerOptions{})
+			if err != nil {
+				t.Fatalf("NewBrokerExporter: %v", err)
+			}
+			var buffer bytes.Buffer
+			if err := ExportBrokerOrders(&buffer, exporter, orders); err != nil {
+				t.Fatalf("ExportBrokerOrders: %v", err)
+			}
+			test.check(t, buffer.String())
+		})
+	}
+}
+
+// TestCompareBrokerOrdersPositionEffect 开平仓不同的订单视为不一致
+func TestCompareBrokerOrdersPositionEffect(t *testing.T) {
+	orders, err := NewBrokerOrders([]TradingAction{brokerTestAction("COVER", "INTC", "40", "24.49")}, BrokerOptions{})
+	if err != nil {
+		t.Fatalf("NewBrokerOrders: %v", err)
+	}
+	opened := append([]BrokerOrder(nil), orders...)
+	opened[0].PositionEffect = BrokerPositionOpen
+	if err := CompareBrokerOrders(orders, opened); err == nil {
+		t.Fatal("CompareBrokerOrders ignored the open/close effect")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import "time"
+
+// nyseSpecialClosures 纽交所因特殊事件休市的日期（YYYYMMDD），不含按规则计算的固定假期
+var nyseSpecialClosures = map[string]bool{
+	"20180105": true, // 老布什国葬日
+	"20250109": true, // 卡特国葬日
+}
+
+// IsNYSEHoliday 判断 date 是否为纽交所休市的工作日假期：
+// 元旦、马丁·路德·金纪念日、总统日、耶稣受难日、阵亡将士纪念日、六月节（2022 年起）、独立日、劳动节、感恩节、圣诞节，
+// 以及特殊休市日；节日逢周六提前到周五、逢周日顺延到周一（元旦逢周六不提前）
+func IsNYSEHoliday(date time.Time) bool {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if nyseSpecialClosures[date.Format("20060102")] {
+		return true
+	}
+
+	year := date.Year()
+	holidays := []time.Time{
+		observedHoliday(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		nthWeekday(year, time.January, time.Monday, 3),
+		nthWeekday(year, time.February, time.Monday, 3),
+		easterSunday(year).AddDate(0, 0, -2),
+		nthWeekday(year, time.June, time.Monday, 1).AddDate(0, 0, -7), // 5 月最后一个周一
+		observedHoliday(time.Date(year, time.July, 4, 0, 0, 0, 0, time.UTC)),
+		nthWeekday(year, time.September, time.Monday, 1),
+		nthWeekday(year, time.November, time.Thursday, 4),
+		observedHoliday(time.Date(year, time.December, 25, 0, 0, 0, 0, time.UTC)),
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observedHoliday(time.Date(year, time.June, 19, 0, 0, 0, 0, time.UTC)))
+	}
+	for _, holiday := range holidays {
+		// 元旦逢周六时提前到的上一年 12 月 31 日不在 year 年内，不会被匹配，该日照常交易
+		if holiday.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTradingDay 判断 date 是否为纽交所交易日（非周末且非假期）
+func IsTradingDay(date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	return !IsNYSEHoliday(date)
+}
+
+// NextTradingDay 返回 date 当天或之后的第一个纽交所交易日
+func NextTradingDay(date time.Time) time.Time {
+	for !IsTradingDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// observedHoliday 节日逢周六提前到周五、逢周日顺延到周一
+func observedHoliday(date time.Time) time.Time {
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday 返回 year 年 month 月的第 n 个 weekday
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	date := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(date.Weekday()) + 7) % 7
+	return date.AddDate(0, 0, offset+7*(n-1))
+}
+
+// easterSunday 按公历复活节算法（Meeus/Jones/Butcher）计算 year 年的复活节
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
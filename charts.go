@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/opts"
@@ -32,47 +37,73 @@ func NewChartGenerator(config *Config) *ChartGenerator {
 // GenerateAllCharts 生成所有图表
 func (cg *ChartGenerator) GenerateAllCharts(reports []*MonthlyReport) error {
 	if len(reports) == 0 {
-		return fmt.Errorf("no report data available")
+		return errors.New(T("no report data available"))
 	}
 
 	// 创建图表输出目录
 	chartDir := filepath.Join(cg.config.OutputDir, "charts")
 	err := os.MkdirAll(chartDir, 0755)
 	if err != nil {
-		return fmt.Errorf("failed to create chart directory: %v", err)
+		return fmt.Errorf(T("failed to create chart directory: %v"), err)
 	}
 
 	// 生成投资组合价值趋势图
 	err = cg.generatePortfolioValueChart(reports, chartDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate portfolio value chart: %v", err)
+		return fmt.Errorf(T("failed to generate portfolio value chart: %v"), err)
 	}
 
 	// 生成收益率趋势图
 	err = cg.generateReturnChart(reports, chartDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate return chart: %v", err)
+		return fmt.Errorf(T("failed to generate return chart: %v"), err)
+	}
+
+	// 生成回撤（水下）图
+	err = cg.generateUnderwaterChart(reports, chartDir)
+	if err != nil {
+		return fmt.Errorf(T("failed to generate underwater chart: %v"), err)
+	}
+
+	// 生成月度收益率热力图
+	err = cg.generateMonthlyReturnsHeatmap(reports, chartDir)
+	if err != nil {
+		return fmt.Errorf(T("failed to generate monthly returns heatmap: %v"), err)
+	}
+
+	// 生成收益贡献图
+	err = cg.generateAttributionChart(reports, chartDir)
+	if err != nil {
+		return fmt.Errorf(T("failed to generate attribution chart: %v"), err)
+	}
+
+	// 生成板块配置趋势图
+	if len(sortedSectors(reports)) > 0 {
+		err = cg.generateSectorAllocationChart(reports, chartDir)
+		if err != nil {
+			return fmt.Errorf(T("failed to generate sector allocation chart: %v"), err)
+		}
 	}
 
 	// 生成资产配置饼图
 	err = cg.generateAssetAllocationChart(reports[len(reports)-1], chartDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate asset allocation chart: %v", err)
+		return fmt.Errorf(T("failed to generate asset allocation chart: %v"), err)
 	}
 
 	// 生成持仓分布图
 	err = cg.generatePositionDistributionChart(reports[len(reports)-1], chartDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate position distribution chart: %v", err)
+		return fmt.Errorf(T("failed to generate position distribution chart: %v"), err)
 	}
 
 	// 生成月度交易活动图
 	err = cg.generateTradingActivityChart(reports, chartDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate trading activity chart: %v", err)
+		return fmt.Errorf(T("failed to generate trading activity chart: %v"), err)
 	}
 
-	fmt.Printf("All charts generated successfully in: %s\n", chartDir)
+	slog.Info(T("All charts generated"), "dir", chartDir)
 	return nil
 }
 
@@ -82,15 +113,15 @@ func (cg *ChartGenerator) generatePortfolioValueChart(reports []*MonthlyReport,
 	line.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Portfolio Value Trend",
-			Subtitle: "Monthly Portfolio Value Over Time",
+			Title:    T("Portfolio Value Trend"),
+			Subtitle: T("Monthly Portfolio Value Over Time"),
 		}),
 		charts.WithXAxisOpts(opts.XAxis{
-			Name: "Date",
+			Name: T("Date"),
 			Type: "category",
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
-			Name: "Value (USD)",
+			Name: T("Value (USD)"),
 			Type: "value",
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
@@ -114,17 +145,17 @@ func (cg *ChartGenerator) generatePortfolioValueChart(reports []*MonthlyReport,
 	}
 
 	line.SetXAxis(xAxis).
-		AddSeries("Total Value", totalValues).
-		AddSeries("Cash", cashValues).
-		AddSeries("Stock Value", stockValues).
+		AddSeries(T("Total Value"), totalValues, regimeMarkAreas(reports)...).
+		AddSeries(T("Cash"), cashValues).
+		AddSeries(T("Stock Value"), stockValues).
 		SetSeriesOptions(
 			charts.WithLineChartOpts(opts.LineChart{Smooth: boolPtr(true)}),
 			charts.WithMarkPointNameTypeItemOpts(opts.MarkPointNameTypeItem{
-				Name: "Maximum",
+				Name: T("Maximum"),
 				Type: "max",
 			}),
 			charts.WithMarkPointNameTypeItemOpts(opts.MarkPointNameTypeItem{
-				Name: "Minimum",
+				Name: T("Minimum"),
 				Type: "min",
 			}),
 		)
@@ -140,21 +171,100 @@ func (cg *ChartGenerator) generatePortfolioValueChart(reports []*MonthlyReport,
 	return line.Render(f)
 }
 
+// equityCurveChart 构建策略净值与基准净值对比图，benchmark 为空时只显示策略净值
+func (cg *ChartGenerator) equityCurveChart(reports []*MonthlyReport, benchmark *BenchmarkCurve) *charts.Line {
+	subtitle := "Portfolio Value"
+	if benchmark != nil {
+		subtitle = fmt.Sprintf(T("Portfolio Value vs %s (Scaled to Initial Capital)"), benchmark.Symbol)
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Equity Curve"),
+			Subtitle: subtitle,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Date"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("Value (USD)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true), Trigger: "axis"}),
+	)
+
+	// 准备数据
+	var xAxis []string
+	var strategyValues []opts.LineData
+	for _, report := range reports {
+		xAxis = append(xAxis, report.Date.Format("2006-01"))
+		valueFloat, _ := report.TotalValue.Round(2).Float64()
+		strategyValues = append(strategyValues, opts.LineData{Value: valueFloat})
+	}
+
+	line.SetXAxis(xAxis).
+		AddSeries(T("Strategy"), strategyValues, regimeMarkAreas(reports)...)
+
+	if benchmark != nil {
+		var benchmarkValues []opts.LineData
+		for _, report := range benchmark.Reports {
+			valueFloat, _ := report.TotalValue.Round(2).Float64()
+			benchmarkValues = append(benchmarkValues, opts.LineData{Value: valueFloat})
+		}
+		line.AddSeries(benchmark.Symbol, benchmarkValues)
+	}
+
+	return line
+}
+
+// regimeMarkAreas 将连续的 risk-off 月份标注为图表背景区域
+func regimeMarkAreas(reports []*MonthlyReport) []charts.SeriesOpts {
+	var areas [][]opts.MarkAreaData
+	for i := 0; i < len(reports); i++ {
+		if reports[i].Regime != RegimeRiskOff {
+			continue
+		}
+		start := i
+		for i+1 < len(reports) && reports[i+1].Regime == RegimeRiskOff {
+			i++
+		}
+		areas = append(areas, []opts.MarkAreaData{
+			{Name: T("Risk-Off"), XAxis: reports[start].Date.Format("2006-01")},
+			{XAxis: reports[i].Date.Format("2006-01")},
+		})
+	}
+
+	if len(areas) == 0 {
+		return nil
+	}
+
+	return []charts.SeriesOpts{
+		charts.WithMarkAreaData(areas...),
+		charts.WithMarkAreaStyleOpts(opts.MarkAreaStyle{
+			ItemStyle: &opts.ItemStyle{Color: "rgba(255, 99, 71, 0.15)"},
+		}),
+	}
+}
+
 // generateReturnChart 生成收益率趋势图
 func (cg *ChartGenerator) generateReturnChart(reports []*MonthlyReport, outputDir string) error {
 	line := charts.NewLine()
 	line.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Return Trend",
-			Subtitle: "Monthly and Cumulative Returns",
+			Title:    T("Return Trend"),
+			Subtitle: T("Monthly and Cumulative Returns"),
 		}),
 		charts.WithXAxisOpts(opts.XAxis{
-			Name: "Date",
+			Name: T("Date"),
 			Type: "category",
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
-			Name: "Return (%)",
+			Name: T("Return (%)"),
 			Type: "value",
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
@@ -179,8 +289,8 @@ func (cg *ChartGenerator) generateReturnChart(reports []*MonthlyReport, outputDi
 	}
 
 	line.SetXAxis(xAxis).
-		AddSeries("Monthly Return (%)", monthlyReturns).
-		AddSeries("Cumulative Return (%)", cumulativeReturns).
+		AddSeries(T("Monthly Return (%)"), monthlyReturns).
+		AddSeries(T("Cumulative Return (%)"), cumulativeReturns, regimeMarkAreas(reports)...).
 		SetSeriesOptions(
 			charts.WithLineChartOpts(opts.LineChart{Smooth: boolPtr(true)}),
 		)
@@ -196,14 +306,280 @@ func (cg *ChartGenerator) generateReturnChart(reports []*MonthlyReport, outputDi
 	return line.Render(f)
 }
 
+// generateUnderwaterChart 生成回撤（水下）面积图
+func (cg *ChartGenerator) generateUnderwaterChart(reports []*MonthlyReport, outputDir string) error {
+	line := cg.underwaterChart(reports)
+
+	// 保存图表
+	filePath := filepath.Join(outputDir, "underwater.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return line.Render(f)
+}
+
+// underwaterChart 构建回撤（水下）面积图
+func (cg *ChartGenerator) underwaterChart(reports []*MonthlyReport) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Underwater Chart"),
+			Subtitle: T("Drawdown from Running Peak (%)"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Date"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("Drawdown (%)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
+	)
+
+	// 准备数据
+	var xAxis []string
+	var drawdowns []opts.LineData
+
+	for i, drawdown := range drawdownSeries(reports) {
+		xAxis = append(xAxis, reports[i].Date.Format("2006-01"))
+		drawdownFloat, _ := drawdown.Mul(decimal.NewFromInt(100)).Round(2).Float64()
+		drawdowns = append(drawdowns, opts.LineData{Value: drawdownFloat})
+	}
+
+	line.SetXAxis(xAxis).
+		AddSeries(T("Drawdown (%)"), drawdowns).
+		SetSeriesOptions(
+			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opts.Float(0.4)}),
+			charts.WithMarkPointNameTypeItemOpts(opts.MarkPointNameTypeItem{
+				Name: T("Max Drawdown"),
+				Type: "min",
+			}),
+		)
+
+	return line
+}
+
+// generateMonthlyReturnsHeatmap 生成年 × 月的月度收益率热力图
+func (cg *ChartGenerator) generateMonthlyReturnsHeatmap(reports []*MonthlyReport, outputDir string) error {
+	heatmap := cg.monthlyReturnsHeatmap(reports)
+
+	// 保存图表
+	filePath := filepath.Join(outputDir, "monthly_returns_heatmap.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return heatmap.Render(f)
+}
+
+// monthlyReturnsHeatmap 构建年 × 月的月度收益率热力图
+func (cg *ChartGenerator) monthlyReturnsHeatmap(reports []*MonthlyReport) *charts.HeatMap {
+	grid := BuildMonthlyReturnGrid(reports)
+
+	// 准备数据：x 轴为月份及 YTD，y 轴为年份
+	var xAxis []string
+	for month := time.January; month <= time.December; month++ {
+		xAxis = append(xAxis, T(month.String()[:3]))
+	}
+	xAxis = append(xAxis, T("YTD"))
+
+	var yAxis []string
+	var items []opts.HeatMapData
+	maxAbs := 0.0
+	for y, year := range grid.Years {
+		yAxis = append(yAxis, strconv.Itoa(year))
+		for month := 1; month <= 12; month++ {
+			monthlyReturn, exists := grid.Returns[year][month]
+			if !exists {
+				continue
+			}
+			value, _ := monthlyReturn.Mul(decimal.NewFromInt(100)).Round(2).Float64()
+			maxAbs = math.Max(maxAbs, math.Abs(value))
+			items = append(items, opts.HeatMapData{Value: [3]interface{}{month - 1, y, value}})
+		}
+		ytd, _ := grid.YTD[year].Mul(decimal.NewFromInt(100)).Round(2).Float64()
+		items = append(items, opts.HeatMapData{Value: [3]interface{}{12, y, ytd}})
+	}
+
+	heatmap := charts.NewHeatMap()
+	heatmap.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Monthly Returns Heatmap"),
+			Subtitle: T("Monthly Return (%) by Year, with YTD"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name:      T("Month"),
+			Type:      "category",
+			SplitArea: &opts.SplitArea{Show: boolPtr(true)},
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name:      T("Year"),
+			Type:      "category",
+			Data:      yAxis,
+			SplitArea: &opts.SplitArea{Show: boolPtr(true)},
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: boolPtr(true),
+			Min:        float32(-maxAbs),
+			Max:        float32(maxAbs),
+			InRange: &opts.VisualMapInRange{
+				Color: []string{"#d73027", "#ffffff", "#1a9850"},
+			},
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
+	)
+
+	heatmap.SetXAxis(xAxis).
+		AddSeries(T("Monthly Return (%)"), items,
+			charts.WithLabelOpts(opts.Label{Show: boolPtr(true)}),
+		)
+
+	return heatmap
+}
+
+// generateAttributionChart 生成贡献最大和最小股票的累计收益贡献图
+func (cg *ChartGenerator) generateAttributionChart(reports []*MonthlyReport, outputDir string) error {
+	bar := cg.attributionChart(reports)
+
+	// 保存图表
+	filePath := filepath.Join(outputDir, "attribution.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return bar.Render(f)
+}
+
+// attributionChart 构建贡献最大和最小股票的累计收益贡献图
+func (cg *ChartGenerator) attributionChart(reports []*MonthlyReport) *charts.Bar {
+	result := AnalyzeAttribution(reports, decimal.NewFromFloat(cg.config.InitialCapital))
+
+	// 取贡献最大和最小的各10只股票
+	maxShow := 10
+	selected := result.Symbols
+	if len(selected) > maxShow*2 {
+		selected = append(append([]SymbolAttribution{}, selected[:maxShow]...), selected[len(selected)-maxShow:]...)
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Performance Attribution"),
+			Subtitle: T("Top and Bottom Contributors to Total Return"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Symbol"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("Contribution (%)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
+	)
+
+	// 准备数据
+	var xAxis []string
+	var contributions []opts.BarData
+
+	for _, attribution := range selected {
+		xAxis = append(xAxis, attribution.Symbol)
+		contributionFloat, _ := attribution.Contribution.Mul(decimal.NewFromInt(100)).Round(2).Float64()
+		color := "#1a9850"
+		if contributionFloat < 0 {
+			color = "#d73027"
+		}
+		contributions = append(contributions, opts.BarData{
+			Value:     contributionFloat,
+			ItemStyle: &opts.ItemStyle{Color: color},
+		})
+	}
+
+	bar.SetXAxis(xAxis).
+		AddSeries(T("Contribution (%)"), contributions)
+
+	return bar
+}
+
+// generateSectorAllocationChart 生成板块权重随时间变化的堆叠面积图
+func (cg *ChartGenerator) generateSectorAllocationChart(reports []*MonthlyReport, outputDir string) error {
+	line := cg.sectorAllocationChart(reports)
+
+	// 保存图表
+	filePath := filepath.Join(outputDir, "sector_allocation.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return line.Render(f)
+}
+
+// sectorAllocationChart 构建板块权重随时间变化的堆叠面积图
+func (cg *ChartGenerator) sectorAllocationChart(reports []*MonthlyReport) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Sector Allocation"),
+			Subtitle: T("Sector Weights Over Time (%)"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Date"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("Weight (%)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true), Trigger: "axis"}),
+	)
+
+	// 准备数据
+	var xAxis []string
+	for _, report := range reports {
+		xAxis = append(xAxis, report.Date.Format("2006-01"))
+	}
+	line.SetXAxis(xAxis)
+
+	for _, sector := range sortedSectors(reports) {
+		var weights []opts.LineData
+		for _, report := range reports {
+			weightFloat, _ := report.SectorWeights[sector].Mul(decimal.NewFromInt(100)).Round(2).Float64()
+			weights = append(weights, opts.LineData{Value: weightFloat})
+		}
+		line.AddSeries(sector, weights,
+			charts.WithLineChartOpts(opts.LineChart{Stack: "sector"}),
+			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opts.Float(0.6)}),
+		)
+	}
+
+	return line
+}
+
 // generateAssetAllocationChart 生成资产配置饼图
 func (cg *ChartGenerator) generateAssetAllocationChart(report *MonthlyReport, outputDir string) error {
 	pie := charts.NewPie()
 	pie.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Asset Allocation",
-			Subtitle: fmt.Sprintf("As of %s", report.Date.Format("2006-01-02")),
+			Title:    T("Asset Allocation"),
+			Subtitle: fmt.Sprintf(T("As of %s"), report.Date.Format("2006-01-02")),
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true), Orient: "vertical", Left: "left"}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
@@ -216,7 +592,7 @@ func (cg *ChartGenerator) generateAssetAllocationChart(report *MonthlyReport, ou
 	cashPercent := report.Cash.Div(report.TotalValue).Mul(decimal.NewFromInt(100))
 	cashPercentFloat, _ := cashPercent.Round(2).Float64()
 	items = append(items, opts.PieData{
-		Name:  "Cash",
+		Name:  T("Cash"),
 		Value: cashPercentFloat,
 	})
 
@@ -224,11 +600,11 @@ func (cg *ChartGenerator) generateAssetAllocationChart(report *MonthlyReport, ou
 	stockPercent := report.StockValue.Div(report.TotalValue).Mul(decimal.NewFromInt(100))
 	stockPercentFloat, _ := stockPercent.Round(2).Float64()
 	items = append(items, opts.PieData{
-		Name:  "Stocks",
+		Name:  T("Stocks"),
 		Value: stockPercentFloat,
 	})
 
-	pie.AddSeries("allocation", items).
+	pie.AddSeries(T("allocation"), items).
 		SetSeriesOptions(
 			charts.WithLabelOpts(opts.Label{
 				Show:      boolPtr(true),
@@ -249,19 +625,34 @@ func (cg *ChartGenerator) generateAssetAllocationChart(report *MonthlyReport, ou
 
 // generatePositionDistributionChart 生成持仓分布图
 func (cg *ChartGenerator) generatePositionDistributionChart(report *MonthlyReport, outputDir string) error {
+	bar := cg.positionDistributionChart(report)
+
+	// 保存图表
+	filePath := filepath.Join(outputDir, "position_distribution.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return bar.Render(f)
+}
+
+// positionDistributionChart 构建持仓分布图
+func (cg *ChartGenerator) positionDistributionChart(report *MonthlyReport) *charts.Bar {
 	bar := charts.NewBar()
 	bar.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Position Distribution",
-			Subtitle: "Top Holdings by Market Value",
+			Title:    T("Position Distribution"),
+			Subtitle: T("Top Holdings by Market Value"),
 		}),
 		charts.WithXAxisOpts(opts.XAxis{
-			Name: "Symbol",
+			Name: T("Symbol"),
 			Type: "category",
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
-			Name: "Market Value (USD)",
+			Name: T("Market Value (USD)"),
 			Type: "value",
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
@@ -298,18 +689,10 @@ func (cg *ChartGenerator) generatePositionDistributionChart(report *MonthlyRepor
 	}
 
 	bar.SetXAxis(xAxis).
-		AddSeries("Market Value", marketValues).
-		AddSeries("P&L", pnlValues)
+		AddSeries(T("Market Value"), marketValues).
+		AddSeries(T("P&L"), pnlValues)
 
-	// 保存图表
-	filePath := filepath.Join(outputDir, "position_distribution.html")
-	f, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return bar.Render(f)
+	return bar
 }
 
 // generateTradingActivityChart 生成月度交易活动图
@@ -318,15 +701,15 @@ func (cg *ChartGenerator) generateTradingActivityChart(reports []*MonthlyReport,
 	bar.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Monthly Trading Activity",
-			Subtitle: "Number of Buy and Sell Transactions",
+			Title:    T("Monthly Trading Activity"),
+			Subtitle: T("Number of Buy and Sell Transactions"),
 		}),
 		charts.WithXAxisOpts(opts.XAxis{
-			Name: "Date",
+			Name: T("Date"),
 			Type: "category",
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
-			Name: "Number of Transactions",
+			Name: T("Number of Transactions"),
 			Type: "value",
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
@@ -356,8 +739,8 @@ func (cg *ChartGenerator) generateTradingActivityChart(reports []*MonthlyReport,
 	}
 
 	bar.SetXAxis(xAxis).
-		AddSeries("Buy Transactions", buyActions).
-		AddSeries("Sell Transactions", sellActions)
+		AddSeries(T("Buy Transactions"), buyActions).
+		AddSeries(T("Sell Transactions"), sellActions)
 
 	// 保存图表
 	filePath := filepath.Join(outputDir, "trading_activity.html")
@@ -376,8 +759,8 @@ func (cg *ChartGenerator) GeneratePositionPieChart(report *MonthlyReport, output
 	pie.SetGlobalOptions(
 		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Position Weight Distribution",
-			Subtitle: fmt.Sprintf("As of %s", report.Date.Format("2006-01-02")),
+			Title:    T("Position Weight Distribution"),
+			Subtitle: fmt.Sprintf(T("As of %s"), report.Date.Format("2006-01-02")),
 		}),
 		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true), Orient: "vertical", Left: "left"}),
 		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
@@ -414,7 +797,7 @@ func (cg *ChartGenerator) GeneratePositionPieChart(report *MonthlyReport, output
 	if !otherWeight.IsZero() {
 		otherWeightFloat, _ := otherWeight.Mul(decimal.NewFromInt(100)).Round(2).Float64()
 		items = append(items, opts.PieData{
-			Name:  "Others",
+			Name:  T("Others"),
 			Value: otherWeightFloat,
 		})
 	}
@@ -423,11 +806,11 @@ func (cg *ChartGenerator) GeneratePositionPieChart(report *MonthlyReport, output
 	cashWeight := report.Cash.Div(report.TotalValue).Mul(decimal.NewFromInt(100))
 	cashWeightFloat, _ := cashWeight.Round(2).Float64()
 	items = append(items, opts.PieData{
-		Name:  "Cash",
+		Name:  T("Cash"),
 		Value: cashWeightFloat,
 	})
 
-	pie.AddSeries("Position Weight", items).
+	pie.AddSeries(T("Position Weight"), items).
 		SetSeriesOptions(
 			charts.WithLabelOpts(opts.Label{
 				Show:      boolPtr(true),
@@ -444,4 +827,63 @@ func (cg *ChartGenerator) GeneratePositionPieChart(report *MonthlyReport, output
 	defer f.Close()
 
 	return pie.Render(f)
-}
\ No newline at end of file
+}
+// GenerateEventStudyChart 生成纳入/剔除信号的平均累计超额收益率曲线
+func (cg *ChartGenerator) GenerateEventStudyChart(result *EventStudyResult) error {
+	chartDir := filepath.Join(cg.config.OutputDir, "charts")
+	err := os.MkdirAll(chartDir, 0755)
+	if err != nil {
+		return fmt.Errorf(T("failed to create chart directory: %v"), err)
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Cumulative Abnormal Return"),
+			Subtitle: fmt.Sprintf(T("Average Excess Return vs %s After Signal (%%)"), result.Benchmark),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Trading Days"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("CAR (%)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true)}),
+	)
+
+	// 准备数据
+	days := 0
+	for _, averages := range result.AverageCAR {
+		if len(averages) > days {
+			days = len(averages)
+		}
+	}
+	var xAxis []string
+	for day := 0; day < days; day++ {
+		xAxis = append(xAxis, strconv.Itoa(day))
+	}
+	line.SetXAxis(xAxis)
+
+	for _, status := range []string{"纳入", "剔除"} {
+		var data []opts.LineData
+		for _, value := range result.AverageCAR[status] {
+			data = append(data, opts.LineData{Value: math.Round(value*10000) / 100})
+		}
+		line.AddSeries(status, data)
+	}
+	line.SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(false)}))
+
+	// 保存图表
+	filePath := filepath.Join(chartDir, "cumulative_abnormal_return.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return line.Render(f)
+}
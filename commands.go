@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command 子命令
+type command struct {
+	name    string              // 子命令名称
+	args    string              // 位置参数说明
+	summary string              // 一行说明
+	run     func(args []string) // 执行函数，参数不含子命令名称
+}
+
+// commandTable 返回所有子命令，顺序即帮助信息中的顺序
+func commandTable() []command {
+	return []command{
+		{"run", "", "Run the backtest and write reports (default when no command is given)", runBacktest},
+		{"validate", "", "Check configuration, signals and price data without running the backtest", runValidate},
+		{"compare", "RUN_DIR[=LABEL] RUN_DIR[=LABEL] ...", "Diff saved runs: metrics, monthly values, unmatched trades and holdings", runCompare},
+		{"sweep", "", "Run the backtest over a grid of parameter values", runSweep},
+		{"report", "RUN_DIR", "Regenerate reports and charts from a saved run without re-running it", runReport},
+		{"orders", "[RUN_DIR]", "List the orders of a saved run's rebalance, or generate the next trading day's orders from current holdings (-positions)", runOrders},
+		{"serve", "[DIR]", "Serve run reports and charts over HTTP", runServe},
+		{"eventstudy", "", "Measure forward and excess returns after include/exclude signals", runEventStudy},
+		{"convert", "", "Convert stock price data between formats", runConvert},
+		{"merge", "NEW_EXPORT.csv ...", "Merge new Yahoo CSV exports into the stock price directory", runMerge},
+		{"import", "", "Import prices, indexes and signals into a SQLite database", runImport},
+		{"runs", "", "List runs saved in the database", runListRuns},
+		{"markdown", "RUN_ID[=LABEL] ...", "Write a Markdown summary or comparison of runs saved in the database", runMarkdown},
+		{"help", "[COMMAND]", "Show help for a command", runHelp},
+	}
+}
+
+// findCommand 按名称查找子命令
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commandTable() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// newCommandFlagSet 创建子命令的参数集，-h 时输出用法、说明和参数列表
+func newCommandFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		cmd, _ := findCommand(name)
+		out := fs.Output()
+		usage := "Usage: tech-titans " + cmd.name + " [flags]"
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+		fmt.Fprintf(out, "%s\n\n%s\n\nFlags:\n", usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// printUsage 输出子命令列表
+func printUsage(out io.Writer) {
+	fmt.Fprint(out, "Usage: tech-titans [COMMAND] [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commandTable() {
+		fmt.Fprintf(out, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprint(out, "\nRun 'tech-titans help COMMAND' or 'tech-titans COMMAND -h' for the flags of a command.\n")
+}
+
+// runHelp 执行 help 子命令：不带参数时列出子命令，否则输出指定子命令的参数
+func runHelp(args []string) {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok || cmd.name == "help" {
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
+	cmd.run([]string{"-h"})
+}
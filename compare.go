@@ -0,0 +1,594 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+	"github.com/shopspring/decimal"
+)
+
+// ComparedRun 参与对比的已保存运行
+type ComparedRun struct {
+	Label      string              // 显示名称
+	Dir        string              // 运行目录
+	Config     *Config             // 运行时的配置
+	Reports    []*MonthlyReport    // 月度报告
+	Metrics    *PerformanceMetrics // 绩效指标
+	FinalValue decimal.Decimal     // 期末总价值
+}
+
+// LoadComparedRun 读取 RUN_DIR[=LABEL] 形式的参数对应的运行，未指定名称时使用目录名
+// RUN_DIR 也可以是 run.json 文件，此时默认名称为所在目录名
+func LoadComparedRun(arg string) (*ComparedRun, error) {
+	dir, label, _ := strings.Cut(arg, "=")
+	results, err := LoadRunResults(dir)
+	if err != nil {
+		return nil, err
+	}
+	if label == "" {
+		label = filepath.Base(results.Config.OutputDir)
+	}
+
+	reports := results.Reports
+	return &ComparedRun{
+		Label:      label,
+		Dir:        dir,
+		Config:     results.Config,
+		Reports:    reports,
+		Metrics:    CalculatePerformanceMetrics(reports),
+		FinalValue: reports[len(reports)-1].TotalValue,
+	}, nil
+}
+
+// comparisonTable 多个运行的关键指标表，收益率和回撤差异以第一个运行为基准（百分点）
+func comparisonTable(runs []*ComparedRun) tearSheetTable {
+	table := tearSheetTable{
+		Title: T("Performance Metrics"),
+		Headers: []string{"Run", "Period", "Final Value", "Return %", "Annual %", "Max DD %",
+			"Sharpe", "Trades", "Δ Return", "Δ Max DD"},
+	}
+	if len(runs) == 0 {
+		return table
+	}
+	hundred := decimal.NewFromInt(100)
+	base := runs[0].Metrics
+
+	for _, run := range runs {
+		metrics := run.Metrics
+		period := run.Config.StartDate.Format("2006-01-02") + "~" + run.Config.EndDate.Format("2006-01-02")
+		table.Rows = append(table.Rows, []string{
+			run.Label, period, run.FinalValue.StringFixed(2),
+			metrics.TotalReturn.Mul(hundred).StringFixed(2),
+			metrics.AnnualizedReturn.Mul(hundred).StringFixed(2),
+			metrics.MaxDrawdown.Mul(hundred).StringFixed(2),
+			metrics.SharpeRatio.StringFixed(2), fmt.Sprintf("%d", metrics.TotalTrades),
+			signed(metrics.TotalReturn.Sub(base.TotalReturn).Mul(hundred).StringFixed(2)),
+			signed(metrics.MaxDrawdown.Sub(base.MaxDrawdown).Mul(hundred).StringFixed(2)),
+		})
+	}
+	return table
+}
+
+// PrintRunComparison 输出多个运行的关键指标，收益率和回撤差异以第一个运行为基准（百分点）
+func PrintRunComparison(w io.Writer, runs []*ComparedRun) {
+	if len(runs) == 0 {
+		return
+	}
+	table := comparisonTable(runs)
+	format := "%-20s %-23s %14s %10s %10s %10s %8s %8s %10s %10s\n"
+
+	headers := make([]any, 0, len(table.Headers))
+	for _, header := range table.Headers {
+		headers = append(headers, T(header))
+	}
+	fmt.Fprintf(w, format, headers...)
+	for _, row := range table.Rows {
+		values := make([]any, 0, len(row))
+		for _, value := range row {
+			values = append(values, value)
+		}
+		fmt.Fprintf(w, format, values...)
+	}
+}
+
+// MonthDiff 两个运行同一月份的月度报告，某个运行缺少该月时对应报告为 nil
+type MonthDiff struct {
+	Month string         // 月份 YYYY-MM
+	Base  *MonthlyReport // 基准运行的月度报告
+	Other *MonthlyReport // 对比运行的月度报告
+}
+
+// Changed 两个运行在该月的净值或收益率是否不同
+func (diff MonthDiff) Changed() bool {
+	if diff.Base == nil || diff.Other == nil {
+		return true
+	}
+	return !diff.Base.TotalValue.Round(2).Equal(diff.Other.TotalValue.Round(2)) ||
+		!diff.Base.MonthlyReturn.Round(6).Equal(diff.Other.MonthlyReturn.Round(6))
+}
+
+// HoldingDiff 同一股票在两个运行中的持仓，未持有时股数和权重为 0
+type HoldingDiff struct {
+	Symbol      string
+	BaseShares  decimal.Decimal
+	OtherShares decimal.Decimal
+	BaseWeight  decimal.Decimal
+	OtherWeight decimal.Decimal
+}
+
+// HoldingsDiff 指定月份两个运行中持股数不同的股票
+type HoldingsDiff struct {
+	Month string // 月份 YYYY-MM
+	Rows  []HoldingDiff
+}
+
+// TradeChange 两个运行中日期、代码和方向相同但股数或金额不同的交易
+type TradeChange struct {
+	Base  TradingAction
+	Other TradingAction
+}
+
+// RunDiff 两个运行的逐月净值、交易和持仓差异，差异均为 Other 相对 Base
+type RunDiff struct {
+	Base          *ComparedRun
+	Other         *ComparedRun
+	Months        []MonthDiff     // 两个运行出现过的所有月份，按时间排序
+	OnlyInBase    []TradingAction // 只出现在基准运行中的交易
+	OnlyInOther   []TradingAction // 只出现在对比运行中的交易
+	ChangedTrades []TradeChange   // 两边都有但股数或金额不同的交易
+	Holdings      []HoldingsDiff  // 指定月份的持仓差异
+}
+
+// NewRunDiff 对比两个运行；months 为比较持仓的月份（YYYY-MM），为空时取两个运行共有的最后一个月
+// 交易按日期、代码和方向匹配，同一键有多笔时按出现顺序配对；配对后股数或金额不同的记为变更交易
+func NewRunDiff(base, other *ComparedRun, months []string) (*RunDiff, error) {
+	diff := &RunDiff{Base: base, Other: other}
+
+	baseMonths := monthlyReportIndex(base.Reports)
+	otherMonths := monthlyReportIndex(other.Reports)
+	var monthList []string
+	for month := range baseMonths {
+		monthList = append(monthList, month)
+	}
+	for month := range otherMonths {
+		if baseMonths[month] == nil {
+			monthList = append(monthList, month)
+		}
+	}
+	sort.Strings(monthList)
+	for _, month := range monthList {
+		diff.Months = append(diff.Months, MonthDiff{Month: month, Base: baseMonths[month], Other: otherMonths[month]})
+	}
+
+	diff.OnlyInBase, diff.OnlyInOther, diff.ChangedTrades = matchTrades(base.Reports, other.Reports)
+
+	if len(months) == 0 {
+		for i := len(diff.Months) - 1; i >= 0; i-- {
+			if diff.Months[i].Base != nil && diff.Months[i].Other != nil {
+				months = []string{diff.Months[i].Month}
+				break
+			}
+		}
+	}
+	for _, month := range months {
+		if _, err := time.Parse("2006-01", month); err != nil {
+			return nil, fmt.Errorf(T("无效的月份: %s"), month)
+		}
+		baseReport, otherReport := baseMonths[month], otherMonths[month]
+		if baseReport == nil {
+			return nil, fmt.Errorf(T("%s 中没有 %s 的月度报告"), base.Label, month)
+		}
+		if otherReport == nil {
+			return nil, fmt.Errorf(T("%s 中没有 %s 的月度报告"), other.Label, month)
+		}
+		diff.Holdings = append(diff.Holdings, HoldingsDiff{Month: month, Rows: holdingDiffs(baseReport, otherReport)})
+	}
+	return diff, nil
+}
+
+// monthlyReportIndex 按月份（YYYY-MM）索引月度报告
+func monthlyReportIndex(reports []*MonthlyReport) map[string]*MonthlyReport {
+	index := make(map[string]*MonthlyReport, len(reports))
+	for _, report := range reports {
+		index[report.Date.Format("2006-01")] = report
+	}
+	return index
+}
+
+// tradeKey 匹配两个运行中同一笔交易使用的键，不含股数，调仓规模不同仍视为同一笔交易
+func tradeKey(action TradingAction) string {
+	return strings.Join([]string{action.Date.Format("2006-01-02"), action.Symbol, action.Action}, "|")
+}
+
+// matchTrades 按 tradeKey 配对两个运行的交易，返回只在 base、只在 other 中的交易（按出现顺序）
+// 以及配对后股数或金额不同的交易（按基准运行中的顺序）
+func matchTrades(base, other []*MonthlyReport) (onlyInBase, onlyInOther []TradingAction, changed []TradeChange) {
+	candidates := make(map[string][]TradingAction)
+	for _, report := range other {
+		for _, action := range report.TradingActions {
+			key := tradeKey(action)
+			candidates[key] = append(candidates[key], action)
+		}
+	}
+
+	matched := make(map[string]int)
+	for _, report := range base {
+		for _, action := range report.TradingActions {
+			key := tradeKey(action)
+			if matched[key] >= len(candidates[key]) {
+				onlyInBase = append(onlyInBase, action)
+				continue
+			}
+			match := candidates[key][matched[key]]
+			matched[key]++
+			if !action.Shares.Equal(match.Shares) || !action.Amount.Round(2).Equal(match.Amount.Round(2)) {
+				changed = append(changed, TradeChange{Base: action, Other: match})
+			}
+		}
+	}
+
+	for _, report := range other {
+		for _, action := range report.TradingActions {
+			key := tradeKey(action)
+			if matched[key] > 0 {
+				matched[key]--
+				continue
+			}
+			onlyInOther = append(onlyInOther, action)
+		}
+	}
+	return onlyInBase, onlyInOther, changed
+}
+
+// holdingDiffs 两个月度报告中持股数不同的股票，按代码排序
+func holdingDiffs(base, other *MonthlyReport) []HoldingDiff {
+	symbols := make(map[string]bool)
+	for symbol := range base.Positions {
+		symbols[symbol] = true
+	}
+	for symbol := range other.Positions {
+		symbols[symbol] = true
+	}
+
+	var rows []HoldingDiff
+	for symbol := range symbols {
+		row := HoldingDiff{Symbol: symbol}
+		if position := base.Positions[symbol]; position != nil {
+			row.BaseShares, row.BaseWeight = position.Shares, position.Weight
+		}
+		if position := other.Positions[symbol]; position != nil {
+			row.OtherShares, row.OtherWeight = position.Shares, position.Weight
+		}
+		if !row.BaseShares.Equal(row.OtherShares) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Symbol < rows[j].Symbol })
+	return rows
+}
+
+// tables 以表格形式返回差异：逐月净值和收益率、两边独有的交易、各月持仓差异
+// 列名为消息 ID，输出时翻译；A 为基准运行，B 为对比运行
+func (diff *RunDiff) tables() []tearSheetTable {
+	hundred := decimal.NewFromInt(100)
+	orDash := func(report *MonthlyReport, value func(report *MonthlyReport) string) string {
+		if report == nil {
+			return "-"
+		}
+		return value(report)
+	}
+	totalValue := func(report *MonthlyReport) string { return report.TotalValue.StringFixed(2) }
+	monthlyReturn := func(report *MonthlyReport) string { return report.MonthlyReturn.Mul(hundred).StringFixed(2) }
+
+	changed := 0
+	monthly := tearSheetTable{
+		Headers: []string{"Month", "Value A", "Value B", "Δ Value", "Return A %", "Return B %", "Δ Return"},
+	}
+	for _, month := range diff.Months {
+		if month.Changed() {
+			changed++
+		}
+		deltaValue, deltaReturn := "-", "-"
+		if month.Base != nil && month.Other != nil {
+			deltaValue = signed(month.Other.TotalValue.Sub(month.Base.TotalValue).StringFixed(2))
+			deltaReturn = signed(month.Other.MonthlyReturn.Sub(month.Base.MonthlyReturn).Mul(hundred).StringFixed(2))
+		}
+		monthly.Rows = append(monthly.Rows, []string{
+			month.Month,
+			orDash(month.Base, totalValue), orDash(month.Other, totalValue), deltaValue,
+			orDash(month.Base, monthlyReturn), orDash(month.Other, monthlyReturn), deltaReturn,
+		})
+	}
+	monthly.Title = fmt.Sprintf(T("Monthly values (%d of %d months differ)"), changed, len(diff.Months))
+
+	tables := []tearSheetTable{
+		monthly,
+		tradeDiffTable(fmt.Sprintf(T("Trades only in %s (%d)"), diff.Base.Label, len(diff.OnlyInBase)), diff.OnlyInBase),
+		tradeDiffTable(fmt.Sprintf(T("Trades only in %s (%d)"), diff.Other.Label, len(diff.OnlyInOther)), diff.OnlyInOther),
+		tradeChangeTable(fmt.Sprintf(T("Changed trades (%d)"), len(diff.ChangedTrades)), diff.ChangedTrades),
+	}
+
+	for _, holdings := range diff.Holdings {
+		table := tearSheetTable{
+			Title:   fmt.Sprintf(T("Holdings differences in %s (%d)"), holdings.Month, len(holdings.Rows)),
+			Headers: []string{"Symbol", "Shares A", "Shares B", "Δ Shares", "Weight A %", "Weight B %"},
+		}
+		for _, row := range holdings.Rows {
+			table.Rows = append(table.Rows, []string{
+				row.Symbol, row.BaseShares.String(), row.OtherShares.String(),
+				signed(row.OtherShares.Sub(row.BaseShares).String()),
+				row.BaseWeight.Mul(hundred).StringFixed(2), row.OtherWeight.Mul(hundred).StringFixed(2),
+			})
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// tradeDiffTable 独有交易表
+func tradeDiffTable(title string, actions []TradingAction) tearSheetTable {
+	table := tearSheetTable{
+		Title:   title,
+		Headers: []string{"Date", "Symbol", "Side", "Shares", "Price", "Notional", "Reason"},
+	}
+	for _, action := range actions {
+		table.Rows = append(table.Rows, []string{
+			action.Date.Format("2006-01-02"), action.Symbol, action.Action,
+			action.Shares.String(), action.Price.StringFixed(2), action.Amount.StringFixed(2), TradeReasonText(action.Reason),
+		})
+	}
+	return table
+}
+
+// tradeChangeTable 变更交易表，列出两边的股数、金额及差额
+func tradeChangeTable(title string, changes []TradeChange) tearSheetTable {
+	table := tearSheetTable{
+		Title:   title,
+		Headers: []string{"Date", "Symbol", "Side", "Shares A", "Shares B", "Δ Shares", "Notional A", "Notional B", "Δ Notional"},
+	}
+	for _, change := range changes {
+		base, other := change.Base, change.Other
+		table.Rows = append(table.Rows, []string{
+			base.Date.Format("2006-01-02"), base.Symbol, base.Action,
+			base.Shares.String(), other.Shares.String(), signed(other.Shares.Sub(base.Shares).String()),
+			base.Amount.StringFixed(2), other.Amount.StringFixed(2), signed(other.Amount.Sub(base.Amount).StringFixed(2)),
+		})
+	}
+	return table
+}
+
+// PrintRunDiff 输出两个运行的逐月净值、独有交易和持仓差异
+func PrintRunDiff(w io.Writer, diff *RunDiff) {
+	fmt.Fprintf(w, T("\n=== A: %s vs B: %s ===\n"), diff.Base.Label, diff.Other.Label)
+	for _, table := range diff.tables() {
+		fmt.Fprintf(w, "\n%s\n", table.Title)
+		if len(table.Rows) == 0 {
+			continue
+		}
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		for i, header := range table.Headers {
+			if i > 0 {
+				fmt.Fprint(writer, "\t")
+			}
+			fmt.Fprint(writer, T(header))
+		}
+		fmt.Fprint(writer, "\t\n")
+		for _, row := range table.Rows {
+			fmt.Fprint(writer, strings.Join(row, "\t")+"\t\n")
+		}
+		writer.Flush()
+	}
+}
+
+// comparisonEquityChart 将多个运行的净值曲线叠加在同一张图中，缺少的月份留空
+func comparisonEquityChart(runs []*ComparedRun) *charts.Line {
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{Theme: types.ThemeWesteros}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    T("Equity Curve"),
+			Subtitle: T("Portfolio Value by Run"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: T("Date"),
+			Type: "category",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: T("Value (USD)"),
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: boolPtr(true)}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: boolPtr(true), Trigger: "axis"}),
+	)
+
+	indexes := make([]map[string]*MonthlyReport, 0, len(runs))
+	seen := make(map[string]bool)
+	var months []string
+	for _, run := range runs {
+		index := monthlyReportIndex(run.Reports)
+		indexes = append(indexes, index)
+		for month := range index {
+			if !seen[month] {
+				seen[month] = true
+				months = append(months, month)
+			}
+		}
+	}
+	sort.Strings(months)
+	line.SetXAxis(months)
+
+	for i, run := range runs {
+		values := make([]opts.LineData, 0, len(months))
+		for _, month := range months {
+			report := indexes[i][month]
+			if report == nil {
+				values = append(values, opts.LineData{Value: "-"})
+				continue
+			}
+			valueFloat, _ := report.TotalValue.Round(2).Float64()
+			values = append(values, opts.LineData{Value: valueFloat})
+		}
+		line.AddSeries(run.Label, values)
+	}
+	return line
+}
+
+// comparisonReportData 对比报告模板数据
+type comparisonReportData struct {
+	Title       string
+	GeneratedAt string
+	ChartFile   string             // Markdown 报告旁的净值曲线图文件名
+	Metrics     tearSheetTable     // 关键指标对比
+	Diffs       []*RunDiff         // 各运行相对第一个运行的差异
+	Tables      [][]tearSheetTable // 与 Diffs 一一对应的差异表
+}
+
+// WriteComparisonReport 按文件扩展名输出对比报告：.md 输出 Markdown，净值曲线叠加图写入同名 _equity.html；
+// .html 输出包含叠加净值曲线的单页报告
+func WriteComparisonReport(path string, runs []*ComparedRun, diffs []*RunDiff) error {
+	labels := make([]string, 0, len(runs))
+	for _, run := range runs {
+		labels = append(labels, run.Label)
+	}
+	data := comparisonReportData{
+		Title:       fmt.Sprintf(T("Run Comparison: %s"), strings.Join(labels, " vs ")),
+		GeneratedAt: time.Now().Format("2006-01-02 15:04"),
+		Metrics:     comparisonTable(runs),
+		Diffs:       diffs,
+	}
+	for _, diff := range diffs {
+		data.Tables = append(data.Tables, diff.tables())
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".md":
+		data.ChartFile = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "_equity.html"
+		chartPath := filepath.Join(filepath.Dir(path), data.ChartFile)
+		if err := writeComparisonChart(chartPath, runs); err != nil {
+			return err
+		}
+		return writeComparisonMarkdown(path, data)
+	case ".html", ".htm":
+		return writeComparisonHTML(path, runs, data)
+	default:
+		return fmt.Errorf(T("不支持的对比报告格式: %s（应为 .md 或 .html）"), path)
+	}
+}
+
+// writeComparisonChart 将叠加净值曲线图写入单独的 HTML 文件
+func writeComparisonChart(filePath string, runs []*ComparedRun) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer f.Close()
+
+	if err := comparisonEquityChart(runs).Render(f); err != nil {
+		return fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	slog.Info(T("净值曲线对比图已生成"), "path", filePath)
+	return nil
+}
+
+// writeComparisonMarkdown 输出 Markdown 对比报告
+func writeComparisonMarkdown(filePath string, data comparisonReportData) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer f.Close()
+
+	if err := comparisonMarkdownTemplate.Execute(f, data); err != nil {
+		return fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	slog.Info(T("对比报告已生成"), "path", filePath)
+	return nil
+}
+
+// writeComparisonHTML 使用单页报告模板输出 HTML 对比报告
+func writeComparisonHTML(filePath string, runs []*ComparedRun, data comparisonReportData) error {
+	chart := comparisonEquityChart(runs)
+	page := components.NewPage()
+	page.AddCharts(chart)
+	page.Validate()
+
+	base := runs[0].Config
+	sheet := tearSheetData{
+		Lang:  Language(),
+		Title: data.Title,
+		Period: fmt.Sprintf("%s - %s",
+			base.StartDate.Format("2006-01-02"), base.EndDate.Format("2006-01-02")),
+		GeneratedAt: data.GeneratedAt,
+		Scripts:     NewChartGenerator(base).tearSheetScripts(page),
+	}
+	for _, run := range runs {
+		sheet.Summary = append(sheet.Summary, tearSheetMetric{Name: run.Label, Strategy: "$" + run.FinalValue.StringFixed(2)})
+	}
+	sheet.Sections = append(sheet.Sections, tearSheetSection{
+		Title:  T("Equity Curve"),
+		Charts: []tearSheetChart{snippetChart(chart.RenderSnippet())},
+		Tables: []tearSheetTable{data.Metrics},
+	})
+	for i, diff := range data.Diffs {
+		sheet.Sections = append(sheet.Sections, tearSheetSection{
+			Title:  fmt.Sprintf(T("A: %s vs B: %s"), diff.Base.Label, diff.Other.Label),
+			Tables: data.Tables[i],
+		})
+	}
+
+	tpl, err := template.New("tearsheet").Funcs(template.FuncMap{"T": T}).Parse(tearSheetTemplate)
+	if err != nil {
+		return fmt.Errorf(T("failed to parse tear sheet template: %v"), err)
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer f.Close()
+
+	if err := tpl.Execute(f, sheet); err != nil {
+		return fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	slog.Info(T("对比报告已生成"), "path", filePath)
+	return nil
+}
+
+// comparisonMarkdownTemplate Markdown 对比报告模板
+var comparisonMarkdownTemplate = texttemplate.Must(texttemplate.New("compare").Funcs(texttemplate.FuncMap{"T": T}).Parse(`# {{ .Title }}
+
+{{ T "Generated" }} {{ .GeneratedAt }}
+{{ define "table" }}
+|{{ range .Headers }} {{ T . }} |{{ end }}
+|{{ range .Headers }}---|{{ end }}
+{{- range .Rows }}
+|{{ range . }} {{ . }} |{{ end }}
+{{- end }}
+{{ end }}
+## {{ T "Equity Curve" }}
+
+[{{ .ChartFile }}]({{ .ChartFile }})
+
+## {{ .Metrics.Title }}
+{{ template "table" .Metrics }}
+{{- range $i, $diff := .Diffs }}
+## {{ printf (T "A: %s vs B: %s") $diff.Base.Label $diff.Other.Label }}
+{{ range index $.Tables $i }}
+### {{ .Title }}
+{{ if .Rows }}{{ template "table" . }}{{ end }}
+{{- end }}
+{{- end }}
+`))
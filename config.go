@@ -9,11 +9,58 @@ type Config struct {
 	InitialCapital float64   // 初始资金
 	StartDate      time.Time // 开始日期
 	EndDate        time.Time // 结束日期
-	StockPriceDir  string    // 股价数据目录
+	StockPriceDir  string    // 股价数据目录（long-csv 格式为文件路径）
+	PriceFormat    string    // 股价格式："yahoo-csv"、"iso-csv"、"json"、"long-csv" 或 "sqlite"
 	HistoryDir     string    // 交易信号数据目录
+	SignalFormat   string    // 交易信号格式："csv-dir"、"long-csv"、"json" 或 "sqlite"
+	SignalFile     string    // long-csv / json 格式的信号文件路径
 	OutputDir      string    // 输出目录
 	ChartsDir      string    // 图表输出目录
 	ReportsDir     string    // 报告输出目录
+	StorePath      string    // SQLite 数据库路径，sqlite 格式读取数据及保存回测结果时使用
+	RunID          string    // 保存回测结果使用的运行 ID
+
+	FractionalShares bool  // 是否允许买入碎股
+	SharePrecision   int32 // 碎股模式下股数保留的小数位数
+
+	AllocationRatio    float64 // 目标投资比例，大于1表示使用融资
+	MarginInterestRate float64 // 融资年化利率
+	MaintenanceMargin  float64 // 维持保证金比例（净值 / 总敞口）
+
+	LongShort      bool    // 是否做空剔除股票
+	GrossExposure  float64 // 多空模式下的总敞口（多头 + 空头）
+	NetExposure    float64 // 多空模式下的净敞口（多头 - 空头）
+	ShortBorrowFee float64 // 融券年化费率
+
+	IndexPriceDir       string  // 指数价格数据目录
+	RegimeEnabled       bool    // 是否启用市场状态过滤
+	RegimeSymbol        string  // 用于判断市场状态的指数代码
+	RegimeRule          string  // 判断规则："ma" 或 "drawdown"
+	RegimeWindow        int     // 均线或高点回看的交易日数
+	RegimeDrawdownLimit float64 // drawdown 规则下触发 risk-off 的回撤阈值
+	RegimeRiskOffScale  float64 // risk-off 时建仓比例的缩放系数，0 表示全部转为现金
+
+	AllocationMode   string  // 建仓比例模式："fixed" 或 "vol-target"
+	TargetVolatility float64 // 目标年化波动率
+	VolLookback      int     // 估算波动率使用的历史交易日数
+	MinExposure      float64 // 波动率目标模式下的最小总仓位
+	MaxExposure      float64 // 波动率目标模式下的最大总仓位
+
+	TopDrawdowns int // 回撤报告中输出的回撤区间数量，0 表示全部
+
+	MetadataFile string  // 股票分类元数据文件，为空时不统计板块
+	SectorCap    float64 // 单一板块权重上限，0 表示不限制
+
+	SignalPriceTolerance float64 // 信号参考价格的容差，0 表示不校验
+	SignalPriceAction    string  // 成交价高于参考价格超过容差时的处理："flag" 或 "skip"
+	SignalWeighting      string  // 买入资金分配方式："equal" 或 "pl"
+
+	Benchmark string // 报告中对比的基准指数代码，从指数目录加载，为空时不对比
+	AssetsDir string // 本地 go-echarts 资源目录（echarts.min.js 等），单页报告优先内联其中的脚本，缺失时使用内置资源
+	AssetsCDN bool   // 单页报告从 CDN 加载 go-echarts 脚本，不内联
+
+	MarkdownTemplate string   // summary.md 使用的自定义模板，为空时使用内置模板
+	OutputFormats    []string // 报告输出格式："csv"、"json"、"jsonl"、"xlsx"，可同时指定多个
 }
 
 // DefaultConfig 返回默认配置
@@ -26,9 +73,72 @@ func DefaultConfig() *Config {
 		StartDate:      startDate,
 		EndDate:        endDate,
 		StockPriceDir:  "stock_price",
+		PriceFormat:    "yahoo-csv",
 		HistoryDir:     "history",
+		SignalFormat:   "csv-dir",
 		OutputDir:      "output",
 		ChartsDir:      "output/charts",
 		ReportsDir:     "output/reports",
+
+		FractionalShares: false,
+		SharePrecision:   4,
+
+		AllocationRatio:    0.9,
+		MarginInterestRate: 0.07,
+		MaintenanceMargin:  0.25,
+
+		LongShort:      false,
+		GrossExposure:  1.3,
+		NetExposure:    0.5,
+		ShortBorrowFee: 0.03,
+
+		IndexPriceDir:       "all_time_stock_price",
+		RegimeEnabled:       false,
+		RegimeSymbol:        "SPY",
+		RegimeRule:          RegimeRuleMovingAverage,
+		RegimeWindow:        200,
+		RegimeDrawdownLimit: 0.1,
+		RegimeRiskOffScale:  0.5,
+
+		AllocationMode:   AllocationModeFixed,
+		TargetVolatility: 0.2,
+		VolLookback:      63,
+		MinExposure:      0.3,
+		MaxExposure:      1.0,
+
+		TopDrawdowns: 10,
+
+		MetadataFile: "metadata/symbols.csv",
+		SectorCap:    0,
+
+		SignalPriceTolerance: 0,
+		SignalPriceAction:    "flag",
+		SignalWeighting:      "equal",
+
+		Benchmark: "SPY",
+
+		OutputFormats: []string{"csv"},
+	}
+}
+
+// maxGrossExposure 返回配置允许的最大总敞口：多空模式为总敞口，波动率目标模式为最大仓位，否则为目标投资比例
+func (config *Config) maxGrossExposure() float64 {
+	switch {
+	case config.LongShort:
+		return config.GrossExposure
+	case config.AllocationMode == AllocationModeVolTarget:
+		return config.MaxExposure
+	default:
+		return config.AllocationRatio
+	}
+}
+
+// HasOutputFormat 判断是否需要输出指定格式的报告
+func (config *Config) HasOutputFormat(format string) bool {
+	for _, f := range config.OutputFormats {
+		if f == format {
+			return true
+		}
 	}
+	return false
 }
\ No newline at end of file
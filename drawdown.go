@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// drawdownSeries 计算每个月相对历史最高净值的回撤（负数或零）
+func drawdownSeries(reports []*MonthlyReport) []decimal.Decimal {
+	series := make([]decimal.Decimal, 0, len(reports))
+	peak := decimal.Zero
+	for _, report := range reports {
+		if report.TotalValue.GreaterThan(peak) {
+			peak = report.TotalValue
+		}
+		drawdown := decimal.Zero
+		if peak.IsPositive() {
+			drawdown = report.TotalValue.Div(peak).Sub(decimal.NewFromInt(1))
+		}
+		series = append(series, drawdown)
+	}
+	return series
+}
+
+// AnalyzeDrawdowns 从净值曲线中提取所有回撤区间，按回撤幅度从大到小排序
+func AnalyzeDrawdowns(reports []*MonthlyReport) []DrawdownEpisode {
+	var episodes []DrawdownEpisode
+	if len(reports) == 0 {
+		return episodes
+	}
+
+	var current *DrawdownEpisode
+	peakValue := reports[0].TotalValue
+	peakDate := reports[0].Date
+
+	for _, report := range reports {
+		value := report.TotalValue
+		if value.GreaterThanOrEqual(peakValue) {
+			// 恢复至前高，结束当前回撤区间
+			if current != nil {
+				current.RecoveryDate = report.Date
+				current.Recovered = true
+				episodes = append(episodes, *current)
+				current = nil
+			}
+			peakValue = value
+			peakDate = report.Date
+			continue
+		}
+
+		if current == nil {
+			current = &DrawdownEpisode{
+				PeakDate:    peakDate,
+				PeakValue:   peakValue,
+				TroughDate:  report.Date,
+				TroughValue: value,
+			}
+		}
+		if value.LessThan(current.TroughValue) {
+			current.TroughDate = report.Date
+			current.TroughValue = value
+		}
+		current.Depth = current.TroughValue.Div(current.PeakValue).Sub(decimal.NewFromInt(1))
+	}
+
+	// 期末尚未恢复的回撤
+	if current != nil {
+		episodes = append(episodes, *current)
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].Depth.LessThan(episodes[j].Depth)
+	})
+
+	return episodes
+}
+
+// durationDays 计算两个日期之间的自然日天数
+func durationDays(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
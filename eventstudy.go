@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// eventStudyHorizons 事件研究的前瞻交易日数
+var eventStudyHorizons = []int{1, 5, 21, 63, 126}
+
+// SignalEvent 单个纳入/剔除信号事件的前瞻收益
+type SignalEvent struct {
+	Symbol           string          // 股票代码
+	Status           string          // "纳入" 或 "剔除"
+	SignalDate       time.Time       // 信号文件日期
+	EventDate        time.Time       // 信号当月首个交易日
+	RawReturns       map[int]float64 // 前瞻交易日数 -> 原始收益率
+	ExcessReturns    map[int]float64 // 前瞻交易日数 -> 超额收益率（相对基准）
+	CumulativeExcess []float64       // 第0..N个交易日的累计超额收益率
+}
+
+// EventStudySummary 某类信号在某个前瞻期的统计结果
+type EventStudySummary struct {
+	Status       string  // "纳入" 或 "剔除"
+	Horizon      int     // 前瞻交易日数
+	Count        int     // 样本数
+	MeanRaw      float64 // 原始收益率均值
+	MedianRaw    float64 // 原始收益率中位数
+	MeanExcess   float64 // 超额收益率均值
+	MedianExcess float64 // 超额收益率中位数
+	HitRate      float64 // 命中率：纳入为超额收益率 > 0 的比例，剔除为超额收益率 < 0 的比例
+}
+
+// EventStudyResult 事件研究结果
+type EventStudyResult struct {
+	Benchmark  string               // 基准指数代码
+	Events     []*SignalEvent       // 所有信号事件
+	Summaries  []EventStudySummary  // 按信号类型和前瞻期汇总
+	AverageCAR map[string][]float64 // 信号类型 -> 第0..N个交易日的平均累计超额收益率
+}
+
+// priceSeries 按日期升序排列的收盘价序列
+type priceSeries struct {
+	dates  []string
+	closes []float64
+}
+
+// newPriceSeries 将股价映射转换为升序收盘价序列
+func newPriceSeries(prices map[string]*StockPrice) *priceSeries {
+	series := &priceSeries{}
+	for dateKey := range prices {
+		series.dates = append(series.dates, dateKey)
+	}
+	sort.Strings(series.dates)
+	for _, dateKey := range series.dates {
+		series.closes = append(series.closes, prices[dateKey].Close.InexactFloat64())
+	}
+	return series
+}
+
+// closeOnOrBefore 返回指定日期当天或之前最近一个交易日的收盘价
+func (series *priceSeries) closeOnOrBefore(dateKey string) (float64, bool) {
+	index := sort.SearchStrings(series.dates, dateKey)
+	if index < len(series.dates) && series.dates[index] == dateKey {
+		return series.closes[index], true
+	}
+	if index == 0 {
+		return 0, false
+	}
+	return series.closes[index-1], true
+}
+
+// EventStudy 信号有效性事件研究
+type EventStudy struct {
+	config     *Config
+	dataLoader *StockDataLoader
+	benchmark  string
+}
+
+// NewEventStudy 创建事件研究
+func NewEventStudy(dataLoader *StockDataLoader, config *Config, benchmark string) *EventStudy {
+	return &EventStudy{
+		config:     config,
+		dataLoader: dataLoader,
+		benchmark:  benchmark,
+	}
+}
+
+// Run 对统计周期内所有纳入/剔除信号计算前瞻收益和超额收益
+func (study *EventStudy) Run() (*EventStudyResult, error) {
+	benchmarkSource, err := newIndexPriceSource(study.config)
+	if err != nil {
+		return nil, err
+	}
+	benchmarkPrices, err := benchmarkSource.LoadStockPrice(study.benchmark)
+	if err != nil {
+		return nil, fmt.Errorf(T("加载基准 %s 数据失败: %v"), study.benchmark, err)
+	}
+	benchmark := newPriceSeries(benchmarkPrices)
+
+	signalDates, err := study.dataLoader.ListSignalDates()
+	if err != nil {
+		return nil, err
+	}
+
+	maxHorizon := eventStudyHorizons[len(eventStudyHorizons)-1]
+	seriesCache := make(map[string]*priceSeries)
+	result := &EventStudyResult{
+		Benchmark:  study.benchmark,
+		AverageCAR: make(map[string][]float64),
+	}
+
+	for _, signalDate := range signalDates {
+		if signalDate.Before(study.config.StartDate) || signalDate.After(study.config.EndDate) {
+			continue
+		}
+
+		signals, err := study.dataLoader.LoadTradeSignals(signalDate)
+		if err != nil {
+			slog.Warn(T("加载交易信号失败"), "date", signalDate.Format("2006-01-02"), "err", err)
+			continue
+		}
+
+		for _, signal := range signals {
+			if signal.Status != "纳入" && signal.Status != "剔除" {
+				continue
+			}
+
+			series, cached := seriesCache[signal.Symbol]
+			if !cached {
+				prices, err := study.dataLoader.LoadStockPrice(signal.Symbol)
+				if err != nil {
+					slog.Warn(T("跳过股票"), "symbol", signal.Symbol, "err", err)
+					seriesCache[signal.Symbol] = nil
+					continue
+				}
+				series = newPriceSeries(prices)
+				seriesCache[signal.Symbol] = series
+			}
+			if series == nil {
+				continue
+			}
+
+			event := study.buildEvent(signal, signalDate, series, benchmark, maxHorizon)
+			if event != nil {
+				result.Events = append(result.Events, event)
+			}
+		}
+	}
+
+	result.Summaries = summarizeEvents(result.Events)
+	result.AverageCAR = averageCAR(result.Events, maxHorizon)
+
+	return result, nil
+}
+
+// buildEvent 计算单个信号的前瞻收益，信号当月没有交易日时返回 nil
+func (study *EventStudy) buildEvent(signal *TradeSignal, signalDate time.Time, series, benchmark *priceSeries, maxHorizon int) *SignalEvent {
+	monthStart := time.Date(signalDate.Year(), signalDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	start := sort.SearchStrings(series.dates, monthStart.Format("20060102"))
+	if start >= len(series.dates) || series.dates[start] >= monthEnd.Format("20060102") {
+		slog.Warn(T("信号当月没有交易日"), "symbol", signal.Symbol, "month", signalDate.Format("2006-01"))
+		return nil
+	}
+
+	eventDate, _ := time.Parse("20060102", series.dates[start])
+	basePrice := series.closes[start]
+	baseBenchmark, ok := benchmark.closeOnOrBefore(series.dates[start])
+	if !ok || basePrice == 0 || baseBenchmark == 0 {
+		return nil
+	}
+
+	event := &SignalEvent{
+		Symbol:        signal.Symbol,
+		Status:        signal.Status,
+		SignalDate:    signalDate,
+		EventDate:     eventDate,
+		RawReturns:    make(map[int]float64),
+		ExcessReturns: make(map[int]float64),
+	}
+
+	for day := 0; day <= maxHorizon && start+day < len(series.dates); day++ {
+		benchmarkPrice, ok := benchmark.closeOnOrBefore(series.dates[start+day])
+		if !ok {
+			break
+		}
+		raw := series.closes[start+day]/basePrice - 1
+		excess := raw - (benchmarkPrice/baseBenchmark - 1)
+		event.CumulativeExcess = append(event.CumulativeExcess, excess)
+
+		for _, horizon := range eventStudyHorizons {
+			if day == horizon {
+				event.RawReturns[horizon] = raw
+				event.ExcessReturns[horizon] = excess
+			}
+		}
+	}
+
+	return event
+}
+
+// summarizeEvents 按信号类型和前瞻期统计均值、中位数和命中率
+func summarizeEvents(events []*SignalEvent) []EventStudySummary {
+	var summaries []EventStudySummary
+	for _, status := range []string{"纳入", "剔除"} {
+		for _, horizon := range eventStudyHorizons {
+			var raws, excesses []float64
+			hits := 0
+			for _, event := range events {
+				if event.Status != status {
+					continue
+				}
+				excess, ok := event.ExcessReturns[horizon]
+				if !ok {
+					continue
+				}
+				raws = append(raws, event.RawReturns[horizon])
+				excesses = append(excesses, excess)
+				if (status == "纳入" && excess > 0) || (status == "剔除" && excess < 0) {
+					hits++
+				}
+			}
+
+			summary := EventStudySummary{Status: status, Horizon: horizon, Count: len(raws)}
+			if len(raws) > 0 {
+				summary.MeanRaw = mean(raws)
+				summary.MedianRaw = median(raws)
+				summary.MeanExcess = mean(excesses)
+				summary.MedianExcess = median(excesses)
+				summary.HitRate = float64(hits) / float64(len(raws))
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+// averageCAR 计算每类信号在第0..N个交易日的平均累计超额收益率
+func averageCAR(events []*SignalEvent, maxHorizon int) map[string][]float64 {
+	result := make(map[string][]float64)
+	for _, status := range []string{"纳入", "剔除"} {
+		var averages []float64
+		for day := 0; day <= maxHorizon; day++ {
+			var values []float64
+			for _, event := range events {
+				if event.Status == status && day < len(event.CumulativeExcess) {
+					values = append(values, event.CumulativeExcess[day])
+				}
+			}
+			if len(values) == 0 {
+				break
+			}
+			averages = append(averages, mean(values))
+		}
+		result[status] = averages
+	}
+	return result
+}
+
+// mean 计算均值
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// median 计算中位数
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// WriteReports 输出事件明细和汇总统计 CSV
+func (study *EventStudy) WriteReports(result *EventStudyResult) error {
+	if err := os.MkdirAll(study.config.OutputDir, 0755); err != nil {
+		return fmt.Errorf(T("创建输出目录失败: %v"), err)
+	}
+
+	// 汇总统计
+	summaryPath := filepath.Join(study.config.OutputDir, "event_study_summary.csv")
+	summaryRows := [][]string{{
+		"Status", "Horizon (Days)", "Count",
+		"Mean Raw %", "Median Raw %", "Mean Excess %", "Median Excess %", "Hit Rate %",
+	}}
+	for _, summary := range result.Summaries {
+		summaryRows = append(summaryRows, []string{
+			summary.Status,
+			strconv.Itoa(summary.Horizon),
+			strconv.Itoa(summary.Count),
+			formatPercent(summary.MeanRaw),
+			formatPercent(summary.MedianRaw),
+			formatPercent(summary.MeanExcess),
+			formatPercent(summary.MedianExcess),
+			formatPercent(summary.HitRate),
+		})
+	}
+	if err := writeCSVFile(summaryPath, summaryRows); err != nil {
+		return err
+	}
+	slog.Info(T("事件研究汇总已生成"), "path", summaryPath)
+
+	// 事件明细
+	eventsPath := filepath.Join(study.config.OutputDir, "event_study_events.csv")
+	header := []string{"Symbol", "Status", "Signal Date", "Event Date"}
+	for _, horizon := range eventStudyHorizons {
+		header = append(header, fmt.Sprintf("Raw %dD %%", horizon), fmt.Sprintf("Excess %dD %%", horizon))
+	}
+	eventRows := [][]string{header}
+	for _, event := range result.Events {
+		row := []string{
+			event.Symbol,
+			event.Status,
+			event.SignalDate.Format("2006-01-02"),
+			event.EventDate.Format("2006-01-02"),
+		}
+		for _, horizon := range eventStudyHorizons {
+			raw, ok := event.RawReturns[horizon]
+			if !ok {
+				row = append(row, "", "")
+				continue
+			}
+			row = append(row, formatPercent(raw), formatPercent(event.ExcessReturns[horizon]))
+		}
+		eventRows = append(eventRows, row)
+	}
+	if err := writeCSVFile(eventsPath, eventRows); err != nil {
+		return err
+	}
+	slog.Info(T("事件研究明细已生成"), "path", eventsPath)
+
+	return nil
+}
+
+// PrintSummary 打印事件研究汇总到控制台
+func (study *EventStudy) PrintSummary(result *EventStudyResult) {
+	fmt.Printf(T("\n=== 信号事件研究 (基准: %s, 事件数: %d) ===\n"), result.Benchmark, len(result.Events))
+	fmt.Printf("%-4s %6s %6s %10s %10s %10s %10s %8s\n",
+		T("信号"), T("天数"), T("样本"), T("平均收益%"), T("中位收益%"), T("平均超额%"), T("中位超额%"), T("命中率%"))
+	for _, summary := range result.Summaries {
+		fmt.Printf("%-4s %6d %6d %10s %10s %10s %10s %8s\n",
+			T(summary.Status), summary.Horizon, summary.Count,
+			formatPercent(summary.MeanRaw), formatPercent(summary.MedianRaw),
+			formatPercent(summary.MeanExcess), formatPercent(summary.MedianExcess),
+			formatPercent(summary.HitRate))
+	}
+}
+
+// formatPercent 将小数格式化为保留两位小数的百分数
+func formatPercent(value float64) string {
+	return strconv.FormatFloat(value*100, 'f', 2, 64)
+}
+
+// writeCSVFile 将所有行写入 CSV 文件
+func writeCSVFile(filePath string, rows [][]string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// 界面语言
+const (
+	LangZH = "zh" // 中文
+	LangEN = "en" // 英文
+)
+
+// langUsage 各命令 -lang 参数的说明
+const langUsage = "Output language for console messages, errors, report summaries and chart titles: zh or en (default from TECH_TITANS_LANG, zh if unset)"
+
+// currentLang 当前界面语言，默认中文，可通过 -lang 参数或 TECH_TITANS_LANG 环境变量设置
+var currentLang = LangZH
+
+// SetLanguage 设置控制台输出、错误信息、报告汇总行和图表标题使用的语言
+func SetLanguage(lang string) error {
+	switch lang {
+	case LangZH, LangEN:
+		currentLang = lang
+		return nil
+	default:
+		return fmt.Errorf(T("不支持的语言: %s"), lang)
+	}
+}
+
+// applyLanguage 应用 -lang 参数，不支持的语言直接退出
+func applyLanguage(lang string) {
+	if err := SetLanguage(lang); err != nil {
+		log.Fatalf(T("Invalid language: %s"), lang)
+	}
+}
+
+// Language 返回当前界面语言
+func Language() string {
+	return currentLang
+}
+
+// defaultLanguage -lang 参数的默认值，取 TECH_TITANS_LANG 环境变量，未设置时为中文
+func defaultLanguage() string {
+	if lang := os.Getenv("TECH_TITANS_LANG"); lang != "" {
+		return lang
+	}
+	return LangZH
+}
+
+// T 返回消息在当前语言下的文本
+// msg 为代码中的原文（中文或英文），作为消息目录的键；目录中没有译文时原样返回，
+// 译文中的格式化占位符与原文保持一致，可直接传给 fmt.Printf / fmt.Errorf
+func T(msg string) string {
+	if text, ok := messageCatalog[currentLang][msg]; ok {
+		return text
+	}
+	return msg
+}
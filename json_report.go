@@ -0,0 +1,578 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunSchemaVersion JSON / JSONL 输出的结构版本，字段含义变化或删除字段时递增，新增字段不递增
+const RunSchemaVersion = "tech-titans.run/v1"
+
+// RunDocumentFile 运行目录中保存完整回测结果的文件名，每次运行都会写入，
+// report、compare、orders、serve 子命令从中读取已保存的运行
+const RunDocumentFile = "run.json"
+
+// 输出格式
+const (
+	OutputFormatCSV   = "csv"   // 各类 CSV 报告
+	OutputFormatJSON  = "json"  // 单个 run.json 文档（每次运行都会写入，保留该格式以兼容旧参数）
+	OutputFormatJSONL = "jsonl" // run.jsonl，每行一条记录
+	OutputFormatXLSX  = "xlsx"  // run.xlsx Excel 工作簿
+)
+
+// 金额、价格和比例均以 JSON 数字输出（保留 decimal 的完整精度），比例为小数形式（0.1 表示 10%），日期为 YYYY-MM-DD
+
+// jsonRunDocument run.json 顶层结构
+type jsonRunDocument struct {
+	Schema         string              `json:"schema"`
+	RunID          string              `json:"run_id"`
+	GeneratedAt    string              `json:"generated_at"`
+	Config         jsonConfig          `json:"config"`
+	Metrics        jsonMetrics         `json:"metrics"`
+	MonthlyReports []jsonMonthlyReport `json:"monthly_reports"`
+}
+
+// jsonConfig 运行配置
+type jsonConfig struct {
+	InitialCapital       json.Number `json:"initial_capital"`
+	StartDate            string      `json:"start_date"`
+	EndDate              string      `json:"end_date"`
+	PriceFormat          string      `json:"price_format"`
+	StockPriceDir        string      `json:"stock_price_dir"`
+	SignalFormat         string      `json:"signal_format"`
+	HistoryDir           string      `json:"history_dir"`
+	SignalFile           string      `json:"signal_file"`
+	IndexPriceDir        string      `json:"index_price_dir"`
+	FractionalShares     bool        `json:"fractional_shares"`
+	SharePrecision       int32       `json:"share_precision"`
+	AllocationMode       string      `json:"allocation_mode"`
+	AllocationRatio      json.Number `json:"allocation_ratio"`
+	TargetVolatility     json.Number `json:"target_volatility"`
+	VolLookback          int         `json:"vol_lookback"`
+	MinExposure          json.Number `json:"min_exposure"`
+	MaxExposure          json.Number `json:"max_exposure"`
+	MarginInterestRate   json.Number `json:"margin_interest_rate"`
+	MaintenanceMargin    json.Number `json:"maintenance_margin"`
+	LongShort            bool        `json:"long_short"`
+	GrossExposure        json.Number `json:"gross_exposure"`
+	NetExposure          json.Number `json:"net_exposure"`
+	ShortBorrowFee       json.Number `json:"short_borrow_fee"`
+	RegimeEnabled        bool        `json:"regime_enabled"`
+	RegimeSymbol         string      `json:"regime_symbol"`
+	RegimeRule           string      `json:"regime_rule"`
+	RegimeWindow         int         `json:"regime_window"`
+	RegimeDrawdownLimit  json.Number `json:"regime_drawdown_limit"`
+	RegimeRiskOffScale   json.Number `json:"regime_risk_off_scale"`
+	MetadataFile         string      `json:"metadata_file"`
+	SectorCap            json.Number `json:"sector_cap"`
+	SignalPriceTolerance json.Number `json:"signal_price_tolerance"`
+	SignalPriceAction    string      `json:"signal_price_action"`
+	SignalWeighting      string      `json:"signal_weighting"`
+	Benchmark            string      `json:"benchmark"`
+	TopDrawdowns         int         `json:"top_drawdowns"`
+	OutputFormats        []string    `json:"output_formats"`
+	MarkdownTemplate     string      `json:"markdown_template"`
+	AssetsDir            string      `json:"assets_dir"`
+	AssetsCDN            bool        `json:"assets_cdn"`
+}
+
+// jsonMetrics 绩效指标
+type jsonMetrics struct {
+	InitialCapital       json.Number `json:"initial_capital"`
+	FinalValue           json.Number `json:"final_value"`
+	TotalReturn          json.Number `json:"total_return"`
+	AnnualizedReturn     json.Number `json:"annualized_return"`
+	MaxDrawdown          json.Number `json:"max_drawdown"`
+	Volatility           json.Number `json:"volatility"`
+	SharpeRatio          json.Number `json:"sharpe_ratio"`
+	WinRate              json.Number `json:"win_rate"`
+	AverageMonthlyReturn json.Number `json:"average_monthly_return"`
+	TotalTrades          int         `json:"total_trades"`
+}
+
+// jsonMonthlyReport 月度报告
+type jsonMonthlyReport struct {
+	jsonMonthSummary
+	Positions      []jsonPosition      `json:"positions"`
+	TradingActions []jsonTradingAction `json:"trading_actions"`
+	SignalIssues   []jsonSignalIssue   `json:"signal_issues"`
+}
+
+// jsonMonthSummary 月度报告中除持仓、交易和信号校验明细外的字段
+type jsonMonthSummary struct {
+	Date             string                 `json:"date"`
+	TotalValue       json.Number            `json:"total_value"`
+	Cash             json.Number            `json:"cash"`
+	StockValue       json.Number            `json:"stock_value"`
+	Borrowed         json.Number            `json:"borrowed"`
+	InterestCharged  json.Number            `json:"interest_charged"`
+	Leverage         json.Number            `json:"leverage"`
+	LongValue        json.Number            `json:"long_value"`
+	ShortValue       json.Number            `json:"short_value"`
+	BorrowFee        json.Number            `json:"borrow_fee"`
+	Regime           string                 `json:"regime"`
+	AllocationRatio  json.Number            `json:"allocation_ratio"`
+	ExAnteVolatility json.Number            `json:"ex_ante_volatility"`
+	MonthlyReturn    json.Number            `json:"monthly_return"`
+	CumulativeReturn json.Number            `json:"cumulative_return"`
+	SectorWeights    map[string]json.Number `json:"sector_weights"`
+}
+
+// jsonPosition 持仓
+type jsonPosition struct {
+	Symbol       string      `json:"symbol"`
+	Shares       json.Number `json:"shares"`
+	BuyPrice     json.Number `json:"buy_price"`
+	BuyDate      string      `json:"buy_date"`
+	CurrentPrice json.Number `json:"current_price"`
+	MarketValue  json.Number `json:"market_value"`
+	CostBasis    json.Number `json:"cost_basis"`
+	PnL          json.Number `json:"pnl"`
+	PnLPercent   json.Number `json:"pnl_percent"`
+	Weight       json.Number `json:"weight"`
+}
+
+// jsonTradingAction 交易
+type jsonTradingAction struct {
+	Date       string      `json:"date"`
+	Symbol     string      `json:"symbol"`
+	Action     string      `json:"action"`
+	Shares     json.Number `json:"shares"`
+	Price      json.Number `json:"price"`
+	Amount     json.Number `json:"amount"`
+	Reason     string      `json:"reason"`
+	ReasonCode string      `json:"reason_code"`
+}
+
+// jsonSignalIssue 信号校验问题
+type jsonSignalIssue struct {
+	Date        string      `json:"date"`
+	Symbol      string      `json:"symbol"`
+	Status      string      `json:"status"`
+	SignalPrice string      `json:"signal_price"`
+	FilePrice   json.Number `json:"file_price"`
+	FillPrice   json.Number `json:"fill_price"`
+	Deviation   json.Number `json:"deviation"`
+	Issue       string      `json:"issue"`
+	Skipped     bool        `json:"skipped"`
+}
+
+// GenerateJSONLReport 将整个运行写入 run.jsonl，每行一条记录，type 字段区分记录类型：
+// run（配置和指标，第一行）、month（月度报告，不含明细）、position（月末持仓）、trade（交易）、signal_issue（信号校验问题）
+// 每行都带 run_id，多个运行的文件可以直接拼接
+func (rg *ReportGenerator) GenerateJSONLReport(reports []*MonthlyReport) error {
+	document := newRunDocument(rg.config, reports)
+
+	filePath := filepath.Join(rg.config.OutputDir, "run.jsonl")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建 JSONL 报告文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	write := func(recordType, date string, record interface{}) error {
+		line := struct {
+			Type   string      `json:"type"`
+			RunID  string      `json:"run_id"`
+			Date   string      `json:"date,omitempty"`
+			Record interface{} `json:"data"`
+		}{recordType, document.RunID, date, record}
+		return encoder.Encode(line)
+	}
+
+	header := struct {
+		Schema      string      `json:"schema"`
+		GeneratedAt string      `json:"generated_at"`
+		Config      jsonConfig  `json:"config"`
+		Metrics     jsonMetrics `json:"metrics"`
+	}{document.Schema, document.GeneratedAt, document.Config, document.Metrics}
+	if err := write("run", "", header); err != nil {
+		return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+	}
+
+	for _, report := range document.MonthlyReports {
+		if err := write("month", report.Date, report.jsonMonthSummary); err != nil {
+			return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+		}
+		for _, position := range report.Positions {
+			if err := write("position", report.Date, position); err != nil {
+				return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+			}
+		}
+		for _, action := range report.TradingActions {
+			if err := write("trade", report.Date, action); err != nil {
+				return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+			}
+		}
+		for _, issue := range report.SignalIssues {
+			if err := write("signal_issue", report.Date, issue); err != nil {
+				return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+			}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf(T("写入 JSONL 报告失败: %v"), err)
+	}
+
+	slog.Info(T("JSONL 报告已生成"), "path", filePath)
+	return nil
+}
+
+// newRunDocument 将配置、绩效指标和月度报告转换为 JSON 结构
+func newRunDocument(config *Config, reports []*MonthlyReport) *jsonRunDocument {
+	metrics := CalculatePerformanceMetrics(reports)
+	initialCapital := decimal.NewFromFloat(config.InitialCapital)
+	finalValue := initialCapital
+	if len(reports) > 0 {
+		finalValue = reports[len(reports)-1].TotalValue
+	}
+
+	document := &jsonRunDocument{
+		Schema:      RunSchemaVersion,
+		RunID:       config.RunID,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Config: jsonConfig{
+			InitialCapital:       jsonFloat(config.InitialCapital),
+			StartDate:            config.StartDate.Format("2006-01-02"),
+			EndDate:              config.EndDate.Format("2006-01-02"),
+			PriceFormat:          config.PriceFormat,
+			StockPriceDir:        config.StockPriceDir,
+			SignalFormat:         config.SignalFormat,
+			HistoryDir:           config.HistoryDir,
+			SignalFile:           config.SignalFile,
+			IndexPriceDir:        config.IndexPriceDir,
+			FractionalShares:     config.FractionalShares,
+			SharePrecision:       config.SharePrecision,
+			AllocationMode:       config.AllocationMode,
+			AllocationRatio:      jsonFloat(config.AllocationRatio),
+			TargetVolatility:     jsonFloat(config.TargetVolatility),
+			VolLookback:          config.VolLookback,
+			MinExposure:          jsonFloat(config.MinExposure),
+			MaxExposure:          jsonFloat(config.MaxExposure),
+			MarginInterestRate:   jsonFloat(config.MarginInterestRate),
+			MaintenanceMargin:    jsonFloat(config.MaintenanceMargin),
+			LongShort:            config.LongShort,
+			GrossExposure:        jsonFloat(config.GrossExposure),
+			NetExposure:          jsonFloat(config.NetExposure),
+			ShortBorrowFee:       jsonFloat(config.ShortBorrowFee),
+			RegimeEnabled:        config.RegimeEnabled,
+			RegimeSymbol:         config.RegimeSymbol,
+			RegimeRule:           config.RegimeRule,
+			RegimeWindow:         config.RegimeWindow,
+			RegimeDrawdownLimit:  jsonFloat(config.RegimeDrawdownLimit),
+			RegimeRiskOffScale:   jsonFloat(config.RegimeRiskOffScale),
+			MetadataFile:         config.MetadataFile,
+			SectorCap:            jsonFloat(config.SectorCap),
+			SignalPriceTolerance: jsonFloat(config.SignalPriceTolerance),
+			SignalPriceAction:    config.SignalPriceAction,
+			SignalWeighting:      config.SignalWeighting,
+			Benchmark:            config.Benchmark,
+			TopDrawdowns:         config.TopDrawdowns,
+			OutputFormats:        config.OutputFormats,
+			MarkdownTemplate:     config.MarkdownTemplate,
+			AssetsDir:            config.AssetsDir,
+			AssetsCDN:            config.AssetsCDN,
+		},
+		Metrics: jsonMetrics{
+			InitialCapital:       jsonDecimal(initialCapital),
+			FinalValue:           jsonDecimal(finalValue),
+			TotalReturn:          jsonDecimal(metrics.TotalReturn),
+			AnnualizedReturn:     jsonDecimal(metrics.AnnualizedReturn),
+			MaxDrawdown:          jsonDecimal(metrics.MaxDrawdown),
+			Volatility:           jsonDecimal(metrics.Volatility),
+			SharpeRatio:          jsonDecimal(metrics.SharpeRatio),
+			WinRate:              jsonDecimal(metrics.WinRate),
+			AverageMonthlyReturn: jsonDecimal(metrics.AverageReturn),
+			TotalTrades:          metrics.TotalTrades,
+		},
+		MonthlyReports: make([]jsonMonthlyReport, 0, len(reports)),
+	}
+
+	for _, report := range reports {
+		document.MonthlyReports = append(document.MonthlyReports, newJSONMonthlyReport(report))
+	}
+	return document
+}
+
+// newJSONMonthlyReport 转换单个月度报告，持仓按股票代码排序，数组字段为空时输出 []
+func newJSONMonthlyReport(report *MonthlyReport) jsonMonthlyReport {
+	summary := jsonMonthSummary{
+		Date:             report.Date.Format("2006-01-02"),
+		TotalValue:       jsonDecimal(report.TotalValue),
+		Cash:             jsonDecimal(report.Cash),
+		StockValue:       jsonDecimal(report.StockValue),
+		Borrowed:         jsonDecimal(report.Borrowed),
+		InterestCharged:  jsonDecimal(report.InterestCharged),
+		Leverage:         jsonDecimal(report.Leverage),
+		LongValue:        jsonDecimal(report.LongValue),
+		ShortValue:       jsonDecimal(report.ShortValue),
+		BorrowFee:        jsonDecimal(report.BorrowFee),
+		Regime:           report.Regime,
+		AllocationRatio:  jsonDecimal(report.AllocationRatio),
+		ExAnteVolatility: jsonDecimal(report.ExAnteVolatility),
+		MonthlyReturn:    jsonDecimal(report.MonthlyReturn),
+		CumulativeReturn: jsonDecimal(report.CumulativeReturn),
+		SectorWeights:    make(map[string]json.Number, len(report.SectorWeights)),
+	}
+	for sector, weight := range report.SectorWeights {
+		summary.SectorWeights[sector] = jsonDecimal(weight)
+	}
+
+	result := jsonMonthlyReport{
+		jsonMonthSummary: summary,
+		Positions:        make([]jsonPosition, 0, len(report.Positions)),
+		TradingActions:   make([]jsonTradingAction, 0, len(report.TradingActions)),
+		SignalIssues:     make([]jsonSignalIssue, 0, len(report.SignalIssues)),
+	}
+
+	symbols := make([]string, 0, len(report.Positions))
+	for symbol := range report.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		position := report.Positions[symbol]
+		result.Positions = append(result.Positions, jsonPosition{
+			Symbol:       position.Symbol,
+			Shares:       jsonDecimal(position.Shares),
+			BuyPrice:     jsonDecimal(position.BuyPrice),
+			BuyDate:      position.BuyDate.Format("2006-01-02"),
+			CurrentPrice: jsonDecimal(position.CurrentPrice),
+			MarketValue:  jsonDecimal(position.MarketValue),
+			CostBasis:    jsonDecimal(position.CostBasis),
+			PnL:          jsonDecimal(position.PnL),
+			PnLPercent:   jsonDecimal(position.PnLPercent),
+			Weight:       jsonDecimal(position.Weight),
+		})
+	}
+
+	for _, action := range report.TradingActions {
+		result.TradingActions = append(result.TradingActions, jsonTradingAction{
+			Date:       action.Date.Format("2006-01-02"),
+			Symbol:     action.Symbol,
+			Action:     action.Action,
+			Shares:     jsonDecimal(action.Shares),
+			Price:      jsonDecimal(action.Price),
+			Amount:     jsonDecimal(action.Amount),
+			Reason:     TradeReasonText(action.Reason),
+			ReasonCode: action.Reason,
+		})
+	}
+
+	for _, issue := range report.SignalIssues {
+		result.SignalIssues = append(result.SignalIssues, jsonSignalIssue{
+			Date:        issue.Date.Format("2006-01-02"),
+			Symbol:      issue.Symbol,
+			Status:      issue.Status,
+			SignalPrice: issue.SignalPrice,
+			FilePrice:   jsonDecimal(issue.FilePrice),
+			FillPrice:   jsonDecimal(issue.FillPrice),
+			Deviation:   jsonDecimal(issue.Deviation),
+			Issue:       issue.Issue,
+			Skipped:     issue.Skipped,
+		})
+	}
+
+	return result
+}
+
+// jsonDecimal 以 JSON 数字输出 decimal，保留完整精度
+func jsonDecimal(value decimal.Decimal) json.Number {
+	return json.Number(value.String())
+}
+
+// jsonFloat 以 JSON 数字输出配置中的浮点数
+func jsonFloat(value float64) json.Number {
+	return json.Number(decimal.NewFromFloat(value).String())
+}
+
+// runResults 将 run.json 文档还原为回测结果，report、compare 等子命令据此在不重新回测的情况下还原运行
+// 较早版本的文档中没有的目录、数值和输出格式字段保留默认值
+func (document *jsonRunDocument) runResults() (*RunResults, error) {
+	source := document.Config
+	config := DefaultConfig()
+	config.RunID = document.RunID
+	config.PriceFormat = source.PriceFormat
+	config.SignalFormat = source.SignalFormat
+	config.SignalFile = source.SignalFile
+	config.FractionalShares = source.FractionalShares
+	config.SharePrecision = source.SharePrecision
+	config.AllocationMode = source.AllocationMode
+	config.LongShort = source.LongShort
+	config.RegimeEnabled = source.RegimeEnabled
+	config.RegimeSymbol = source.RegimeSymbol
+	config.RegimeRule = source.RegimeRule
+	config.RegimeWindow = source.RegimeWindow
+	config.MetadataFile = source.MetadataFile
+	config.SignalPriceAction = source.SignalPriceAction
+	config.SignalWeighting = source.SignalWeighting
+	config.Benchmark = source.Benchmark
+	config.MarkdownTemplate = source.MarkdownTemplate
+	config.AssetsDir = source.AssetsDir
+	config.AssetsCDN = source.AssetsCDN
+	config.TopDrawdowns = source.TopDrawdowns
+
+	dirs := []struct {
+		target *string
+		value  string
+	}{
+		{&config.StockPriceDir, source.StockPriceDir},
+		{&config.HistoryDir, source.HistoryDir},
+		{&config.IndexPriceDir, source.IndexPriceDir},
+	}
+	for _, field := range dirs {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if source.VolLookback > 0 {
+		config.VolLookback = source.VolLookback
+	}
+	if len(source.OutputFormats) > 0 {
+		config.OutputFormats = source.OutputFormats
+	}
+
+	var err error
+	if config.StartDate, err = time.Parse("2006-01-02", source.StartDate); err != nil {
+		return nil, err
+	}
+	if config.EndDate, err = time.Parse("2006-01-02", source.EndDate); err != nil {
+		return nil, err
+	}
+	floats := []struct {
+		target *float64
+		value  json.Number
+	}{
+		{&config.InitialCapital, source.InitialCapital},
+		{&config.AllocationRatio, source.AllocationRatio},
+		{&config.TargetVolatility, source.TargetVolatility},
+		{&config.MinExposure, source.MinExposure},
+		{&config.MaxExposure, source.MaxExposure},
+		{&config.MarginInterestRate, source.MarginInterestRate},
+		{&config.MaintenanceMargin, source.MaintenanceMargin},
+		{&config.GrossExposure, source.GrossExposure},
+		{&config.NetExposure, source.NetExposure},
+		{&config.ShortBorrowFee, source.ShortBorrowFee},
+		{&config.RegimeDrawdownLimit, source.RegimeDrawdownLimit},
+		{&config.RegimeRiskOffScale, source.RegimeRiskOffScale},
+		{&config.SectorCap, source.SectorCap},
+		{&config.SignalPriceTolerance, source.SignalPriceTolerance},
+	}
+	for _, field := range floats {
+		if field.value == "" {
+			continue
+		}
+		if *field.target, err = field.value.Float64(); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := make([]*MonthlyReport, 0, len(document.MonthlyReports))
+	for _, month := range document.MonthlyReports {
+		report, err := month.monthlyReport()
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return &RunResults{Config: config, Reports: reports}, nil
+}
+
+// monthlyReport 将 run.json 中的月度报告还原为 MonthlyReport
+func (month *jsonMonthlyReport) monthlyReport() (*MonthlyReport, error) {
+	var firstErr error
+	number := func(value json.Number) decimal.Decimal {
+		d, err := decimal.NewFromString(string(value))
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf(T("无效的数值: %s"), value)
+		}
+		return d
+	}
+	date := func(value string) time.Time {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf(T("无效的日期: %s"), value)
+		}
+		return t
+	}
+
+	report := &MonthlyReport{
+		Date:             date(month.Date),
+		TotalValue:       number(month.TotalValue),
+		Cash:             number(month.Cash),
+		StockValue:       number(month.StockValue),
+		Borrowed:         number(month.Borrowed),
+		InterestCharged:  number(month.InterestCharged),
+		Leverage:         number(month.Leverage),
+		LongValue:        number(month.LongValue),
+		ShortValue:       number(month.ShortValue),
+		BorrowFee:        number(month.BorrowFee),
+		Regime:           month.Regime,
+		AllocationRatio:  number(month.AllocationRatio),
+		ExAnteVolatility: number(month.ExAnteVolatility),
+		MonthlyReturn:    number(month.MonthlyReturn),
+		CumulativeReturn: number(month.CumulativeReturn),
+		SectorWeights:    make(map[string]decimal.Decimal, len(month.SectorWeights)),
+		Positions:        make(map[string]*Position, len(month.Positions)),
+	}
+	for sector, weight := range month.SectorWeights {
+		report.SectorWeights[sector] = number(weight)
+	}
+	for _, position := range month.Positions {
+		report.Positions[position.Symbol] = &Position{
+			Symbol:       position.Symbol,
+			Shares:       number(position.Shares),
+			BuyPrice:     number(position.BuyPrice),
+			BuyDate:      date(position.BuyDate),
+			CurrentPrice: number(position.CurrentPrice),
+			MarketValue:  number(position.MarketValue),
+			CostBasis:    number(position.CostBasis),
+			PnL:          number(position.PnL),
+			PnLPercent:   number(position.PnLPercent),
+			Weight:       number(position.Weight),
+		}
+	}
+	for _, action := range month.TradingActions {
+		// 较早版本的文档中没有 reason_code，只有中文描述
+		reason := action.ReasonCode
+		if reason == "" {
+			reason = action.Reason
+		}
+		report.TradingActions = append(report.TradingActions, TradingAction{
+			Date:   date(action.Date),
+			Symbol: action.Symbol,
+			Action: action.Action,
+			Shares: number(action.Shares),
+			Price:  number(action.Price),
+			Amount: number(action.Amount),
+			Reason: reason,
+		})
+	}
+	for _, issue := range month.SignalIssues {
+		report.SignalIssues = append(report.SignalIssues, SignalIssue{
+			Date:        date(issue.Date),
+			Symbol:      issue.Symbol,
+			Status:      issue.Status,
+			SignalPrice: issue.SignalPrice,
+			FilePrice:   number(issue.FilePrice),
+			FillPrice:   number(issue.FillPrice),
+			Deviation:   number(issue.Deviation),
+			Issue:       issue.Issue,
+			Skipped:     issue.Skipped,
+		})
+	}
+	return report, firstErr
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// 日志格式
+const (
+	LogFormatText = "text" // key=value 文本
+	LogFormatJSON = "json" // 每行一个 JSON 对象
+)
+
+// logOptions 各命令共用的日志参数
+// 进度、交易和警告写入日志（默认 stderr），标准输出只保留配置信息和执行汇总，便于管道处理
+type logOptions struct {
+	level  *string
+	format *string
+	file   *string
+}
+
+// addLogFlags 在命令参数中注册 -log-level、-log-format 和 -log-file
+func addLogFlags(fs *flag.FlagSet) *logOptions {
+	return &logOptions{
+		level:  fs.String("log-level", "info", "Log level: debug, info, warn or error"),
+		format: fs.String("log-format", LogFormatText, "Log format: text or json"),
+		file:   fs.String("log-file", "", "Append logs to this file instead of stderr"),
+	}
+}
+
+// apply 按参数设置默认日志，参数无效时直接退出；返回的函数在程序结束前调用以关闭日志文件
+func (options *logOptions) apply() func() {
+	closeLog, err := setupLogging(*options.level, *options.format, *options.file)
+	if err != nil {
+		log.Fatalf(T("Invalid logging options: %v"), err)
+	}
+	return closeLog
+}
+
+// setupLogging 创建 slog 日志处理器并设为默认日志
+// log 包仍直接写 stderr，保证启动失败等致命错误不受日志级别和日志文件影响
+func setupLogging(level, format, file string) (func(), error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf(T("不支持的日志级别: %s"), level)
+	}
+
+	var writer io.Writer = os.Stderr
+	closeLog := func() {}
+	if file != "" {
+		logFile, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf(T("无法打开日志文件 %s: %v"), file, err)
+		}
+		writer = logFile
+		closeLog = func() { logFile.Close() }
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: logLevel}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case LogFormatText:
+		handler = slog.NewTextHandler(writer, handlerOptions)
+	case LogFormatJSON:
+		handler = slog.NewJSONHandler(writer, handlerOptions)
+	default:
+		closeLog()
+		return nil, fmt.Errorf(T("不支持的日志格式: %s"), format)
+	}
+
+	// SetDefault 会把 log 包的输出转到 slog，这里恢复为 stderr
+	slog.SetDefault(slog.New(handler))
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags)
+
+	return closeLog, nil
+}
+
+// logTrade 记录一笔交易
+func logTrade(action *TradingAction) {
+	slog.Info(T("交易"),
+		"date", action.Date.Format("2006-01-02"),
+		"action", action.Action,
+		"symbol", action.Symbol,
+		"shares", action.Shares,
+		"price", action.Price,
+		"amount", action.Amount,
+		"reason", TradeReasonText(action.Reason))
+}
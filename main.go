@@ -4,94 +4,200 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 func main() {
-	// 命令行参数
-	var (
-		initialCapital = flag.Float64("capital", 100000, "Initial capital in USD")
-		startDate      = flag.String("start", "20230101", "Start date (YYYYMMDD)")
-		endDate        = flag.String("end", "20250831", "End date (YYYYMMDD)")
-		stockPriceDir  = flag.String("stock-dir", "stock_price", "Stock price data directory")
-		historyDir     = flag.String("history-dir", "history", "Trading history directory")
-		outputDir      = flag.String("output-dir", "output", "Output directory")
-	)
-	flag.Parse()
-
-	// 解析日期
-	startTime, err := time.Parse("20060102", *startDate)
-	if err != nil {
-		log.Fatalf("Invalid start date format: %v", err)
+	// 不带子命令（或第一个参数为选项）时执行 run，兼容原有用法
+	name, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
-	endTime, err := time.Parse("20060102", *endDate)
-	if err != nil {
-		log.Fatalf("Invalid end date format: %v", err)
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+		printUsage(os.Stderr)
+		os.Exit(2)
 	}
+	cmd.run(args)
+}
+
+// runBacktest 执行 run 子命令：运行回测，生成报告和图表，并将结果保存到输出目录
+func runBacktest(args []string) {
+	fs := newCommandFlagSet("run")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	options := addRunFlags(fs)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
 
-	// 创建配置
-	config := &Config{
-		InitialCapital: *initialCapital,
-		StartDate:      startTime,
-		EndDate:        endTime,
-		StockPriceDir:  *stockPriceDir,
-		HistoryDir:     *historyDir,
-		OutputDir:      *outputDir,
-		ChartsDir:      filepath.Join(*outputDir, "charts"),
-		ReportsDir:     filepath.Join(*outputDir, "reports"),
+	if fs.NArg() > 0 {
+		log.Fatalf(T("Unexpected arguments: %s"), strings.Join(fs.Args(), " "))
+	}
+	config, err := options.config()
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	// 创建输出目录
 	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+		log.Fatalf(T("Failed to create output directory: %v"), err)
 	}
 
-	fmt.Printf("=== Tech Titans Quantitative Investment Strategy Analysis ===\n")
-	fmt.Printf("Initial Capital: $%.2f\n", config.InitialCapital)
-	fmt.Printf("Analysis Period: %s - %s\n", config.StartDate, config.EndDate)
-	fmt.Printf("Stock Price Directory: %s\n", config.StockPriceDir)
-	fmt.Printf("History Directory: %s\n", config.HistoryDir)
-	fmt.Printf("Output Directory: %s\n", config.OutputDir)
+	fmt.Print(T("=== Tech Titans Quantitative Investment Strategy Analysis ===\n"))
+	fmt.Printf(T("Initial Capital: $%.2f\n"), config.InitialCapital)
+	fmt.Printf(T("Analysis Period: %s - %s\n"), config.StartDate, config.EndDate)
+	fmt.Printf(T("Stock Price Directory: %s\n"), config.StockPriceDir)
+	if config.SignalFormat == SignalFormatCSVDir {
+		fmt.Printf(T("History Directory: %s\n"), config.HistoryDir)
+	} else {
+		fmt.Printf(T("Signal File: %s (%s)\n"), config.SignalFile, config.SignalFormat)
+	}
+	fmt.Printf(T("Output Directory: %s\n"), config.OutputDir)
+	if config.LongShort {
+		fmt.Printf(T("Long/Short: gross %.2f, net %.2f, borrow fee %.2f%%\n"), config.GrossExposure, config.NetExposure, config.ShortBorrowFee*100)
+	} else if config.AllocationMode == AllocationModeVolTarget {
+		fmt.Printf(T("Volatility Target: %.2f%% (exposure %.2f - %.2f, lookback %d days)\n"), config.TargetVolatility*100, config.MinExposure, config.MaxExposure, config.VolLookback)
+	} else {
+		fmt.Printf(T("Allocation Ratio: %.2f\n"), config.AllocationRatio)
+	}
+	if config.AllocationRatio > 1 {
+		fmt.Printf(T("Margin: rate %.2f%%, maintenance %.2f%%\n"), config.MarginInterestRate*100, config.MaintenanceMargin*100)
+	}
+	if config.RegimeEnabled {
+		fmt.Printf(T("Regime Filter: %s %s(%d), risk-off scale %.2f\n"), config.RegimeSymbol, config.RegimeRule, config.RegimeWindow, config.RegimeRiskOffScale)
+	}
+	if config.SectorCap > 0 {
+		fmt.Printf(T("Sector Cap: %.2f%%\n"), config.SectorCap*100)
+	}
+	if config.SignalWeighting == SignalWeightingPL {
+		fmt.Print(T("Weighting: pl score\n"))
+	}
+	if config.SignalPriceAction == SignalPriceActionSkip && config.SignalPriceTolerance > 0 {
+		fmt.Printf(T("Signal Price Check: skip buys filling %.2f%% above signal price\n"), config.SignalPriceTolerance*100)
+	}
+	if config.FractionalShares {
+		fmt.Printf(T("Fractional Shares: enabled (precision %d)\n"), config.SharePrecision)
+	}
 	fmt.Println()
 
 	// 初始化数据加载器
-	dataLoader := NewStockDataLoader(config.StockPriceDir, config.HistoryDir)
+	dataLoader, err := newDataLoader(config)
+	if err != nil {
+		log.Fatalf(T("Invalid data source: %v"), err)
+	}
 
 	// 初始化交易策略
 	strategy := NewTradingStrategy(dataLoader, config)
 
 	// 执行策略
-	fmt.Println("Executing trading strategy...")
+	slog.Info(T("Executing trading strategy"))
 	start := time.Now()
 	reports, err := strategy.ExecuteStrategy()
 	if err != nil {
-		log.Fatalf("Strategy execution failed: %v", err)
+		log.Fatalf(T("Strategy execution failed: %v"), err)
 	}
 	executionTime := time.Since(start)
-	fmt.Printf("Strategy execution completed in %v\n\n", executionTime)
+	slog.Info(T("Strategy execution completed"), "elapsed", executionTime)
+
+	// 生成报告和图表
+	writeRunOutputs(config, reports)
+	if len(reports) > 0 {
+		if err := SaveRunResults(config, reports); err != nil {
+			slog.Error(T("Failed to save run results"), "err", err)
+		}
+	}
+
+	// 保存回测结果
+	if config.StorePath != "" && len(reports) > 0 {
+		store, err := OpenStore(config.StorePath)
+		if err != nil {
+			slog.Error(T("Failed to open database"), "err", err)
+		} else {
+			if err := store.SaveRun(config.RunID, config, reports); err != nil {
+				slog.Error(T("Failed to save run"), "err", err)
+			} else {
+				slog.Info(T("Run saved"), "db", config.StorePath, "run_id", config.RunID)
+			}
+			store.Close()
+		}
+	}
 
-	// 生成报告
-	fmt.Println("Generating reports...")
+	fmt.Print(T("\n=== Analysis Complete ===\n"))
+	fmt.Printf(T("Total execution time: %v\n"), time.Since(start))
+	fmt.Printf(T("Reports and charts saved to: %s\n"), config.OutputDir)
+	printGeneratedFiles(config.OutputDir)
+}
+
+// writeRunOutputs 按配置的输出格式生成报告、控制台摘要、图表和单页报告
+func writeRunOutputs(config *Config, reports []*MonthlyReport) {
+	slog.Info(T("Generating reports"))
 	reportGenerator := NewReportGenerator(config)
 
 	// 生成月度报告
-	for _, report := range reports {
-		if err := reportGenerator.GenerateMonthlyReport(report); err != nil {
-			log.Printf("Failed to generate monthly report for %s: %v", report.Date.Format("2006-01"), err)
+	if config.HasOutputFormat(OutputFormatCSV) {
+		for _, report := range reports {
+			if err := reportGenerator.GenerateMonthlyReport(report); err != nil {
+				slog.Error(T("Failed to generate monthly report"), "month", report.Date.Format("2006-01"), "err", err)
+			}
 		}
 	}
 
 	// 生成最终报告
 	if len(reports) > 0 {
-		finalReport := reports[len(reports)-1]
-		if err := reportGenerator.generateFinalPositionReport([]*MonthlyReport{finalReport}); err != nil {
-			log.Printf("Failed to generate final position report: %v", err)
+		if config.HasOutputFormat(OutputFormatCSV) {
+			finalReport := reports[len(reports)-1]
+			if err := reportGenerator.generateFinalPositionReport([]*MonthlyReport{finalReport}); err != nil {
+				slog.Error(T("Failed to generate final position report"), "err", err)
+			}
+
+			if err := reportGenerator.generatePerformanceSummary(reports); err != nil {
+				slog.Error(T("Failed to generate performance summary"), "err", err)
+			}
+
+			if err := reportGenerator.GenerateDrawdownReport(reports); err != nil {
+				slog.Error(T("Failed to generate drawdown report"), "err", err)
+			}
+
+			if err := reportGenerator.GenerateMonthlyReturnsTable(reports); err != nil {
+				slog.Error(T("Failed to generate monthly returns table"), "err", err)
+			}
+
+			if err := reportGenerator.GenerateAttributionReport(reports); err != nil {
+				slog.Error(T("Failed to generate attribution report"), "err", err)
+			}
+
+			if config.SignalPriceTolerance > 0 {
+				if err := reportGenerator.GenerateSignalValidationReport(reports); err != nil {
+					slog.Error(T("Failed to generate signal validation report"), "err", err)
+				}
+			}
 		}
 
-		if err := reportGenerator.generatePerformanceSummary(reports); err != nil {
-			log.Printf("Failed to generate performance summary: %v", err)
+		if config.HasOutputFormat(OutputFormatJSONL) {
+			if err := reportGenerator.GenerateJSONLReport(reports); err != nil {
+				slog.Error(T("Failed to generate JSONL report"), "err", err)
+			}
+		}
+
+		if config.HasOutputFormat(OutputFormatXLSX) {
+			if err := reportGenerator.GenerateExcelReport(reports); err != nil {
+				slog.Error(T("Failed to generate Excel report"), "err", err)
+			}
+		}
+
+		if err := reportGenerator.GenerateMarkdownSummary(reports); err != nil {
+			slog.Error(T("Failed to generate markdown summary"), "err", err)
 		}
 
 		// 打印控制台摘要
@@ -99,28 +205,884 @@ func main() {
 	}
 
 	// 生成图表
-	fmt.Println("\nGenerating charts...")
+	slog.Info(T("Generating charts"))
 	chartGenerator := NewChartGenerator(config)
 	if err := chartGenerator.GenerateAllCharts(reports); err != nil {
-		log.Printf("Failed to generate charts: %v", err)
-	} else {
-		fmt.Println("Charts generated successfully")
+		slog.Error(T("Failed to generate charts"), "err", err)
 	}
+	if err := chartGenerator.GenerateTearSheet(reports); err != nil {
+		slog.Error(T("Failed to generate tear sheet"), "err", err)
+	}
+}
 
-	fmt.Printf("\n=== Analysis Complete ===\n")
-	fmt.Printf("Total execution time: %v\n", time.Since(start))
-	fmt.Printf("Reports and charts saved to: %s\n", config.OutputDir)
-
-	// 列出生成的文件
-	fmt.Println("\nGenerated files:")
-	filepath.Walk(config.OutputDir, func(path string, info os.FileInfo, err error) error {
+// printGeneratedFiles 列出输出目录中的文件
+func printGeneratedFiles(dir string) {
+	fmt.Println(T("\nGenerated files:"))
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 		if !info.IsDir() {
-			relPath, _ := filepath.Rel(config.OutputDir, path)
+			relPath, _ := filepath.Rel(dir, path)
 			fmt.Printf("  - %s\n", relPath)
 		}
 		return nil
 	})
-}
\ No newline at end of file
+}
+
+// runValidate 执行 validate 子命令：检查配置和输入数据，不运行回测；有错误（-strict 时含警告）时退出码为 1
+func runValidate(args []string) {
+	fs := newCommandFlagSet("validate")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	options := addRunFlags(fs)
+	strict := fs.Bool("strict", false, "Treat warnings as errors")
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	config, err := options.config()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	issues, err := ValidateInputs(config)
+	if err != nil {
+		log.Fatalf(T("Invalid data source: %v"), err)
+	}
+	errorCount, warningCount := PrintValidationIssues(os.Stdout, issues)
+	if errorCount > 0 || (*strict && warningCount > 0) {
+		os.Exit(1)
+	}
+}
+
+// runCompare 执行 compare 子命令：对比多个运行目录中保存的回测结果
+func runCompare(args []string) {
+	fs := newCommandFlagSet("compare")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	months := fs.String("months", "", "Comma-separated months (YYYY-MM) to compare holdings at (default: last common month)")
+	output := fs.String("o", "", "Write a diff report with overlaid equity curves (.md or .html)")
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if fs.NArg() < 2 {
+		log.Fatal(T("Usage: tech-titans compare [flags] RUN_DIR[=LABEL] RUN_DIR[=LABEL] ..."))
+	}
+
+	var runs []*ComparedRun
+	for _, arg := range fs.Args() {
+		run, err := LoadComparedRun(arg)
+		if err != nil {
+			log.Fatalf(T("Failed to load run: %v"), err)
+		}
+		runs = append(runs, run)
+	}
+
+	var holdingMonths []string
+	for _, month := range strings.Split(*months, ",") {
+		if month = strings.TrimSpace(month); month != "" {
+			holdingMonths = append(holdingMonths, month)
+		}
+	}
+	var diffs []*RunDiff
+	for _, run := range runs[1:] {
+		diff, err := NewRunDiff(runs[0], run, holdingMonths)
+		if err != nil {
+			log.Fatalf(T("Failed to compare runs: %v"), err)
+		}
+		diffs = append(diffs, diff)
+	}
+
+	PrintRunComparison(os.Stdout, runs)
+	for _, diff := range diffs {
+		PrintRunDiff(os.Stdout, diff)
+	}
+
+	if *output != "" {
+		if err := WriteComparisonReport(*output, runs, diffs); err != nil {
+			log.Fatalf(T("Failed to write comparison report: %v"), err)
+		}
+		fmt.Printf(T("\nComparison report written to %s\n"), *output)
+	}
+}
+
+// runSweep 执行 sweep 子命令：按 -param 给出的取值组合逐一回测，输出 sweep.csv，
+// 每个组合的结果保存在 输出目录/sweep/序号 下，可用 report、compare 子命令查看
+func runSweep(args []string) {
+	fs := newCommandFlagSet("sweep")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	options := addRunFlags(fs)
+	var params sweepParams
+	fs.Var(&params, "param", "Parameter values to sweep as name=v1,v2,... using run flag names (repeatable)")
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if len(params) == 0 {
+		log.Fatal(T("Usage: tech-titans sweep [flags] -param name=v1,v2,... [-param ...]"))
+	}
+	for _, param := range params {
+		if fs.Lookup(param.Name) == nil || sweepExcludedFlags[param.Name] {
+			log.Fatalf(T("Cannot sweep parameter: %s"), param.Name)
+		}
+	}
+
+	baseRunID := *options.runID
+	if baseRunID == "" {
+		baseRunID = time.Now().Format("20060102-150405")
+	}
+
+	combinations := params.combinations()
+	var results []*SweepResult
+	for i, values := range combinations {
+		for j, param := range params {
+			if err := fs.Set(param.Name, values[j]); err != nil {
+				log.Fatalf(T("Invalid value for %s: %v"), param.Name, err)
+			}
+		}
+		config, err := options.config()
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.RunID = fmt.Sprintf("%s-%03d", baseRunID, i+1)
+		config.OutputDir = sweepRunDir(*options.outputDir, i+1)
+		config.ChartsDir = filepath.Join(config.OutputDir, "charts")
+		config.ReportsDir = filepath.Join(config.OutputDir, "reports")
+
+		slog.Info(T("Running sweep combination"), "run", i+1, "of", len(combinations), "params", strings.Join(values, ","))
+		dataLoader, err := newDataLoader(config)
+		if err != nil {
+			log.Fatalf(T("Invalid data source: %v"), err)
+		}
+		reports, err := NewTradingStrategy(dataLoader, config).ExecuteStrategy()
+		if err != nil || len(reports) == 0 {
+			slog.Error(T("Sweep combination failed"), "run", i+1, "err", err)
+			continue
+		}
+
+		if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+			log.Fatalf(T("Failed to create output directory: %v"), err)
+		}
+		if err := SaveRunResults(config, reports); err != nil {
+			slog.Error(T("Failed to save run results"), "err", err)
+		}
+		results = append(results, &SweepResult{
+			Index:      i + 1,
+			Values:     values,
+			RunDir:     config.OutputDir,
+			Metrics:    CalculatePerformanceMetrics(reports),
+			FinalValue: reports[len(reports)-1].TotalValue,
+		})
+	}
+
+	PrintSweepResults(os.Stdout, params, results)
+	filePath, err := WriteSweepReport(*options.outputDir, params, results)
+	if err != nil {
+		log.Fatalf(T("Failed to write sweep report: %v"), err)
+	}
+	fmt.Printf(T("\nSweep report written to %s\n"), filePath)
+}
+
+// runReport 执行 report 子命令：从运行目录中保存的结果重新生成报告和图表，不重新回测
+func runReport(args []string) {
+	fs := newCommandFlagSet("report")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		outputDir    = fs.String("output-dir", "", "Output directory (default: the run directory)")
+		outputFormat = fs.String("output-format", "", "Comma-separated report formats: csv, json, jsonl, xlsx (default: formats of the saved run)")
+		mdTemplate   = fs.String("md-template", "", "Custom text/template file for summary.md (default: template of the saved run)")
+		assetsDir    = fs.String("assets-dir", "", "Local go-echarts assets directory to inline into report.html (default: setting of the saved run)")
+		assetsCDN    = fs.Bool("assets-cdn", false, "Load go-echarts scripts in report.html from the CDN instead of inlining them (default: setting of the saved run)")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if fs.NArg() != 1 {
+		log.Fatal(T("Usage: tech-titans report [flags] RUN_DIR"))
+	}
+	results, err := LoadRunResults(fs.Arg(0))
+	if err != nil {
+		log.Fatalf(T("Failed to load run: %v"), err)
+	}
+
+	config := results.Config
+	if *outputDir != "" {
+		config.OutputDir = *outputDir
+		config.ChartsDir = filepath.Join(*outputDir, "charts")
+		config.ReportsDir = filepath.Join(*outputDir, "reports")
+		if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+			log.Fatalf(T("Failed to create output directory: %v"), err)
+		}
+	}
+	if *outputFormat != "" {
+		if config.OutputFormats, err = parseOutputFormats(*outputFormat); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *mdTemplate != "" {
+		config.MarkdownTemplate = *mdTemplate
+	}
+	if *assetsDir != "" {
+		config.AssetsDir = *assetsDir
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "assets-cdn" {
+			config.AssetsCDN = *assetsCDN
+		}
+	})
+
+	writeRunOutputs(config, results.Reports)
+	if *outputDir != "" {
+		if err := SaveRunResults(config, results.Reports); err != nil {
+			slog.Error(T("Failed to save run results"), "err", err)
+		}
+	}
+
+	fmt.Printf(T("Reports and charts saved to: %s\n"), config.OutputDir)
+	printGeneratedFiles(config.OutputDir)
+}
+
+// runOrders 执行 orders 子命令：输出已保存运行中某次调仓的订单；
+// 指定 -positions 时根据当前持仓和最新交易信号生成下一交易日的订单，不运行完整回测
+func runOrders(args []string) {
+	fs := newCommandFlagSet("orders")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	options := addRunFlags(fs)
+	var (
+		month         = fs.String("month", "", "Rebalance month (YYYY-MM) of a saved run (default: last month with trades)")
+		format        = fs.String("format", "table", "Output format: table, csv, ib-basket, fix42 or alpaca-json")
+		outputPath    = fs.String("o", "", "Output file (default: stdout; with -positions: OUTPUT_DIR/orders_YYYYMMDD.csv)")
+		positionsFile = fs.String("positions", "", "Current holdings CSV (symbol,shares[,buy_price,buy_date,cost_basis]); generates orders for the next trading day instead of reading RUN_DIR")
+		cashFlag      = fs.Float64("cash", 0, "Cash balance with -positions (overrides a CASH row in the holdings CSV)")
+		signalDate    = fs.String("signal-date", "", "Signal date YYYYMMDD with -positions (default: latest signal date)")
+		tradeDate     = fs.String("trade-date", "", "Trading day YYYYMMDD with -positions (default: first NYSE trading day on or after the signal date)")
+		dryRun        = fs.Bool("dry-run", false, "With -positions: print the orders and the resulting target portfolio without writing files")
+		orderType     = fs.String("order-type", "market", "Broker order type: market or limit (limit price: rounded trade price)")
+		timeInForce   = fs.String("tif", "day", "Broker time in force: day or gtc")
+		account       = fs.String("account", "", "Broker account written to ib-basket and fix42 files")
+		orderPrefix   = fs.String("order-prefix", "tt", "Client order ID prefix")
+		fixSender     = fs.String("fix-sender", "TECHTITANS", "FIX SenderCompID")
+		fixTarget     = fs.String("fix-target", "BROKER", "FIX TargetCompID")
+		checkFile     = fs.String("check", "", "Parse and validate an existing broker order file of -format instead of generating orders")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if *format != "table" && *format != "csv" && !IsBrokerFormat(*format) {
+		log.Fatalf(T("Invalid orders format: %s"), *format)
+	}
+	broker := BrokerOptions{
+		OrderType:    *orderType,
+		TimeInForce:  *timeInForce,
+		Account:      *account,
+		OrderPrefix:  *orderPrefix,
+		SenderCompID: *fixSender,
+		TargetCompID: *fixTarget,
+	}
+
+	if *checkFile != "" {
+		checkBrokerOrders(*checkFile, *format, broker)
+		return
+	}
+
+	if *positionsFile != "" {
+		if fs.NArg() != 0 {
+			log.Fatalf(T("Unexpected arguments: %s"), strings.Join(fs.Args(), " "))
+		}
+		formatSet, cashSet := false, false
+		fs.Visit(func(f *flag.Flag) {
+			formatSet = formatSet || f.Name == "format"
+			cashSet = cashSet || f.Name == "cash"
+		})
+		config, err := options.config()
+		if err != nil {
+			log.Fatal(err)
+		}
+		liveOrders(config, liveOrderOptions{
+			positionsFile: *positionsFile,
+			cash:          *cashFlag,
+			cashSet:       cashSet,
+			signalDate:    *signalDate,
+			tradeDate:     *tradeDate,
+			dryRun:        *dryRun,
+			outputPath:    *outputPath,
+			format:        *format,
+			formatSet:     formatSet,
+			broker:        broker,
+		})
+		return
+	}
+
+	if fs.NArg() != 1 {
+		log.Fatal(T("Usage: tech-titans orders [flags] RUN_DIR"))
+	}
+	results, err := LoadRunResults(fs.Arg(0))
+	if err != nil {
+		log.Fatalf(T("Failed to load run: %v"), err)
+	}
+	date, actions, err := RunOrders(results.Reports, *month)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeOrders(*outputPath, *format, date.Format("2006-01"), date.IsZero(), actions, broker)
+}
+
+// liveOrderOptions orders -positions 的参数
+type liveOrderOptions struct {
+	positionsFile string
+	cash          float64
+	cashSet       bool
+	signalDate    string
+	tradeDate     string
+	dryRun        bool
+	outputPath    string
+	format        string
+	formatSet     bool
+	broker        BrokerOptions
+}
+
+// liveOrders 根据当前持仓和交易信号生成下一交易日的订单；dry-run 时同时输出目标组合，不写文件
+func liveOrders(config *Config, options liveOrderOptions) {
+	positions, cash, err := LoadHoldings(options.positionsFile)
+	if err != nil {
+		log.Fatalf(T("Failed to load holdings: %v"), err)
+	}
+	if options.cashSet {
+		cash = decimal.NewFromFloat(options.cash)
+	}
+
+	dataLoader, err := newDataLoader(config)
+	if err != nil {
+		log.Fatalf(T("Invalid data source: %v"), err)
+	}
+
+	var signal time.Time
+	if options.signalDate != "" {
+		if signal, err = time.Parse("20060102", options.signalDate); err != nil {
+			log.Fatalf(T("Invalid signal date: %v"), err)
+		}
+	} else if signal, err = LatestSignalDate(dataLoader); err != nil {
+		log.Fatalf(T("Failed to find latest signal date: %v"), err)
+	}
+	trade := NextTradingDay(signal)
+	if options.tradeDate != "" {
+		if trade, err = time.Parse("20060102", options.tradeDate); err != nil {
+			log.Fatalf(T("Invalid trade date: %v"), err)
+		}
+	}
+
+	report, err := GenerateLiveOrders(config, dataLoader, positions, cash, signal, trade)
+	if err != nil {
+		log.Fatalf(T("Failed to generate orders: %v"), err)
+	}
+	title := fmt.Sprintf(T("%s (signals %s, estimated at the last close)"),
+		trade.Format("2006-01-02"), signal.Format("2006-01-02"))
+
+	fmt.Printf(T("Orders for %s (%d)\n\n"), title, len(report.TradingActions))
+	PrintOrders(os.Stdout, report.TradingActions)
+	if options.dryRun {
+		fmt.Print(T("\nTarget portfolio\n\n"))
+		PrintTargetPortfolio(os.Stdout, report)
+		return
+	}
+
+	// 订单文件默认为 CSV，写入输出目录
+	outputPath, format := options.outputPath, options.format
+	if !options.formatSet {
+		format = "csv"
+	}
+	if outputPath == "" {
+		if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+			log.Fatalf(T("Failed to create output file: %v"), err)
+		}
+		outputPath = filepath.Join(config.OutputDir, "orders_"+trade.Format("20060102")+ordersFileExt(format))
+	}
+	fmt.Println()
+	writeOrders(outputPath, format, title, false, report.TradingActions, options.broker)
+}
+
+// ordersFileExt 订单文件的默认扩展名
+func ordersFileExt(format string) string {
+	switch format {
+	case BrokerFormatFIX42:
+		return ".fix"
+	case BrokerFormatAlpacaJSON:
+		return ".json"
+	default:
+		return ".csv"
+	}
+}
+
+// writeOrders 以表格、CSV 或券商订单文件输出订单，outputPath 为空时输出到标准输出
+func writeOrders(outputPath, format, title string, untitled bool, actions []TradingAction, broker BrokerOptions) {
+	// 先生成并校验券商订单，避免校验失败时留下空文件
+	var exporter BrokerExporter
+	var orders []BrokerOrder
+	if IsBrokerFormat(format) {
+		var err error
+		if exporter, err = NewBrokerExporter(format, broker); err != nil {
+			log.Fatal(err)
+		}
+		if orders, err = NewBrokerOrders(actions, broker); err != nil {
+			log.Fatalf(T("Invalid broker orders: %v"), err)
+		}
+		if err := exporter.Validate(orders); err != nil {
+			log.Fatalf(T("Invalid broker orders: %v"), err)
+		}
+	}
+
+	output := os.Stdout
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			log.Fatalf(T("Failed to create output file: %v"), err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	if exporter != nil {
+		if err := ExportBrokerOrders(output, exporter, orders); err != nil {
+			log.Fatalf(T("Failed to write orders: %v"), err)
+		}
+	} else if format == "csv" {
+		if err := WriteOrdersCSV(output, actions); err != nil {
+			log.Fatalf(T("Failed to write orders: %v"), err)
+		}
+	} else {
+		if !untitled {
+			fmt.Fprintf(output, T("Orders for %s (%d)\n\n"), title, len(actions))
+		}
+		PrintOrders(output, actions)
+	}
+	if outputPath != "" {
+		fmt.Printf(T("Orders written to %s\n"), outputPath)
+	}
+}
+
+// checkBrokerOrders 解析并校验已有的券商订单文件，输出其中的订单
+func checkBrokerOrders(filePath, format string, broker BrokerOptions) {
+	if !IsBrokerFormat(format) {
+		log.Fatalf(T("-check requires a broker format (ib-basket, fix42 or alpaca-json): %s"), format)
+	}
+	exporter, err := NewBrokerExporter(format, broker)
+	if err != nil {
+		log.Fatal(err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Fatalf(T("Failed to open order file: %v"), err)
+	}
+	defer file.Close()
+
+	orders, err := exporter.Parse(file)
+	if err != nil {
+		log.Fatalf(T("Failed to parse order file: %v"), err)
+	}
+	if err := exporter.Validate(orders); err != nil {
+		log.Fatalf(T("Invalid broker orders: %v"), err)
+	}
+	fmt.Printf(T("%s: %d valid %s orders\n\n"), filePath, len(orders), format)
+	PrintBrokerOrders(os.Stdout, orders)
+}
+
+// runServe 执行 serve 子命令：通过 HTTP 浏览目录下各运行的报告和图表
+func runServe(args []string) {
+	fs := newCommandFlagSet("serve")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	addr := fs.String("addr", "127.0.0.1:8080", "Listen address")
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if fs.NArg() > 1 {
+		log.Fatal(T("Usage: tech-titans serve [flags] [DIR]"))
+	}
+	dir := "output"
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		log.Fatalf(T("Not a directory: %s"), dir)
+	}
+
+	fmt.Printf(T("Serving %s on http://%s/\n"), dir, *addr)
+	if err := http.ListenAndServe(*addr, NewRunServer(dir)); err != nil {
+		log.Fatalf(T("Server failed: %v"), err)
+	}
+}
+
+// runEventStudy 执行 eventstudy 子命令：统计纳入/剔除信号之后的前瞻收益和超额收益
+func runEventStudy(args []string) {
+	fs := newCommandFlagSet("eventstudy")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		startDate     = fs.String("start", "20230101", "Start date (YYYYMMDD)")
+		endDate       = fs.String("end", "20250831", "End date (YYYYMMDD)")
+		stockPriceDir = fs.String("stock-dir", "stock_price", "Stock price data directory (file for long-csv)")
+		priceFormat   = fs.String("price-format", "yahoo-csv", "Stock price format: yahoo-csv, iso-csv, json, long-csv or sqlite")
+		historyDir    = fs.String("history-dir", "history", "Trading history directory")
+		signalFormat  = fs.String("signal-format", "csv-dir", "Signal source format: csv-dir, long-csv, json or sqlite")
+		signalFile    = fs.String("signal-file", "", "Signal file for long-csv / json formats")
+		dbPath        = fs.String("db", "", "SQLite database for sqlite formats")
+		indexDir      = fs.String("index-dir", "all_time_stock_price", "Index price data directory")
+		benchmark     = fs.String("benchmark", "SPY", "Benchmark symbol for excess returns")
+		outputDir     = fs.String("output-dir", "output", "Output directory")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	startTime, err := time.Parse("20060102", *startDate)
+	if err != nil {
+		log.Fatalf(T("Invalid start date format: %v"), err)
+	}
+	endTime, err := time.Parse("20060102", *endDate)
+	if err != nil {
+		log.Fatalf(T("Invalid end date format: %v"), err)
+	}
+
+	config := DefaultConfig()
+	config.StartDate = startTime
+	config.EndDate = endTime
+	config.StockPriceDir = *stockPriceDir
+	config.PriceFormat = *priceFormat
+	config.HistoryDir = *historyDir
+	config.SignalFormat = *signalFormat
+	config.SignalFile = *signalFile
+	config.StorePath = *dbPath
+	config.IndexPriceDir = *indexDir
+	config.OutputDir = *outputDir
+	config.ChartsDir = filepath.Join(*outputDir, "charts")
+	config.ReportsDir = filepath.Join(*outputDir, "reports")
+
+	fmt.Print(T("=== Signal Event Study ===\n"))
+	fmt.Printf(T("Analysis Period: %s - %s\n"), config.StartDate, config.EndDate)
+	fmt.Printf(T("Benchmark: %s\n\n"), *benchmark)
+
+	dataLoader, err := newDataLoader(config)
+	if err != nil {
+		log.Fatalf(T("Invalid data source: %v"), err)
+	}
+	study := NewEventStudy(dataLoader, config, *benchmark)
+	result, err := study.Run()
+	if err != nil {
+		log.Fatalf(T("Event study failed: %v"), err)
+	}
+
+	if err := study.WriteReports(result); err != nil {
+		slog.Error(T("Failed to write event study reports"), "err", err)
+	}
+	study.PrintSummary(result)
+
+	chartGenerator := NewChartGenerator(config)
+	if err := chartGenerator.GenerateEventStudyChart(result); err != nil {
+		slog.Error(T("Failed to generate event study chart"), "err", err)
+	}
+
+	fmt.Print(T("\n=== Event Study Complete ===\n"))
+}
+
+// runConvert 执行 convert 子命令：将股价数据转换为指定格式
+func runConvert(args []string) {
+	fs := newCommandFlagSet("convert")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		fromPath   = fs.String("from", "stock_price", "Source price directory (file for long-csv)")
+		fromFormat = fs.String("from-format", "yahoo-csv", "Source format: yahoo-csv, iso-csv, json or long-csv")
+		toPath     = fs.String("to", "", "Target price directory (file for long-csv)")
+		toFormat   = fs.String("to-format", "iso-csv", "Target format: yahoo-csv, iso-csv, json or long-csv")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if *toPath == "" {
+		log.Fatal(T("Missing -to target path"))
+	}
+
+	source, err := NewPriceSource(*fromFormat, *fromPath)
+	if err != nil {
+		log.Fatalf(T("Invalid source: %v"), err)
+	}
+	writer, err := NewPriceWriter(*toFormat, *toPath)
+	if err != nil {
+		log.Fatalf(T("Invalid target: %v"), err)
+	}
+
+	converted, err := ConvertPrices(source, writer)
+	if err != nil {
+		log.Fatalf(T("Convert failed: %v"), err)
+	}
+	fmt.Printf(T("Converted %d symbols from %s (%s) to %s (%s)\n"), converted, *fromPath, *fromFormat, *toPath, *toFormat)
+}
+
+// newDataLoader 根据配置创建数据加载器及其股价和信号数据源
+func newDataLoader(config *Config) (*StockDataLoader, error) {
+	dataLoader := NewStockDataLoader(config.StockPriceDir, config.HistoryDir)
+
+	pricePath := config.StockPriceDir
+	if config.PriceFormat == PriceFormatSQLite {
+		pricePath = config.StorePath
+	}
+	priceSource, err := NewPriceSource(config.PriceFormat, pricePath)
+	if err != nil {
+		return nil, err
+	}
+	dataLoader.SetPriceSource(priceSource)
+
+	signalSource, err := NewSignalSource(config)
+	if err != nil {
+		return nil, err
+	}
+	dataLoader.SetSignalSource(signalSource)
+
+	return dataLoader, nil
+}
+
+// runImport 执行 import 子命令：将股价、指数和交易信号导入 SQLite 数据库
+func runImport(args []string) {
+	fs := newCommandFlagSet("import")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		dbPath        = fs.String("db", "tech-titans.db", "SQLite database path")
+		stockPriceDir = fs.String("stock-dir", "stock_price", "Stock price data directory (file for long-csv); empty to skip")
+		priceFormat   = fs.String("price-format", "yahoo-csv", "Stock price format: yahoo-csv, iso-csv, json or long-csv")
+		indexDir      = fs.String("index-dir", "all_time_stock_price", "Index price data directory (yahoo-csv); empty to skip")
+		historyDir    = fs.String("history-dir", "history", "Trading history directory")
+		signalFormat  = fs.String("signal-format", "csv-dir", "Signal source format: csv-dir, long-csv or json; empty to skip")
+		signalFile    = fs.String("signal-file", "", "Signal file for long-csv / json formats")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	store, err := OpenStore(*dbPath)
+	if err != nil {
+		log.Fatalf(T("Failed to open database: %v"), err)
+	}
+	defer store.Close()
+
+	if *stockPriceDir != "" {
+		source, err := NewPriceSource(*priceFormat, *stockPriceDir)
+		if err != nil {
+			log.Fatalf(T("Invalid stock price source: %v"), err)
+		}
+		symbols, rows, err := store.ImportPrices(StorePriceSourceStock, source)
+		if err != nil {
+			log.Fatalf(T("Failed to import stock prices: %v"), err)
+		}
+		fmt.Printf(T("Imported %d symbols (%d rows) from %s\n"), symbols, rows, *stockPriceDir)
+	}
+
+	if *indexDir != "" {
+		symbols, rows, err := store.ImportPrices(StorePriceSourceIndex, NewYahooCSVPriceSource(*indexDir))
+		if err != nil {
+			log.Fatalf(T("Failed to import index prices: %v"), err)
+		}
+		fmt.Printf(T("Imported %d indexes (%d rows) from %s\n"), symbols, rows, *indexDir)
+	}
+
+	if *signalFormat != "" {
+		config := DefaultConfig()
+		config.HistoryDir = *historyDir
+		config.SignalFormat = *signalFormat
+		config.SignalFile = *signalFile
+		source, err := NewSignalSource(config)
+		if err != nil {
+			log.Fatalf(T("Invalid signal source: %v"), err)
+		}
+		dates, rows, err := store.ImportSignals(source)
+		if err != nil {
+			log.Fatalf(T("Failed to import signals: %v"), err)
+		}
+		fmt.Printf(T("Imported %d signal dates (%d rows)\n"), dates, rows)
+	}
+
+	fmt.Printf(T("Database ready: %s\n"), *dbPath)
+}
+
+// runListRuns 执行 runs 子命令：列出数据库中已保存的回测运行
+func runListRuns(args []string) {
+	fs := newCommandFlagSet("runs")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	dbPath := fs.String("db", "tech-titans.db", "SQLite database path")
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	store, err := OpenStore(*dbPath)
+	if err != nil {
+		log.Fatalf(T("Failed to open database: %v"), err)
+	}
+	defer store.Close()
+
+	runs, err := store.ListRuns()
+	if err != nil {
+		log.Fatalf(T("Failed to list runs: %v"), err)
+	}
+
+	fmt.Printf("%-20s %-20s %-23s %14s %10s %10s %8s %8s\n",
+		T("Run ID"), T("Saved At"), T("Period"), T("Final Value"), T("Return %"), T("Max DD %"), T("Sharpe"), T("Trades"))
+	for _, run := range runs {
+		fmt.Printf("%-20s %-20s %-23s %14s %10s %10s %8s %8d\n",
+			run.RunID, run.CreatedAt.Format("2006-01-02 15:04:05"),
+			run.StartDate+"~"+run.EndDate, run.FinalValue.StringFixed(2),
+			run.TotalReturn.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			run.MaxDrawdown.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			run.SharpeRatio.StringFixed(2), run.TotalTrades)
+	}
+}
+
+// runMarkdown 执行 markdown 子命令：将数据库中的一个运行输出为摘要，多个运行输出为对比
+func runMarkdown(args []string) {
+	fs := newCommandFlagSet("markdown")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		dbPath        = fs.String("db", "tech-titans.db", "SQLite database path")
+		templatePath  = fs.String("template", "", "Custom text/template file (default: built-in template)")
+		title         = fs.String("title", "", "Document title (default: derived from the runs)")
+		outputPath    = fs.String("o", "", "Output file (default: stdout)")
+		printTemplate = fs.Bool("print-template", false, "Print the built-in template and exit")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	if *printTemplate {
+		_, text := builtinMarkdownTemplate()
+		fmt.Print(text)
+		return
+	}
+
+	runArgs := fs.Args()
+	if len(runArgs) == 0 {
+		log.Fatal(T("Usage: tech-titans markdown [flags] RUN_ID[=LABEL] ..."))
+	}
+
+	store, err := OpenStore(*dbPath)
+	if err != nil {
+		log.Fatalf(T("Failed to open database: %v"), err)
+	}
+	defer store.Close()
+
+	var runs []*MarkdownRun
+	for _, arg := range runArgs {
+		runID, label, _ := strings.Cut(arg, "=")
+		saved, err := store.LoadRun(runID)
+		if err != nil {
+			log.Fatalf(T("Failed to load run: %v"), err)
+		}
+		run, err := NewMarkdownRun(saved.RunID, label, saved.Config, saved.Reports)
+		if err != nil {
+			log.Fatalf(T("Failed to load run: %v"), err)
+		}
+		runs = append(runs, run)
+	}
+
+	report, err := NewMarkdownReport(*title, runs)
+	if err != nil {
+		log.Fatalf(T("Failed to build report: %v"), err)
+	}
+
+	output := os.Stdout
+	if *outputPath != "" {
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			log.Fatalf(T("Failed to create output file: %v"), err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	if err := WriteMarkdownReport(output, report, *templatePath); err != nil {
+		log.Fatalf(T("Failed to write markdown report: %v"), err)
+	}
+	if *outputPath != "" {
+		fmt.Printf(T("Markdown report written to %s\n"), *outputPath)
+	}
+}
+
+// runMerge 执行 merge 子命令：将新导出的 Yahoo CSV 合并到股价目录
+func runMerge(args []string) {
+	fs := newCommandFlagSet("merge")
+	lang := fs.String("lang", defaultLanguage(), langUsage)
+	logging := addLogFlags(fs)
+	var (
+		stockPriceDir = fs.String("stock-dir", "stock_price", "Stock price directory to merge into")
+		symbol        = fs.String("symbol", "", "Symbol for a single input file (default: input file name)")
+		keep          = fs.String("keep", "incoming", "Values kept on conflicting dates: incoming or existing")
+		tolerance     = fs.Float64("tolerance", 0.0001, "Relative difference treated as equal")
+		dryRun        = fs.Bool("dry-run", false, "Report changes without writing files")
+		reportPath    = fs.String("report", "", "Write added and conflicting rows to this CSV")
+	)
+	fs.Parse(args)
+	applyLanguage(*lang)
+	closeLog := logging.apply()
+	defer closeLog()
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		log.Fatal(T("Usage: tech-titans merge [flags] NEW_EXPORT.csv ..."))
+	}
+	if *symbol != "" && len(inputs) > 1 {
+		log.Fatal(T("-symbol can only be used with a single input file"))
+	}
+	if *keep != MergeKeepIncoming && *keep != MergeKeepExisting {
+		log.Fatalf(T("Invalid keep mode: %s"), *keep)
+	}
+
+	var results []*MergeResult
+	failed := 0
+	for _, input := range inputs {
+		name := *symbol
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+		}
+
+		result, err := MergePriceFile(*stockPriceDir, name, input, *tolerance, *keep, *dryRun)
+		if err != nil {
+			slog.Error(T("Failed to merge"), "file", input, "err", err)
+			failed++
+			continue
+		}
+		PrintMergeResult(result, *dryRun)
+		results = append(results, result)
+	}
+
+	if *reportPath != "" {
+		if err := WriteMergeReport(*reportPath, results); err != nil {
+			slog.Error(T("Failed to write merge report"), "err", err)
+		} else {
+			fmt.Printf(T("Merge report written to %s\n"), *reportPath)
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
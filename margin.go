@@ -0,0 +1,304 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// borrowedAmount 返回当前融资借款金额（现金为负时即为借款）
+func borrowedAmount(portfolio *Portfolio) decimal.Decimal {
+	if portfolio.Cash.IsNegative() {
+		return portfolio.Cash.Neg()
+	}
+	return decimal.Zero
+}
+
+// buyingPower 计算当前可用购买力
+// 分配比例不超过 1 时仅能使用现金；超过 1 时允许融资至 总价值 × (比例 - 1)
+func (strategy *TradingStrategy) buyingPower(portfolio *Portfolio, allocationRatio decimal.Decimal) decimal.Decimal {
+	power := portfolio.Cash
+	if allocationRatio.GreaterThan(decimal.NewFromInt(1)) {
+		maxBorrow := portfolio.Value.Mul(allocationRatio.Sub(decimal.NewFromInt(1)))
+		power = power.Add(maxBorrow)
+	}
+	if power.IsNegative() {
+		return decimal.Zero
+	}
+	return power
+}
+
+// chargeMarginInterest 按月计提融资利息，直接从现金中扣除
+func (strategy *TradingStrategy) chargeMarginInterest(portfolio *Portfolio) decimal.Decimal {
+	borrowed := borrowedAmount(portfolio)
+	if borrowed.IsZero() || strategy.config.MarginInterestRate <= 0 {
+		return decimal.Zero
+	}
+
+	monthlyRate := decimal.NewFromFloat(strategy.config.MarginInterestRate).Div(decimal.NewFromInt(12))
+	interest := borrowed.Mul(monthlyRate)
+	portfolio.Cash = portfolio.Cash.Sub(interest)
+
+	slog.Info(T("计提融资利息"), "borrowed", borrowed.StringFixed(2), "interest", interest.StringFixed(2))
+	return interest
+}
+
+// leverageRatio 计算杠杆率（多头市值 + 空头市值）/ 净值
+func leverageRatio(portfolio *Portfolio) decimal.Decimal {
+	if !portfolio.Value.IsPositive() {
+		return decimal.Zero
+	}
+	longValue, shortValue := exposureValues(portfolio.Positions)
+	return longValue.Add(shortValue).Div(portfolio.Value)
+}
+
+// checkMarginCall 检查维持保证金，不足时按比例强制平仓
+// 平仓金额使总敞口降至 净值 / 维持保证金比例，持仓价格取最近一次估值价格
+func (strategy *TradingStrategy) checkMarginCall(portfolio *Portfolio, date time.Time) []TradingAction {
+	var actions []TradingAction
+
+	longValue, shortValue := exposureValues(portfolio.Positions)
+	maintenance := decimal.NewFromFloat(strategy.config.MaintenanceMargin)
+	if (borrowedAmount(portfolio).IsZero() && shortValue.IsZero()) || !maintenance.IsPositive() {
+		return actions
+	}
+
+	stockValue := longValue.Add(shortValue)
+	if !stockValue.IsPositive() {
+		return actions
+	}
+
+	equity := portfolio.Value
+	if equity.Div(stockValue).GreaterThanOrEqual(maintenance) {
+		return actions
+	}
+
+	// 目标总敞口，净值为负时全部平仓
+	targetStockValue := decimal.Zero
+	if equity.IsPositive() {
+		targetStockValue = equity.Div(maintenance)
+	}
+	fraction := stockValue.Sub(targetStockValue).Div(stockValue)
+
+	slog.Warn(T("追加保证金"), "month", date.Format("2006-01"),
+		"equity", equity.StringFixed(2), "exposure", stockValue.StringFixed(2),
+		"margin_pct", equity.Div(stockValue).Mul(decimal.NewFromInt(100)).StringFixed(2),
+		"liquidate_pct", fraction.Mul(decimal.NewFromInt(100)).StringFixed(2))
+
+	return strategy.liquidateProportionally(portfolio, fraction, date, TradeReasonMarginCall, false)
+}
+
+// reduceToTarget 将多头市值缩减至 总价值 × 目标比例，超出部分转为现金；
+// 全部卖出的多头记入 Sidelined，建仓比例上调时由 increaseToTarget 买回
+func (strategy *TradingStrategy) reduceToTarget(portfolio *Portfolio, date time.Time, allocationRatio decimal.Decimal, reason string) ([]TradingAction, error) {
+	// 先按当月价格重新估值
+	if err := strategy.updatePortfolioValue(portfolio, date); err != nil {
+		return nil, err
+	}
+
+	longValue, _ := exposureValues(portfolio.Positions)
+	target := portfolio.Value.Mul(allocationRatio)
+	if !longValue.IsPositive() || longValue.LessThanOrEqual(target) {
+		return nil, nil
+	}
+
+	fraction := longValue.Sub(target).Div(longValue)
+	slog.Info(TradeReasonText(reason), "month", date.Format("2006-01"),
+		"long_value", longValue.StringFixed(2), "target", target.StringFixed(2),
+		"reduce_pct", fraction.Mul(decimal.NewFromInt(100)).StringFixed(2))
+
+	actions := strategy.liquidateProportionally(portfolio, fraction, date, reason, true)
+	for _, action := range actions {
+		if _, held := portfolio.Positions[action.Symbol]; !held {
+			if portfolio.Sidelined == nil {
+				portfolio.Sidelined = make(map[string]decimal.Decimal)
+			}
+			portfolio.Sidelined[action.Symbol] = action.Shares
+		}
+	}
+	return actions, nil
+}
+
+// increaseToTarget 建仓比例上调后（risk-off 结束、波动率回落）加仓已持有的多头并买回 Sidelined 中的股票，
+// 使多头市值回到 总价值 × 建仓比例；pending 为本月待买入的新纳入股票数，按等权为其预留份额。
+// 加仓金额按持有市值（买回的股票按清空前股数 × 当月价格）分配，受购买力和板块上限限制，
+// 按股票代码顺序成交，交易日期为当月第一个交易日
+func (strategy *TradingStrategy) increaseToTarget(portfolio *Portfolio, date time.Time, allocationRatio decimal.Decimal, pending int) ([]TradingAction, error) {
+	// 先按当月价格重新估值
+	if err := strategy.updatePortfolioValue(portfolio, date); err != nil {
+		return nil, err
+	}
+
+	weights := make(map[string]decimal.Decimal)
+	for symbol, position := range portfolio.Positions {
+		if !isShort(position) {
+			weights[symbol] = position.MarketValue
+		}
+	}
+	for symbol, shares := range portfolio.Sidelined {
+		if _, held := portfolio.Positions[symbol]; held {
+			continue
+		}
+		_, stockPrice, err := strategy.tradingDayPrice(symbol, date)
+		if err != nil {
+			slog.Warn(T("买入股票失败"), "month", date.Format("2006-01"), "symbol", symbol, "err", err)
+			continue
+		}
+		weights[symbol] = stockPrice.Close.Mul(shares)
+	}
+	basket := decimal.Zero
+	for _, weight := range weights {
+		basket = basket.Add(weight)
+	}
+	if !basket.IsPositive() {
+		return nil, nil
+	}
+
+	longValue, _ := exposureValues(portfolio.Positions)
+	target := portfolio.Value.Mul(allocationRatio)
+	if pending > 0 {
+		count := decimal.NewFromInt(int64(len(weights)))
+		target = target.Mul(count).Div(count.Add(decimal.NewFromInt(int64(pending))))
+	}
+	room := target.Sub(longValue)
+	if !room.IsPositive() {
+		return nil, nil
+	}
+	slog.Info(TradeReasonText(TradeReasonScaleUp), "month", date.Format("2006-01"),
+		"long_value", longValue.StringFixed(2), "target", target.StringFixed(2),
+		"sidelined", len(portfolio.Sidelined))
+
+	symbols := make([]string, 0, len(weights))
+	for symbol := range weights {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var actions []TradingAction
+	for _, symbol := range symbols {
+		tradingDay, stockPrice, err := strategy.tradingDayPrice(symbol, date)
+		if err != nil {
+			slog.Warn(T("买入股票失败"), "month", date.Format("2006-01"), "symbol", symbol, "err", err)
+			continue
+		}
+
+		amount := room.Mul(weights[symbol]).Div(basket)
+		if sectorRoom, ok := strategy.sectorRoom(symbol, portfolio); ok && sectorRoom.LessThan(amount) {
+			amount = sectorRoom
+		}
+		if buyingPower := strategy.buyingPower(portfolio, allocationRatio); buyingPower.LessThan(amount) {
+			amount = buyingPower
+		}
+		shares := strategy.calculateShares(amount, stockPrice.Close)
+		if !shares.IsPositive() {
+			continue
+		}
+
+		amount = stockPrice.Close.Mul(shares)
+		portfolio.Cash = portfolio.Cash.Sub(amount)
+		if position, held := portfolio.Positions[symbol]; held {
+			position.Shares = position.Shares.Add(shares)
+			position.CostBasis = position.CostBasis.Add(amount)
+			position.MarketValue = position.MarketValue.Add(amount)
+		} else {
+			portfolio.Positions[symbol] = &Position{
+				Symbol:       symbol,
+				Shares:       shares,
+				BuyPrice:     stockPrice.Close,
+				BuyDate:      tradingDay,
+				CurrentPrice: stockPrice.Close,
+				MarketValue:  amount,
+				CostBasis:    amount,
+			}
+			delete(portfolio.Sidelined, symbol)
+		}
+
+		action := TradingAction{
+			Date:   tradingDay,
+			Symbol: symbol,
+			Action: "BUY",
+			Shares: shares,
+			Price:  stockPrice.Close,
+			Amount: amount,
+			Reason: TradeReasonScaleUp,
+		}
+		logTrade(&action)
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// liquidateProportionally 按比例缩减持仓，多头卖出、空头回补
+// longOnly 为 true 时仅缩减多头持仓，持仓价格取最近一次估值价格；按股票代码顺序成交，交易日期为当月第一个交易日
+func (strategy *TradingStrategy) liquidateProportionally(portfolio *Portfolio, fraction decimal.Decimal, date time.Time, reason string, longOnly bool) []TradingAction {
+	var actions []TradingAction
+
+	symbols := make([]string, 0, len(portfolio.Positions))
+	for symbol := range portfolio.Positions {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		position := portfolio.Positions[symbol]
+		if longOnly && isShort(position) {
+			continue
+		}
+		held := position.Shares.Abs()
+		shares := held.Mul(fraction)
+		if strategy.config.FractionalShares {
+			shares = shares.RoundUp(strategy.config.SharePrecision)
+		} else {
+			shares = shares.Ceil()
+		}
+		if shares.GreaterThan(held) {
+			shares = held
+		}
+		if !shares.IsPositive() {
+			continue
+		}
+
+		tradingDay := date
+		if day, _, err := strategy.tradingDayPrice(symbol, date); err == nil {
+			tradingDay = day
+		}
+
+		amount := position.CurrentPrice.Mul(shares)
+		actionType := "SELL"
+		if isShort(position) {
+			// 空头通过买入回补
+			portfolio.Cash = portfolio.Cash.Sub(amount)
+			actionType = "COVER"
+		} else {
+			portfolio.Cash = portfolio.Cash.Add(amount)
+		}
+
+		if shares.Equal(held) {
+			delete(portfolio.Positions, symbol)
+		} else {
+			// 按剩余比例缩减成本
+			remaining := held.Sub(shares)
+			position.CostBasis = position.CostBasis.Mul(remaining).Div(held)
+			if isShort(position) {
+				position.Shares = remaining.Neg()
+			} else {
+				position.Shares = remaining
+			}
+		}
+
+		actions = append(actions, TradingAction{
+			Date:   tradingDay,
+			Symbol: symbol,
+			Action: actionType,
+			Shares: shares,
+			Price:  position.CurrentPrice,
+			Amount: amount,
+			Reason: reason,
+		})
+		logTrade(&actions[len(actions)-1])
+	}
+
+	return actions
+}
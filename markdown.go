@@ -0,0 +1,326 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultMarkdownTemplate 内置的 Markdown 报告模板：单个运行输出摘要，多个运行输出对比
+//
+//go:embed templates/report.md.tmpl
+var defaultMarkdownTemplate string
+
+// defaultMarkdownTemplateEN 内置模板的英文版本，-lang en 时使用
+//
+//go:embed templates/report.en.md.tmpl
+var defaultMarkdownTemplateEN string
+
+// MarkdownRun Markdown 报告中的一次回测运行
+type MarkdownRun struct {
+	ID             string              // 运行 ID
+	Label          string              // 显示名称
+	Period         string              // 回测区间
+	Strategy       []string            // 策略设置说明
+	Config         *Config             // 运行配置
+	Metrics        *PerformanceMetrics // 绩效指标
+	InitialCapital decimal.Decimal     // 初始资金
+	FinalValue     decimal.Decimal     // 最终价值
+	Months         int                 // 月度报告数
+	Returns        []MarkdownReturnRow // 年 × 月收益率表
+	Drawdowns      []DrawdownEpisode   // 回撤幅度最大的前5个区间
+	Holdings       []*Position         // 期末市值最大的前10个持仓
+	Reports        []*MonthlyReport    // 全部月度报告，供自定义模板使用
+}
+
+// MarkdownReturnRow 年 × 月收益率表的一行，缺失月份为空字符串
+type MarkdownReturnRow struct {
+	Year   int
+	Months [12]string
+	YTD    string
+}
+
+// MarkdownYearRow 多个运行的年度收益率对比，缺失年份为 "-"
+type MarkdownYearRow struct {
+	Year    int
+	Returns []string
+}
+
+// MarkdownReport Markdown 报告模板数据
+type MarkdownReport struct {
+	Title       string
+	GeneratedAt string
+	Runs        []*MarkdownRun    // 全部运行，第一个为对比基准
+	Baseline    *MarkdownRun      // 对比基准（第一个运行）
+	Others      []*MarkdownRun    // 除基准外的其他运行
+	Years       []MarkdownYearRow // 年度收益率对比
+}
+
+// NewMarkdownRun 根据运行配置和月度报告构造 Markdown 报告数据
+func NewMarkdownRun(id, label string, config *Config, reports []*MonthlyReport) (*MarkdownRun, error) {
+	if len(reports) == 0 {
+		return nil, fmt.Errorf(T("运行 %s 没有报告数据"), id)
+	}
+	if label == "" {
+		label = id
+	}
+
+	lastReport := reports[len(reports)-1]
+	run := &MarkdownRun{
+		ID:    id,
+		Label: label,
+		Period: fmt.Sprintf("%s ~ %s",
+			config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02")),
+		Strategy:       describeStrategy(config),
+		Config:         config,
+		Metrics:        CalculatePerformanceMetrics(reports),
+		InitialCapital: decimal.NewFromFloat(config.InitialCapital),
+		FinalValue:     lastReport.TotalValue,
+		Months:         len(reports),
+		Reports:        reports,
+	}
+
+	grid := BuildMonthlyReturnGrid(reports)
+	for _, year := range grid.Years {
+		row := MarkdownReturnRow{Year: year, YTD: formatPercentValue(grid.YTD[year])}
+		for month := 1; month <= 12; month++ {
+			if monthlyReturn, exists := grid.Returns[year][month]; exists {
+				row.Months[month-1] = formatPercentValue(monthlyReturn)
+			}
+		}
+		run.Returns = append(run.Returns, row)
+	}
+
+	drawdowns := AnalyzeDrawdowns(reports)
+	if len(drawdowns) > 5 {
+		drawdowns = drawdowns[:5]
+	}
+	run.Drawdowns = drawdowns
+
+	// 按市值排序持仓
+	for _, position := range lastReport.Positions {
+		run.Holdings = append(run.Holdings, position)
+	}
+	sort.Slice(run.Holdings, func(i, j int) bool {
+		return run.Holdings[i].MarketValue.GreaterThan(run.Holdings[j].MarketValue)
+	})
+	if len(run.Holdings) > 10 {
+		run.Holdings = run.Holdings[:10]
+	}
+
+	return run, nil
+}
+
+// NewMarkdownReport 汇总一个或多个运行，第一个运行作为对比基准
+func NewMarkdownReport(title string, runs []*MarkdownRun) (*MarkdownReport, error) {
+	if len(runs) == 0 {
+		return nil, errors.New(T("没有可输出的运行"))
+	}
+	if title == "" {
+		if len(runs) == 1 {
+			title = fmt.Sprintf(T("回测报告: %s"), runs[0].Label)
+		} else {
+			title = T("策略收益对比分析")
+		}
+	}
+
+	report := &MarkdownReport{
+		Title:       title,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04"),
+		Runs:        runs,
+		Baseline:    runs[0],
+		Others:      runs[1:],
+	}
+
+	// 年度收益率对比：取所有运行出现过的年份
+	years := make(map[int]bool)
+	var yearList []int
+	for _, run := range runs {
+		for _, row := range run.Returns {
+			if !years[row.Year] {
+				years[row.Year] = true
+				yearList = append(yearList, row.Year)
+			}
+		}
+	}
+	sort.Ints(yearList)
+	for _, year := range yearList {
+		row := MarkdownYearRow{Year: year}
+		for _, run := range runs {
+			value := "-"
+			for _, returnRow := range run.Returns {
+				if returnRow.Year == year {
+					value = returnRow.YTD
+				}
+			}
+			row.Returns = append(row.Returns, value)
+		}
+		report.Years = append(report.Years, row)
+	}
+
+	return report, nil
+}
+
+// WriteMarkdownReport 使用模板渲染 Markdown 报告，templatePath 为空时使用当前语言的内置模板
+func WriteMarkdownReport(w io.Writer, report *MarkdownReport, templatePath string) error {
+	name, text := builtinMarkdownTemplate()
+	if templatePath != "" {
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			return fmt.Errorf(T("读取模板 %s 失败: %v"), templatePath, err)
+		}
+		text = string(content)
+		name = templatePath
+	}
+
+	tpl, err := template.New(name).Funcs(markdownFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf(T("解析模板 %s 失败: %v"), name, err)
+	}
+	if err := tpl.Execute(w, report); err != nil {
+		return fmt.Errorf(T("渲染模板 %s 失败: %v"), name, err)
+	}
+	return nil
+}
+
+// builtinMarkdownTemplate 返回当前语言的内置模板名称和内容
+func builtinMarkdownTemplate() (string, string) {
+	if Language() == LangEN {
+		return "report.en.md.tmpl", defaultMarkdownTemplateEN
+	}
+	return "report.md.tmpl", defaultMarkdownTemplate
+}
+
+// GenerateMarkdownSummary 将本次回测的摘要写入 summary.md
+func (rg *ReportGenerator) GenerateMarkdownSummary(reports []*MonthlyReport) error {
+	run, err := NewMarkdownRun(rg.config.RunID, "", rg.config, reports)
+	if err != nil {
+		return err
+	}
+	report, err := NewMarkdownReport("", []*MarkdownRun{run})
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(rg.config.OutputDir, "summary.md")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建 Markdown 报告文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	if err := WriteMarkdownReport(file, report, rg.config.MarkdownTemplate); err != nil {
+		return err
+	}
+
+	slog.Info(T("Markdown 报告已生成"), "path", filePath)
+	return nil
+}
+
+// describeStrategy 将配置转换为策略设置说明
+func describeStrategy(config *Config) []string {
+	var lines []string
+	switch {
+	case config.LongShort:
+		lines = append(lines, fmt.Sprintf(T("**建仓方式**: 多空，总敞口 %.0f%%，净敞口 %.0f%%，融券费率 %.2f%%"),
+			config.GrossExposure*100, config.NetExposure*100, config.ShortBorrowFee*100))
+	case config.AllocationMode == AllocationModeVolTarget:
+		lines = append(lines, fmt.Sprintf(T("**建仓方式**: 波动率目标 %.0f%%，仓位 %.0f%% ~ %.0f%%，回看 %d 个交易日"),
+			config.TargetVolatility*100, config.MinExposure*100, config.MaxExposure*100, config.VolLookback))
+	default:
+		lines = append(lines, fmt.Sprintf(T("**建仓方式**: 初始即投入 %.0f%% 资金"), config.AllocationRatio*100))
+	}
+	if config.AllocationRatio > 1 {
+		lines = append(lines, fmt.Sprintf(T("**融资**: 年化利率 %.2f%%，维持保证金 %.0f%%"),
+			config.MarginInterestRate*100, config.MaintenanceMargin*100))
+	}
+	if config.RegimeEnabled {
+		lines = append(lines, fmt.Sprintf(T("**市场状态过滤**: %s %s(%d)，risk-off 时仓位缩放至 %.0f%%"),
+			config.RegimeSymbol, config.RegimeRule, config.RegimeWindow, config.RegimeRiskOffScale*100))
+	}
+	if config.SectorCap > 0 {
+		lines = append(lines, fmt.Sprintf(T("**板块上限**: %.0f%%"), config.SectorCap*100))
+	}
+	if config.SignalWeighting == SignalWeightingPL {
+		lines = append(lines, T("**买入加权**: 按信号 pl 列加权"))
+	}
+	if config.SignalPriceAction == SignalPriceActionSkip && config.SignalPriceTolerance > 0 {
+		lines = append(lines, fmt.Sprintf(T("**信号价格校验**: 成交价高于信号价格 %.0f%% 以上时跳过买入"), config.SignalPriceTolerance*100))
+	}
+	if config.FractionalShares {
+		lines = append(lines, fmt.Sprintf(T("**碎股**: 保留 %d 位小数"), config.SharePrecision))
+	}
+	lines = append(lines, fmt.Sprintf(T("**初始资金**: %s"), formatMoney(decimal.NewFromFloat(config.InitialCapital))))
+	return lines
+}
+
+// markdownFuncs Markdown 模板可用的格式化函数
+var markdownFuncs = template.FuncMap{
+	// pct 百分比，如 81.79%
+	"pct": formatPercentValue,
+	// pctDiff 两个比例之差（百分点），如 +9.11%
+	"pctDiff": func(base, value decimal.Decimal) string {
+		return signed(value.Sub(base).Mul(decimal.NewFromInt(100)).StringFixed(2)) + "%"
+	},
+	// relDiff 相对变化，如 +12.53%
+	"relDiff": func(base, value decimal.Decimal) string {
+		if base.IsZero() {
+			return "-"
+		}
+		return signed(value.Div(base).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)).StringFixed(2)) + "%"
+	},
+	// money 金额，取整到美元，如 $181,794
+	"money": formatMoney,
+	// moneyDiff 金额之差，如 +$9,110
+	"moneyDiff": func(base, value decimal.Decimal) string {
+		diff := value.Sub(base)
+		if diff.IsNegative() {
+			return "-" + formatMoney(diff.Neg())
+		}
+		return "+" + formatMoney(diff)
+	},
+	// fixed 保留两位小数
+	"fixed": func(value decimal.Decimal) string {
+		return value.StringFixed(2)
+	},
+	// fixedDiff 两个数值之差，保留两位小数
+	"fixedDiff": func(base, value decimal.Decimal) string {
+		return signed(value.Sub(base).StringFixed(2))
+	},
+	// intDiff 两个整数之差
+	"intDiff": func(base, value int) string {
+		return signed(fmt.Sprintf("%d", value-base))
+	},
+	// date 日期，如 2024-07-01
+	"date": func(date time.Time) string {
+		return date.Format("2006-01-02")
+	},
+}
+
+// formatPercentValue 将比例格式化为百分比，如 0.8179 -> 81.79%
+func formatPercentValue(value decimal.Decimal) string {
+	return value.Mul(decimal.NewFromInt(100)).StringFixed(2) + "%"
+}
+
+// formatMoney 将金额取整并加千位分隔符，如 $181,794
+func formatMoney(value decimal.Decimal) string {
+	return "$" + formatThousands(value.Round(0).IntPart())
+}
+
+// signed 为非负数值加上 + 号
+func signed(text string) string {
+	if strings.HasPrefix(text, "-") {
+		return text
+	}
+	return "+" + text
+}
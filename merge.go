@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// 同一日期数据不一致的类型
+const (
+	RestatementSplit    = "split"    // 价格按接近整数的倍数整体变化，通常由拆股/合股引起
+	RestatementDividend = "dividend" // 仅调整后收盘价变化，通常由分红调整引起
+	RestatementRevision = "revision" // 其他修订（如当日盘中导出的数据被收盘数据替换）
+)
+
+// 合并时同一日期数据冲突的处理方式
+const (
+	MergeKeepIncoming = "incoming" // 使用新导出的数据
+	MergeKeepExisting = "existing" // 保留原有数据
+)
+
+// PriceConflict 同一日期新旧数据不一致的记录
+type PriceConflict struct {
+	DateKey  string          // 日期键 YYYYMMDD
+	Fields   []string        // 不一致的字段
+	Old      *StockPrice     // 原有数据
+	New      *StockPrice     // 新导出的数据
+	Ratio    decimal.Decimal // 原收盘价 / 新收盘价
+	Category string          // 不一致类型
+}
+
+// MergeResult 单只股票的合并结果
+type MergeResult struct {
+	Symbol    string          // 股票代码
+	FilePath  string          // 目标文件
+	Existing  int             // 原有记录数
+	Incoming  int             // 新导出记录数
+	Added     []string        // 新增日期键（升序）
+	Unchanged int             // 新旧一致的记录数
+	Conflicts []PriceConflict // 新旧不一致的记录（按日期升序）
+	Merged    int             // 合并后记录数
+}
+
+// MergePrices 合并原有和新导出的股价，按日期去重
+// 同一日期字段差异超过相对容差时记为冲突，keep 决定冲突时保留哪一方
+func MergePrices(existing, incoming map[string]*StockPrice, tolerance float64, keep string) (map[string]*StockPrice, *MergeResult) {
+	result := &MergeResult{
+		Existing: len(existing),
+		Incoming: len(incoming),
+	}
+
+	merged := make(map[string]*StockPrice, len(existing)+len(incoming))
+	for key, price := range existing {
+		merged[key] = price
+	}
+
+	for _, key := range sortedPriceKeys(incoming) {
+		newPrice := incoming[key]
+		oldPrice, exists := existing[key]
+		if !exists {
+			merged[key] = newPrice
+			result.Added = append(result.Added, key)
+			continue
+		}
+
+		fields := differingFields(oldPrice, newPrice, tolerance)
+		if len(fields) == 0 {
+			result.Unchanged++
+			continue
+		}
+
+		conflict := PriceConflict{
+			DateKey: key,
+			Fields:  fields,
+			Old:     oldPrice,
+			New:     newPrice,
+		}
+		if newPrice.Close.IsPositive() {
+			conflict.Ratio = oldPrice.Close.Div(newPrice.Close)
+		}
+		conflict.Category = classifyRestatement(conflict)
+		result.Conflicts = append(result.Conflicts, conflict)
+
+		if keep != MergeKeepExisting {
+			merged[key] = newPrice
+		}
+	}
+
+	result.Merged = len(merged)
+	return merged, result
+}
+
+// differingFields 返回相对差异超过容差的字段名
+func differingFields(oldPrice, newPrice *StockPrice, tolerance float64) []string {
+	var fields []string
+	compare := func(name string, a, b decimal.Decimal) {
+		if relativeDifference(a.InexactFloat64(), b.InexactFloat64()) > tolerance {
+			fields = append(fields, name)
+		}
+	}
+	compare("Open", oldPrice.Open, newPrice.Open)
+	compare("High", oldPrice.High, newPrice.High)
+	compare("Low", oldPrice.Low, newPrice.Low)
+	compare("Close", oldPrice.Close, newPrice.Close)
+	compare("Adj Close", oldPrice.AdjClose, newPrice.AdjClose)
+	if relativeDifference(float64(oldPrice.Volume), float64(newPrice.Volume)) > tolerance {
+		fields = append(fields, "Volume")
+	}
+	return fields
+}
+
+// relativeDifference 计算相对差异 |a - b| / max(|a|, |b|)
+func relativeDifference(a, b float64) float64 {
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	if scale == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / scale
+}
+
+// classifyRestatement 判断冲突类型：收盘价比例接近 2 倍以上的整数（或其倒数）为拆股，仅调整后收盘价不同为分红调整
+func classifyRestatement(conflict PriceConflict) string {
+	ratio := conflict.Ratio.InexactFloat64()
+	if ratio > 0 {
+		factor := ratio
+		if factor < 1 {
+			factor = 1 / factor
+		}
+		rounded := math.Round(factor)
+		if rounded >= 2 && math.Abs(factor-rounded)/rounded < 0.02 {
+			return RestatementSplit
+		}
+	}
+	if len(conflict.Fields) == 1 && conflict.Fields[0] == "Adj Close" {
+		return RestatementDividend
+	}
+	return RestatementRevision
+}
+
+// MergePriceFile 将新导出的 Yahoo CSV 合并到 stockDir/SYMBOL.csv
+// 原文件中未变化的行按原样写回，只写入新增和被替换的行；
+// dryRun 为 true 时只计算变化不写入；否则先写入同目录临时文件再重命名，保证原文件不会被写坏
+func MergePriceFile(stockDir, symbol, inputPath string, tolerance float64, keep string, dryRun bool) (*MergeResult, error) {
+	incoming, err := loadYahooCSV(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(incoming) == 0 {
+		return nil, fmt.Errorf(T("%s 中没有有效的股价记录"), inputPath)
+	}
+
+	filePath := filepath.Join(stockDir, symbol+".csv")
+	existing := make(map[string]*StockPrice)
+	var original []byte
+	if _, err := os.Stat(filePath); err == nil {
+		existing, err = loadYahooCSV(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if original, err = os.ReadFile(filePath); err != nil {
+			return nil, fmt.Errorf(T("无法打开股价文件 %s: %v"), filePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf(T("无法访问股价文件 %s: %v"), filePath, err)
+	}
+
+	merged, result := MergePrices(existing, incoming, tolerance, keep)
+	result.Symbol = symbol
+	result.FilePath = filePath
+
+	noChange := len(result.Added) == 0 && (len(result.Conflicts) == 0 || keep == MergeKeepExisting)
+	if dryRun || noChange {
+		return result, nil
+	}
+
+	if err := writeFileAtomically(filePath, func(file *os.File) error {
+		if original == nil {
+			return writeYahooCSV(file, merged)
+		}
+		return writeMergedYahooCSV(file, original, existing, merged)
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// writeMergedYahooCSV 在原文件内容的基础上写入合并结果：merged 中与 existing 相同的行原样保留，
+// 被替换的行就地改写，新增的行按原文件的日期顺序（默认从新到旧）插入；
+// 新写入的行沿用原文件的换行符和 Volume 写法（千分位是否加引号）
+func writeMergedYahooCSV(w io.Writer, original []byte, existing, merged map[string]*StockPrice) error {
+	lines := strings.Split(strings.TrimSuffix(string(original), "\n"), "\n")
+	if len(lines) == 0 {
+		return writeYahooCSV(w, merged)
+	}
+	newline := "\n"
+	if strings.HasSuffix(lines[0], "\r") {
+		newline = "\r\n"
+	}
+
+	// 原文件数据行的日期键，无法解析的行（空行等）为空
+	keys := make([]string, len(lines))
+	var dated []string
+	quoted := false
+	for i, line := range lines[1:] {
+		fields, err := csv.NewReader(strings.NewReader(line)).Read()
+		if err != nil || len(fields) == 0 {
+			continue
+		}
+		date, err := parseDate(strings.TrimSpace(fields[0]))
+		if err != nil {
+			continue
+		}
+		keys[i+1] = date.Format("20060102")
+		if len(dated) == 0 {
+			quoted = strings.Contains(line, `"`)
+		}
+		dated = append(dated, keys[i+1])
+	}
+	ascending := len(dated) > 1 && dated[0] < dated[len(dated)-1]
+
+	formatRow := func(price *StockPrice) (string, error) {
+		record := isoPriceRecord(price)
+		record[6] = formatThousands(price.Volume)
+		if !quoted {
+			return strings.Join(record, ",") + newline, nil
+		}
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		writer.UseCRLF = newline == "\r\n"
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+		writer.Flush()
+		return buffer.String(), writer.Error()
+	}
+
+	// 新增日期按原文件顺序排列
+	var added []string
+	for _, key := range sortedPriceKeys(merged) {
+		if _, exists := existing[key]; !exists {
+			added = append(added, key)
+		}
+	}
+	if !ascending {
+		for i, j := 0, len(added)-1; i < j; i, j = i+1, j-1 {
+			added[i], added[j] = added[j], added[i]
+		}
+	}
+	before := func(a, b string) bool {
+		if ascending {
+			return a < b
+		}
+		return a > b
+	}
+
+	var out strings.Builder
+	out.WriteString(lines[0] + "\n")
+	writeAdded := func(limit string) error {
+		for len(added) > 0 && (limit == "" || before(added[0], limit)) {
+			row, err := formatRow(merged[added[0]])
+			if err != nil {
+				return err
+			}
+			out.WriteString(row)
+			added = added[1:]
+		}
+		return nil
+	}
+	for i, line := range lines[1:] {
+		key := keys[i+1]
+		if key == "" {
+			out.WriteString(line + "\n")
+			continue
+		}
+		if err := writeAdded(key); err != nil {
+			return err
+		}
+		if price := merged[key]; price != nil && price != existing[key] {
+			row, err := formatRow(price)
+			if err != nil {
+				return err
+			}
+			out.WriteString(row)
+			continue
+		}
+		out.WriteString(line + "\n")
+	}
+	if err := writeAdded(""); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, out.String()); err != nil {
+		return fmt.Errorf(T("写入股价数据失败: %v"), err)
+	}
+	return nil
+}
+
+// writeFileAtomically 写入同目录下的临时文件，成功后重命名为目标文件
+func writeFileAtomically(filePath string, write func(file *os.File) error) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf(T("创建目录 %s 失败: %v"), dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf(T("创建临时文件失败: %v"), err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // 重命名成功后该调用无效果
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf(T("写入临时文件失败: %v"), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf(T("关闭临时文件失败: %v"), err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf(T("设置文件权限失败: %v"), err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf(T("替换文件 %s 失败: %v"), filePath, err)
+	}
+	return nil
+}
+
+// PrintMergeResult 打印单只股票的合并结果
+func PrintMergeResult(result *MergeResult, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] "
+	}
+
+	fmt.Printf(T("%s%s: 原有 %d 条, 新导出 %d 条, 新增 %d 条, 一致 %d 条, 冲突 %d 条, 合并后 %d 条 -> %s\n"),
+		prefix, result.Symbol, result.Existing, result.Incoming, len(result.Added),
+		result.Unchanged, len(result.Conflicts), result.Merged, result.FilePath)
+
+	if len(result.Added) > 0 {
+		fmt.Printf(T("  新增日期: %s ~ %s\n"),
+			formatDateKey(result.Added[0]), formatDateKey(result.Added[len(result.Added)-1]))
+	}
+
+	categories := make(map[string]int)
+	for _, conflict := range result.Conflicts {
+		categories[conflict.Category]++
+	}
+	for _, category := range []string{RestatementSplit, RestatementDividend, RestatementRevision} {
+		if categories[category] > 0 {
+			fmt.Printf(T("  %s: %d 条\n"), category, categories[category])
+		}
+	}
+
+	// 最多列出前10条冲突明细
+	for i, conflict := range result.Conflicts {
+		if i == 10 {
+			fmt.Printf(T("  ... 另有 %d 条冲突\n"), len(result.Conflicts)-10)
+			break
+		}
+		fmt.Printf(T("  %s [%s] %s: Close %s -> %s (比例 %s)\n"),
+			formatDateKey(conflict.DateKey), conflict.Category, strings.Join(conflict.Fields, "/"),
+			formatPrice(conflict.Old.Close), formatPrice(conflict.New.Close), conflict.Ratio.StringFixed(4))
+	}
+}
+
+// formatDateKey 将 YYYYMMDD 日期键格式化为 YYYY-MM-DD
+func formatDateKey(key string) string {
+	if len(key) != 8 {
+		return key
+	}
+	return key[:4] + "-" + key[4:6] + "-" + key[6:]
+}
+
+// WriteMergeReport 将所有股票的新增和冲突明细写入 CSV
+func WriteMergeReport(filePath string, results []*MergeResult) error {
+	rows := [][]string{{"Symbol", "Date", "Change", "Fields", "Old Close", "New Close", "Old Adj Close", "New Adj Close", "Ratio"}}
+	for _, result := range results {
+		for _, key := range result.Added {
+			rows = append(rows, []string{result.Symbol, formatDateKey(key), "added", "", "", "", "", "", ""})
+		}
+		for _, conflict := range result.Conflicts {
+			rows = append(rows, []string{
+				result.Symbol,
+				formatDateKey(conflict.DateKey),
+				conflict.Category,
+				strings.Join(conflict.Fields, "/"),
+				formatPrice(conflict.Old.Close),
+				formatPrice(conflict.New.Close),
+				formatPrice(conflict.Old.AdjClose),
+				formatPrice(conflict.New.AdjClose),
+				conflict.Ratio.StringFixed(4),
+			})
+		}
+	}
+	return writeCSVFile(filePath, rows)
+}
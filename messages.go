@@ -0,0 +1,652 @@
+package main
+
+// messageCatalog 消息目录：语言 -> 原文 -> 译文
+// 中文原文在 LangEN 下给出英文译文，英文原文在 LangZH 下给出中文译文；
+// 新增带 T() 的消息时需在对应语言下补充译文，并保持格式化占位符的顺序和类型一致
+var messageCatalog = map[string]map[string]string{
+	LangEN: {
+		// 通用
+		"没有报告数据":         "no report data available",
+		"创建输出目录失败: %v":   "failed to create output directory: %v",
+		"创建文件 %s 失败: %v": "failed to create file %s: %v",
+		"写入文件 %s 失败: %v": "failed to write file %s: %v",
+		"写入标题失败: %v":     "failed to write header: %v",
+		"读取CSV表头失败: %v":  "failed to read CSV header: %v",
+		"读取CSV记录失败: %v":  "failed to read CSV record: %v",
+		"不支持的语言: %s":     "unsupported language: %s",
+
+		// 交易原因及信号状态（数据中保存原文，显示时翻译）
+		"股票被纳入":          "symbol included",
+		"股票被剔除":          "symbol excluded",
+		"追加保证金强制平仓":      "margin call liquidation",
+		"波动率目标减仓":        "vol-target de-risking",
+		"市场 risk-off 减仓": "risk-off de-risking",
+		"建仓比例上调加仓":       "allocation scale-up",
+		"纳入":             "include",
+		"剔除":             "exclude",
+
+		// 策略执行
+		"初始化市场状态过滤器失败: %v":  "failed to initialize regime filter: %v",
+		"加载股票分类元数据失败: %v":   "failed to load symbol metadata: %v",
+		"加载交易信号失败: %v":      "failed to load trading signals: %v",
+		"更新投资组合价值失败: %v":    "failed to update portfolio value: %v",
+		"加载股价数据失败: %v":      "failed to load price data: %v",
+		"获取交易日失败: %v":       "failed to get trading day: %v",
+		"未找到 %s 在 %s 的股价数据": "no price data for %s on %s",
+		"多头敞口已达上限":          "long exposure limit reached",
+		"资金不足以买入 %s":        "insufficient funds to buy %s",
+		"现金不足以买入 %s":        "insufficient cash to buy %s",
+
+		// 融资、多空、波动率目标、市场状态、信号校验
+		"空头敞口已达上限":         "short exposure limit reached",
+		"额度不足以做空 %s":       "insufficient capacity to short %s",
+		"没有可用于估算的持仓":       "no holdings available for estimation",
+		"历史数据不足":           "insufficient history",
+		"不支持的市场状态规则: %s":   "unsupported regime rule: %s",
+		"市场状态窗口必须大于0: %d":  "regime window must be greater than 0: %d",
+		"加载指数 %s 数据失败: %v": "failed to load index %s: %v",
+		"无法打开元数据文件 %s: %v": "cannot open metadata file %s: %v",
+		"元数据文件缺少列: %s":     "metadata file is missing column: %s",
+
+		// 数据加载
+		"未找到 %d年%d月 的交易日":      "no trading day found in %d-%02d",
+		"无法解析日期格式: %s":         "cannot parse date: %s",
+		"不支持的股价格式: %s":         "unsupported price format: %s",
+		"无法打开股价文件 %s: %v":      "cannot open price file %s: %v",
+		"解析股价文件 %s 失败: %v":     "failed to parse price file %s: %v",
+		"股价文件 %s 第 %d 条记录: %v": "price file %s record %d: %v",
+		"股价文件 %s 中没有 %s 的数据":   "price file %s has no data for %s",
+		"股价文件 %s 缺少 %s 列":      "price file %s is missing column %s",
+		"股价文件 %s 第 %d 行: %v":   "price file %s line %d: %v",
+		"查找股价文件失败: %v":         "failed to find price files: %v",
+		"写入股价数据失败: %v":         "failed to write price data: %v",
+		"序列化 %s 股价失败: %v":      "failed to serialize prices for %s: %v",
+		"%s 格式需要指定信号文件":        "%s format requires a signal file",
+		"不支持的信号格式: %s":         "unsupported signal format: %s",
+		"无法打开交易信号文件 %s: %v":    "cannot open signal file %s: %v",
+		"查找交易信号文件失败: %v":       "failed to find signal files: %v",
+		"信号文件 %s 中没有 %s 的交易信号": "signal file %s has no signals for %s",
+		"信号文件 %s 缺少 date 列":    "signal file %s is missing the date column",
+		"信号文件 %s 第 %d 行: %v":   "signal file %s line %d: %v",
+		"解析信号文件 %s 失败: %v":     "failed to parse signal file %s: %v",
+		"读取信号文件 %s 失败: %v":     "failed to read signal file %s: %v",
+		"信号文件 %s 第 %d 条记录: %v": "signal file %s record %d: %v",
+		"加载基准 %s 数据失败: %v":     "failed to load benchmark %s: %v",
+		"基准 %s 缺少 %s 的价格数据":    "benchmark %s has no price data for %s",
+
+		// 股价合并
+		"%s 中没有有效的股价记录":   "%s contains no valid price records",
+		"无法访问股价文件 %s: %v": "cannot access price file %s: %v",
+		"创建目录 %s 失败: %v":  "failed to create directory %s: %v",
+		"创建临时文件失败: %v":    "failed to create temporary file: %v",
+		"写入临时文件失败: %v":    "failed to write temporary file: %v",
+		"关闭临时文件失败: %v":    "failed to close temporary file: %v",
+		"设置文件权限失败: %v":    "failed to set file permissions: %v",
+		"替换文件 %s 失败: %v":  "failed to replace file %s: %v",
+		"%s%s: 原有 %d 条, 新导出 %d 条, 新增 %d 条, 一致 %d 条, 冲突 %d 条, 合并后 %d 条 -> %s\n": "%s%s: %d existing, %d incoming, %d added, %d unchanged, %d conflicts, %d after merge -> %s\n",
+		"  新增日期: %s ~ %s\n":                      "  Added dates: %s ~ %s\n",
+		"  %s: %d 条\n":                           "  %s: %d\n",
+		"  ... 另有 %d 条冲突\n":                      "  ... %d more conflicts\n",
+		"  %s [%s] %s: Close %s -> %s (比例 %s)\n": "  %s [%s] %s: Close %s -> %s (ratio %s)\n",
+
+		// SQLite 存储
+		"未指定数据库路径":          "no database path specified",
+		"打开数据库 %s 失败: %v":   "failed to open database %s: %v",
+		"初始化数据库 %s 失败: %v":  "failed to initialize database %s: %v",
+		"写入 %s 股价失败: %v":    "failed to write prices for %s: %v",
+		"写入 %s 交易信号失败: %v":  "failed to write signals for %s: %v",
+		"查询 %s 股价失败: %v":    "failed to query prices for %s: %v",
+		"数据库中没有 %s 的股价数据":   "no price data for %s in database",
+		"查询 %s 交易信号失败: %v":  "failed to query signals for %s: %v",
+		"数据库中没有 %s 的交易信号":   "no signals for %s in database",
+		"序列化配置失败: %v":       "failed to serialize config: %v",
+		"保存运行 %s 失败: %v":    "failed to save run %s: %v",
+		"保存 %s 月度报告失败: %v":  "failed to save monthly report %s: %v",
+		"保存 %s 持仓失败: %v":    "failed to save positions for %s: %v",
+		"保存 %s 交易失败: %v":    "failed to save trades for %s: %v",
+		"数据库中没有运行 %s":       "run %s not found in database",
+		"解析运行 %s 的配置失败: %v": "failed to parse config of run %s: %v",
+		"运行 %s 的报告日期无效: %s": "run %s has an invalid report date: %s",
+		"运行 %s 的板块权重无效: %s": "run %s has invalid sector weights: %s",
+
+		// 报告
+		"创建报告文件失败: %v":        "failed to create report file: %v",
+		"写入持仓数据失败: %v":        "failed to write positions: %v",
+		"写入空头标题失败: %v":        "failed to write short positions header: %v",
+		"写入汇总信息失败: %v":        "failed to write summary: %v",
+		"写入板块权重失败: %v":        "failed to write sector weights: %v",
+		"生成最终持仓报告失败: %v":      "failed to generate final position report: %v",
+		"生成业绩汇总报告失败: %v":      "failed to generate performance summary: %v",
+		"创建最终持仓报告文件失败: %v":    "failed to create final position report file: %v",
+		"创建业绩汇总报告文件失败: %v":    "failed to create performance summary file: %v",
+		"写入业绩数据失败: %v":        "failed to write performance data: %v",
+		"创建回撤报告文件失败: %v":      "failed to create drawdown report file: %v",
+		"写入回撤数据失败: %v":        "failed to write drawdown data: %v",
+		"创建月度收益率表文件失败: %v":    "failed to create monthly returns file: %v",
+		"写入月度收益率失败: %v":       "failed to write monthly returns: %v",
+		"创建信号校验报告文件失败: %v":    "failed to create signal validation report file: %v",
+		"写入信号校验数据失败: %v":      "failed to write signal validation data: %v",
+		"创建归因报告文件失败: %v":      "failed to create attribution report file: %v",
+		"写入归因数据失败: %v":        "failed to write attribution data: %v",
+		"创建区间归因报告文件失败: %v":    "failed to create period attribution file: %v",
+		"写入区间归因数据失败: %v":      "failed to write period attribution data: %v",
+		"创建 JSONL 报告文件失败: %v": "failed to create JSONL report file: %v",
+		"写入 JSONL 报告失败: %v":   "failed to write JSONL report: %v",
+		"创建 Excel 样式失败: %v":   "failed to create Excel styles: %v",
+		"创建工作表失败: %v":         "failed to create worksheet: %v",
+		"保存 Excel 报告失败: %v":   "failed to save Excel report: %v",
+		"创建工作表 %s 失败: %v":     "failed to create worksheet %s: %v",
+		"设置工作表 %s 列宽失败: %v":   "failed to set column widths of worksheet %s: %v",
+		"设置工作表 %s 冻结窗格失败: %v": "failed to freeze panes of worksheet %s: %v",
+		"写入工作表 %s 标题失败: %v":   "failed to write header of worksheet %s: %v",
+		"写入工作表 %s 数据失败: %v":   "failed to write rows of worksheet %s: %v",
+		"写入工作表 %s 失败: %v":     "failed to write worksheet %s: %v",
+
+		// 控制台汇总
+		"\n=== 投资策略执行汇总 ===":          "\n=== Strategy Execution Summary ===",
+		"执行周期: %s 至 %s\n":             "Period: %s to %s\n",
+		"初始资金: $%s\n":                 "Initial Capital: $%s\n",
+		"最终价值: $%s\n":                 "Final Value: $%s\n",
+		"现金余额: $%s\n":                 "Cash Balance: $%s\n",
+		"股票市值: $%s\n":                 "Stock Value: $%s\n",
+		"融资借款: $%s\n":                 "Borrowed: $%s\n",
+		"累计利息: $%s\n":                 "Total Interest: $%s\n",
+		"杠杆率: %s\n":                   "Leverage: %s\n",
+		"多头市值: $%s\n":                 "Long Value: $%s\n",
+		"空头市值: $%s\n":                 "Short Value: $%s\n",
+		"累计融券费用: $%s\n":               "Total Borrow Fees: $%s\n",
+		"总收益率: %s%%\n":                "Total Return: %s%%\n",
+		"持仓数量: %d\n":                  "Positions: %d\n",
+		"risk-off 月数: %d\n":           "Risk-off Months: %d\n",
+		"实现波动率: %.2f%% (目标 %.2f%%)\n": "Realized Volatility: %.2f%% (target %.2f%%)\n",
+		"总交易月数: %d\n":                 "Months Traded: %d\n",
+		"信号校验问题: %d (跳过买入 %d)\n":      "Signal Issues: %d (buys skipped %d)\n",
+		"年化收益率: %s%%\n":               "Annualized Return: %s%%\n",
+		"最大回撤: %s%%\n":                "Max Drawdown: %s%%\n",
+		"\n=== 前10大持仓 ===":            "\n=== Top 10 Holdings ===",
+
+		// 事件研究
+		"\n=== 信号事件研究 (基准: %s, 事件数: %d) ===\n": "\n=== Signal Event Study (benchmark: %s, events: %d) ===\n",
+		"信号":    "Signal",
+		"天数":    "Days",
+		"样本":    "N",
+		"平均收益%": "Mean Raw%",
+		"中位收益%": "Med Raw%",
+		"平均超额%": "Mean Exc%",
+		"中位超额%": "Med Exc%",
+		"命中率%":  "Hit%",
+
+		// Markdown 报告
+		"运行 %s 没有报告数据":           "run %s has no report data",
+		"没有可输出的运行":               "no runs to report",
+		"回测报告: %s":               "Backtest Report: %s",
+		"策略收益对比分析":               "Strategy Comparison",
+		"读取模板 %s 失败: %v":         "failed to read template %s: %v",
+		"解析模板 %s 失败: %v":         "failed to parse template %s: %v",
+		"渲染模板 %s 失败: %v":         "failed to render template %s: %v",
+		"创建 Markdown 报告文件失败: %v": "failed to create Markdown report file: %v",
+		"**建仓方式**: 多空，总敞口 %.0f%%，净敞口 %.0f%%，融券费率 %.2f%%":       "**Allocation**: long/short, gross exposure %.0f%%, net exposure %.0f%%, borrow fee %.2f%%",
+		"**建仓方式**: 波动率目标 %.0f%%，仓位 %.0f%% ~ %.0f%%，回看 %d 个交易日": "**Allocation**: volatility target %.0f%%, exposure %.0f%% ~ %.0f%%, %d-day lookback",
+		"**建仓方式**: 初始即投入 %.0f%% 资金":                            "**Allocation**: %.0f%% of capital invested from the start",
+		"**融资**: 年化利率 %.2f%%，维持保证金 %.0f%%":                     "**Margin**: %.2f%% annual rate, %.0f%% maintenance margin",
+		"**市场状态过滤**: %s %s(%d)，risk-off 时仓位缩放至 %.0f%%":         "**Regime Filter**: %s %s(%d), exposure scaled to %.0f%% when risk-off",
+		"**板块上限**: %.0f%%":     "**Sector Cap**: %.0f%%",
+		"**买入加权**: 按信号 pl 列加权": "**Buy Weighting**: by signal pl column",
+		"**信号价格校验**: 成交价高于信号价格 %.0f%% 以上时跳过买入": "**Signal Price Check**: skip buys filling more than %.0f%% above the signal price",
+		"**碎股**: 保留 %d 位小数":                    "**Fractional Shares**: %d decimal places",
+		"**初始资金**: %s":                         "**Initial Capital**: %s",
+
+		// 日志
+		"交易":     "trade",
+		"处理月份":   "processing month",
+		"处理月份失败": "failed to process month",
+		"加载股票分类元数据失败，跳过板块统计": "failed to load symbol metadata, skipping sector statistics",
+		"卖出股票失败":           "failed to sell stock",
+		"买入股票失败":           "failed to buy stock",
+		"做空股票失败":           "failed to short stock",
+		"回补股票失败":           "failed to cover stock",
+		"减仓至目标仓位失败":        "failed to reduce to target exposure",
+		"加仓至目标仓位失败":        "failed to increase to target exposure",
+		"分配买入资金":           "allocating buy cash",
+		"分配空头额度":           "allocating short capacity",
+		"板块上限调整买入资金":       "buy amount capped by sector limit",
+		"无法加载股价数据":         "cannot load stock prices",
+		"无法获取交易日":          "cannot find trading day",
+		"未找到股价数据":          "stock price not found",
+		"计提融资利息":           "margin interest charged",
+		"计提融券费用":           "borrow fee charged",
+		"追加保证金":            "margin call",
+		"市场状态":             "market regime",
+		"计算市场状态失败":         "failed to evaluate market regime",
+		"波动率目标":            "volatility target",
+		"估算组合波动率失败，使用最大仓位": "failed to estimate portfolio volatility, using maximum exposure",
+		"无法获取历史收益率":        "cannot load trailing returns",
+		"成交价高于信号参考价，跳过买入":  "fill price above signal reference price, skipping buy",
+		"信号校验发现问题":         "signal validation issues found",
+		"无法解析日期，跳过该行":      "cannot parse date, skipping row",
+		"字段数量不匹配，跳过该行":     "field count mismatch, skipping row",
+		"跳过股票":             "skipping symbol",
+		"跳过信号日":            "skipping signal date",
+		"加载交易信号失败":         "failed to load trade signals",
+		"信号当月没有交易日":        "no trading day in signal month",
+		"加载基准失败，报告中不显示基准":  "failed to load benchmark, omitting it from the report",
+		"本地资源不可用，改为远程加载":   "local asset unavailable, loading from remote",
+		"月度报告已生成":          "monthly report generated",
+		"最终持仓报告已生成":        "final position report generated",
+		"业绩汇总报告已生成":        "performance summary generated",
+		"回撤分析报告已生成":        "drawdown report generated",
+		"月度收益率表已生成":        "monthly returns table generated",
+		"信号校验报告已生成":        "signal validation report generated",
+		"归因报告已生成":          "attribution report generated",
+		"JSONL 报告已生成":      "JSONL report generated",
+		"Excel 报告已生成":      "Excel report generated",
+		"Markdown 报告已生成":   "Markdown report generated",
+		"事件研究汇总已生成":        "event study summary generated",
+		"事件研究明细已生成":        "event study details generated",
+		"不支持的日志级别: %s":     "unsupported log level: %s",
+		"不支持的日志格式: %s":     "unsupported log format: %s",
+		"无法打开日志文件 %s: %v":  "cannot open log file %s: %v",
+		// 回测结果、输入校验、订单和运行列表
+		"%s 中没有回测结果 %s":                                   "%s contains no run results %s",
+		"解析回测结果 %s 失败: %v":                                "failed to parse run results %s: %v",
+		"不支持的回测结果版本: %s":                                  "unsupported run results schema: %s",
+		"回测结果 %s 中没有报告数据":                                 "run results %s contain no reports",
+		"无法加载交易信号: %v":                                    "cannot load trade signals: %v",
+		"没有交易信号":                                          "no trade signals",
+		"未知的信号状态: %s":                                     "unknown signal status: %s",
+		"无法加载股价数据: %v":                                    "cannot load stock prices: %v",
+		"元数据中的股票缺少股价数据，回测将跳过买入: %v":                       "symbol in metadata has no price data, buys will be skipped: %v",
+		"当月没有股价数据":                                        "no stock prices in the month",
+		"信号参考价格无效: %s":                                    "invalid signal reference price: %s",
+		"信号参考价格 %s 与收盘价 %s 偏差 %s%%":                       "signal reference price %s deviates from close %s by %s%%",
+		"无法加载指数数据: %v":                                    "cannot load index prices: %v",
+		"\n校验完成: %d 个错误, %d 个警告\n":                        "\nValidation complete: %d errors, %d warnings\n",
+		"扫描参数格式应为 name=v1,v2,...: %s":                     "sweep parameter must be name=v1,v2,...: %s",
+		"重复的扫描参数: %s":                                     "duplicate sweep parameter: %s",
+		"无效的月份: %s":                                       "invalid month: %s",
+		"运行中没有 %s 的月度报告":                                  "run has no monthly report for %s",
+		"生成运行列表失败":                                        "failed to render run list",
+		"跳过无法读取的运行":                                       "skipping unreadable run",
+		"无效的数值: %s":                                       "invalid number: %s",
+		"无效的日期: %s":                                       "invalid date: %s",
+		"%s 中没有 %s 的月度报告":                                 "%s has no monthly report for %s",
+		"不支持的对比报告格式: %s（应为 .md 或 .html）":                  "unsupported comparison report format: %s (use .md or .html)",
+		"净值曲线对比图已生成":                                      "equity curve comparison chart generated",
+		"对比报告已生成":                                         "comparison report generated",
+		"无法打开持仓文件 %s: %v":                                 "cannot open holdings file %s: %v",
+		"读取持仓文件 %s 失败: %v":                                "failed to read holdings file %s: %v",
+		"持仓文件 %s 为空":                                      "holdings file %s is empty",
+		"持仓文件 %s 缺少 %s 列":                                 "holdings file %s has no %s column",
+		"持仓文件第 %d 行的数量无效: %s":                             "invalid shares on line %d of the holdings file: %s",
+		"持仓文件中 %s 重复":                                     "duplicate symbol %s in the holdings file",
+		"持仓文件第 %d 行的买入价格无效: %s":                           "invalid buy price on line %d of the holdings file: %s",
+		"持仓文件第 %d 行的成本无效: %s":                             "invalid cost basis on line %d of the holdings file: %s",
+		"交易日 %s 应在信号日 %s 当月且不早于信号日":                       "trading day %s must be in the month of signal date %s and not before it",
+		"交易日 %s 不在股价数据的交易日历中":                             "trading day %s is not in the price data calendar",
+		"交易日 %s 不是纽交所交易日":                                 "trading day %s is not an NYSE trading day",
+		"无法估算 %s 的价格":                                     "cannot estimate the price of %s",
+		"不支持的订单类型: %s":                                    "unsupported order type: %s",
+		"不支持的订单有效期: %s":                                   "unsupported time in force: %s",
+		"未知的交易方向: %s":                                     "unknown trade action: %s",
+		"不支持的券商订单格式: %s":                                  "unsupported broker order format: %s",
+		"回读订单文件失败: %v":                                    "order file round trip failed: %v",
+		"订单数量不一致: %d != %d":                               "order count mismatch: %d != %d",
+		"第 %d 个订单不一致: %+v != %+v":                         "order %d mismatch: %+v != %+v",
+		"订单 %s 缺少股票代码":                                    "order %s has no symbol",
+		"订单 %s 缺少客户订单号":                                   "order for %s has no client order ID",
+		"订单 %s 的方向无效: %s":                                 "order %s has an invalid side: %s",
+		"订单 %s 的开平仓方向无效: %s %s":                           "order %s has an invalid open/close effect: %s %s",
+		"订单 %s 的股数必须大于 0":                                 "order %s quantity must be greater than 0",
+		"市价单 %s 不能设置限价":                                   "market order %s cannot have a limit price",
+		"限价单 %s 的限价必须大于 0":                                "limit order %s limit price must be greater than 0",
+		"订单 %s 的类型无效: %s":                                 "order %s has an invalid type: %s",
+		"订单 %s 的有效期无效: %s":                                "order %s has an invalid time in force: %s",
+		"IB 篮子订单 %s 的股数必须为整数: %s":                         "IB basket order %s quantity must be a whole number: %s",
+		"缺少标题行":                                           "missing header row",
+		"缺少 %s 列":                                         "missing %s column",
+		"第 %d 行的 %s 无效: %s":                               "line %d: invalid %s: %s",
+		"FIX 订单 %s 的字段包含非法字符: %q":                         "FIX order %s field contains an invalid character: %q",
+		"第 %d 行不是 FIX 4.2 消息":                             "line %d is not a FIX 4.2 message",
+		"第 %d 行的 CheckSum 不正确: %s":                        "line %d: incorrect CheckSum: %s",
+		"第 %d 行的字段无效: %s":                                 "line %d: invalid field: %s",
+		"第 %d 行的 BodyLength 不正确: %s":                      "line %d: incorrect BodyLength: %s",
+		"第 %d 行不是 NewOrderSingle 消息: 35=%s":               "line %d is not a NewOrderSingle message: 35=%s",
+		"第 %d 行的 Side、OpenClose、OrdType 或 TimeInForce 无效": "line %d: invalid Side, OpenClose, OrdType or TimeInForce",
+		"Alpaca 碎股订单 %s 只能是当日有效的市价单":                      "Alpaca fractional order %s must be a day market order",
+		"Alpaca 不支持碎股卖空: %s":                              "Alpaca does not support fractional short sales: %s",
+		"Alpaca 客户订单号超过 128 个字符: %s":                      "Alpaca client order ID exceeds 128 characters: %s",
+		"第 %d 个订单的 %s 无效: %s":                             "order %d: invalid %s: %s",
+	},
+
+	LangZH: {
+		// 主程序
+		"Invalid language: %s":                                                             "无效的语言: %s",
+		"Invalid start date format: %v":                                                    "开始日期格式无效: %v",
+		"Invalid end date format: %v":                                                      "结束日期格式无效: %v",
+		"Invalid allocation mode: %s":                                                      "无效的建仓比例模式: %s",
+		"Invalid target volatility: %.4g (must be positive)":                               "无效的目标波动率: %.4g（应大于 0）",
+		"Invalid volatility lookback: %d (must be at least 2)":                             "无效的波动率回看天数: %d（不能小于 2）",
+		"Invalid exposure range: %.4g - %.4g (need 0 <= min <= max, max > 0)":              "无效的仓位范围: %.4g - %.4g（应满足 0 <= 下限 <= 上限，上限大于 0）",
+		"Invalid signal price action: %s":                                                  "无效的信号价格处理方式: %s",
+		"Invalid weighting: %s":                                                            "无效的买入加权方式: %s",
+		"Invalid share precision: %d (must be 0-%d)":                                       "无效的碎股精度: %d（应为 0-%d）",
+		"Invalid allocation: %.4g (must be at least 0)":                                    "无效的建仓比例: %.4g（不能小于 0）",
+		"Invalid margin rate: %.4g (must be at least 0)":                                   "无效的融资利率: %.4g（不能小于 0）",
+		"Invalid maintenance margin: %.4g (must be below 1 / target exposure %.4g)":        "无效的维持保证金比例: %.4g（应低于 1 / 目标敞口 %.4g）",
+		"Invalid long/short exposure: gross %.4g must be positive and at least |net %.4g|": "无效的多空敞口: 总敞口 %.4g 必须为正且不低于 |净敞口 %.4g|",
+		"Invalid regime rule: %s":                                                          "无效的市场状态规则: %s",
+		"Invalid regime window: %d (must be positive)":                                     "无效的市场状态回看天数: %d（应大于 0）",
+		"Invalid regime drawdown: %.4g (must be between 0 and 1)":                          "无效的市场状态回撤阈值: %.4g（应在 0 和 1 之间）",
+		"Invalid regime scale: %.4g (must be 0-1)":                                         "无效的 risk-off 仓位系数: %.4g（应为 0-1）",
+		"-allocation-mode vol-target cannot be combined with -long-short":                  "-allocation-mode vol-target 不能与 -long-short 同时使用",
+		"Invalid output format: %s":                                                        "无效的输出格式: %s",
+		"Failed to create output directory: %v":                                            "创建输出目录失败: %v",
+		"=== Tech Titans Quantitative Investment Strategy Analysis ===\n":                  "=== Tech Titans 量化投资策略分析 ===\n",
+		"Initial Capital: $%.2f\n":                                                         "初始资金: $%.2f\n",
+		"Analysis Period: %s - %s\n":                                                       "分析区间: %s - %s\n",
+		"Stock Price Directory: %s\n":                                                      "股价数据目录: %s\n",
+		"History Directory: %s\n":                                                          "交易信号目录: %s\n",
+		"Signal File: %s (%s)\n":                                                           "信号文件: %s (%s)\n",
+		"Output Directory: %s\n":                                                           "输出目录: %s\n",
+		"Long/Short: gross %.2f, net %.2f, borrow fee %.2f%%\n":                            "多空: 总敞口 %.2f, 净敞口 %.2f, 融券费率 %.2f%%\n",
+		"Volatility Target: %.2f%% (exposure %.2f - %.2f, lookback %d days)\n":             "波动率目标: %.2f%% (仓位 %.2f - %.2f, 回看 %d 个交易日)\n",
+		"Allocation Ratio: %.2f\n":                                                         "建仓比例: %.2f\n",
+		"Margin: rate %.2f%%, maintenance %.2f%%\n":                                        "融资: 年化利率 %.2f%%, 维持保证金 %.2f%%\n",
+		"Regime Filter: %s %s(%d), risk-off scale %.2f\n":                                  "市场状态过滤: %s %s(%d), risk-off 仓位系数 %.2f\n",
+		"Sector Cap: %.2f%%\n":                                                             "板块上限: %.2f%%\n",
+		"Weighting: pl score\n":                                                            "买入加权: 按 pl 分数\n",
+		"Signal Price Check: skip buys filling %.2f%% above signal price\n":                "信号价格校验: 成交价高于信号价格 %.2f%% 以上时跳过买入\n",
+		"Fractional Shares: enabled (precision %d)\n":                                      "碎股: 已启用 (保留 %d 位小数)\n",
+		"Invalid data source: %v":                                                          "数据源无效: %v",
+		"Strategy execution failed: %v":                                                    "策略执行失败: %v",
+		"Charts generated successfully":                                                    "图表生成完成",
+		"Failed to open database: %v":                                                      "打开数据库失败: %v",
+		"\n=== Analysis Complete ===\n":                                                    "\n=== 分析完成 ===\n",
+		"Total execution time: %v\n":                                                       "总耗时: %v\n",
+		"Reports and charts saved to: %s\n":                                                "报告和图表已保存到: %s\n",
+		"\nGenerated files:":                                                               "\n已生成文件:",
+
+		// 子命令
+		"=== Signal Event Study ===\n":                   "=== 信号事件研究 ===\n",
+		"Benchmark: %s\n\n":                              "基准: %s\n\n",
+		"Event study failed: %v":                         "事件研究失败: %v",
+		"\n=== Event Study Complete ===\n":               "\n=== 事件研究完成 ===\n",
+		"Missing -to target path":                        "缺少 -to 目标路径",
+		"Invalid source: %v":                             "源数据无效: %v",
+		"Invalid target: %v":                             "目标无效: %v",
+		"Convert failed: %v":                             "转换失败: %v",
+		"Converted %d symbols from %s (%s) to %s (%s)\n": "已将 %d 只股票从 %s (%s) 转换到 %s (%s)\n",
+		"Invalid stock price source: %v":                 "股价数据源无效: %v",
+		"Failed to import stock prices: %v":              "导入股价失败: %v",
+		"Imported %d symbols (%d rows) from %s\n":        "已导入 %d 只股票 (%d 行)，来源 %s\n",
+		"Failed to import index prices: %v":              "导入指数价格失败: %v",
+		"Imported %d indexes (%d rows) from %s\n":        "已导入 %d 个指数 (%d 行)，来源 %s\n",
+		"Invalid signal source: %v":                      "交易信号数据源无效: %v",
+		"Failed to import signals: %v":                   "导入交易信号失败: %v",
+		"Imported %d signal dates (%d rows)\n":           "已导入 %d 个信号日期 (%d 行)\n",
+		"Database ready: %s\n":                           "数据库已就绪: %s\n",
+		"Failed to list runs: %v":                        "列出运行记录失败: %v",
+		"Run ID":                                         "运行 ID",
+		"Saved At":                                       "保存时间",
+		"Period":                                         "回测区间",
+		"Return %":                                       "收益率 %",
+		"Max DD %":                                       "最大回撤 %",
+		"Sharpe":                                         "夏普",
+		"Trades":                                         "交易次数",
+		"Usage: tech-titans markdown [flags] RUN_ID[=LABEL] ...": "用法: tech-titans markdown [参数] RUN_ID[=LABEL] ...",
+		"Failed to load run: %v":                                 "加载运行记录失败: %v",
+		"Failed to build report: %v":                             "生成报告数据失败: %v",
+		"Failed to create output file: %v":                       "创建输出文件失败: %v",
+		"Failed to write markdown report: %v":                    "写入 Markdown 报告失败: %v",
+		"Markdown report written to %s\n":                        "Markdown 报告已写入 %s\n",
+		"Usage: tech-titans merge [flags] NEW_EXPORT.csv ...":    "用法: tech-titans merge [参数] NEW_EXPORT.csv ...",
+		"-symbol can only be used with a single input file":      "-symbol 只能在单个输入文件时使用",
+		"Invalid keep mode: %s":                                  "无效的冲突处理方式: %s",
+		"Merge report written to %s\n":                           "合并报告已写入 %s\n",
+
+		// 图表
+		"no report data available":                           "没有报告数据",
+		"failed to create chart directory: %v":               "创建图表目录失败: %v",
+		"failed to generate portfolio value chart: %v":       "生成组合价值图失败: %v",
+		"failed to generate return chart: %v":                "生成收益率图失败: %v",
+		"failed to generate underwater chart: %v":            "生成回撤图失败: %v",
+		"failed to generate monthly returns heatmap: %v":     "生成月度收益热力图失败: %v",
+		"failed to generate attribution chart: %v":           "生成收益归因图失败: %v",
+		"failed to generate sector allocation chart: %v":     "生成板块配置图失败: %v",
+		"failed to generate asset allocation chart: %v":      "生成资产配置图失败: %v",
+		"failed to generate position distribution chart: %v": "生成持仓分布图失败: %v",
+		"failed to generate trading activity chart: %v":      "生成交易活动图失败: %v",
+		"Portfolio Value Trend":                              "组合价值走势",
+		"Monthly Portfolio Value Over Time":                  "月度组合价值变化",
+		"Date":                                               "日期",
+		"Value (USD)":                                        "价值（美元）",
+		"Total Value":                                        "总价值",
+		"Cash":                                               "现金",
+		"Stock Value":                                        "股票市值",
+		"Maximum":                                            "最大值",
+		"Minimum":                                            "最小值",
+		"Portfolio Value vs %s (Scaled to Initial Capital)":  "组合价值与 %s 对比（按初始资金缩放）",
+		"Equity Curve":                                       "净值曲线",
+		"Strategy":                                           "策略",
+		"Return Trend":                                       "收益率走势",
+		"Monthly and Cumulative Returns":                     "月度及累计收益率",
+		"Return (%)":                                         "收益率 (%)",
+		"Monthly Return (%)":                                 "月度收益率 (%)",
+		"Cumulative Return (%)":                              "累计收益率 (%)",
+		"Underwater Chart":                                   "水下回撤图",
+		"Drawdown from Running Peak (%)":                     "相对历史高点的回撤 (%)",
+		"Drawdown (%)":                                       "回撤 (%)",
+		"Max Drawdown":                                       "最大回撤",
+		"YTD":                                                "全年",
+		"Jan":                                                "1月",
+		"Feb":                                                "2月",
+		"Mar":                                                "3月",
+		"Apr":                                                "4月",
+		"May":                                                "5月",
+		"Jun":                                                "6月",
+		"Jul":                                                "7月",
+		"Aug":                                                "8月",
+		"Sep":                                                "9月",
+		"Oct":                                                "10月",
+		"Nov":                                                "11月",
+		"Dec":                                                "12月",
+		"Monthly Returns Heatmap":                            "月度收益率热力图",
+		"Monthly Return (%) by Year, with YTD":               "各年月度收益率 (%) 及全年收益",
+		"Month":                                              "月份",
+		"Year":                                               "年份",
+		"Performance Attribution":                            "收益归因",
+		"Top and Bottom Contributors to Total Return": "总收益贡献最大和最小的股票",
+		"Symbol":                              "股票",
+		"Contribution (%)":                    "贡献 (%)",
+		"Sector Allocation":                   "板块配置",
+		"Sector Weights Over Time (%)":        "板块权重变化 (%)",
+		"Weight (%)":                          "权重 (%)",
+		"Asset Allocation":                    "资产配置",
+		"As of %s":                            "截至 %s",
+		"Stocks":                              "股票",
+		"allocation":                          "配置",
+		"Position Distribution":               "持仓分布",
+		"Top Holdings by Market Value":        "市值最大的持仓",
+		"Market Value (USD)":                  "市值（美元）",
+		"Market Value":                        "市值",
+		"P&L":                                 "盈亏",
+		"Monthly Trading Activity":            "月度交易活动",
+		"Number of Buy and Sell Transactions": "买入和卖出交易笔数",
+		"Number of Transactions":              "交易笔数",
+		"Buy Transactions":                    "买入",
+		"Sell Transactions":                   "卖出",
+		"Position Weight Distribution":        "持仓权重分布",
+		"Others":                              "其他",
+		"Position Weight":                     "持仓权重",
+		"Cumulative Abnormal Return":          "累计超额收益",
+		"Average Excess Return vs %s After Signal (%%)": "信号后相对 %s 的平均超额收益 (%%)",
+		"Trading Days": "交易日",
+		"CAR (%)":      "累计超额收益 (%)",
+		"Risk-Off":     "市场 risk-off",
+
+		// CSV / Excel 报告汇总行
+		"Summary":           "汇总",
+		"Cash Balance":      "现金余额",
+		"Total Stock Value": "股票总市值",
+		"Initial Capital":   "初始资金",
+		"Start Date":        "开始日期",
+		"End Date":          "结束日期",
+		"Volatility":        "年化波动率",
+		"Win Rate":          "月度胜率",
+
+		// 单页报告
+		"Tech Titans Tear Sheet": "Tech Titans 回测报告",
+		"Final Value":            "最终价值",
+		"Positions":              "持仓数量",
+		"Months":                 "月数",
+		"Current Holdings":       "当前持仓",
+		"Drawdowns":              "回撤",
+		"Monthly Returns":        "月度收益",
+		"Attribution":            "收益归因",
+		"Trade Log":              "交易记录",
+		"failed to parse tear sheet template: %v": "解析单页报告模板失败: %v",
+		"failed to render tear sheet: %v":         "生成单页报告失败: %v",
+		"Total Return":                            "总收益率",
+		"Annualized Return":                       "年化收益率",
+		"Volatility (ann.)":                       "年化波动率",
+		"Sharpe Ratio":                            "夏普比率",
+		"Win Rate (monthly)":                      "月度胜率",
+		"Average Monthly Return":                  "平均月收益率",
+		"Total Trades":                            "交易次数",
+		"Holdings as of %s":                       "截至 %s 的持仓",
+		"Worst Drawdowns":                         "最大回撤区间",
+		"Top and Bottom Contributors":             "贡献最大和最小的股票",
+		"Closed":                                  "已平仓",
+		"Open":                                    "持有中",
+		"%d Trades":                               "%d 笔交易",
+		"Benchmark":                               "基准",
+		"Generated":                               "生成于",
+		"Performance Metrics":                     "绩效指标",
+		"Metric":                                  "指标",
+		"Buy Date":                                "买入日期",
+		"Buy Price":                               "买入价格",
+		"Price":                                   "价格",
+		"Shares":                                  "股数",
+		"P&L %":                                   "盈亏 %",
+		"Weight %":                                "权重 %",
+		"Rank":                                    "排名",
+		"Peak Date":                               "峰值日期",
+		"Trough Date":                             "谷底日期",
+		"Recovery Date":                           "恢复日期",
+		"Depth %":                                 "回撤幅度 %",
+		"Total Days":                              "持续天数",
+		"Total P&L":                               "总盈亏",
+		"Contribution %":                          "贡献 %",
+		"Periods Held":                            "持有期数",
+		"Average Weight %":                        "平均权重 %",
+		"Status":                                  "状态",
+		"Action":                                  "操作",
+		"Amount":                                  "金额",
+		"Reason":                                  "原因",
+
+		// 日志
+		"Invalid logging options: %v":                 "无效的日志参数: %v",
+		"Executing trading strategy":                  "执行交易策略",
+		"Strategy execution completed":                "策略执行完成",
+		"Generating reports":                          "生成报告",
+		"Generating charts":                           "生成图表",
+		"All charts generated":                        "图表已生成",
+		"Tear sheet generated":                        "单页报告已生成",
+		"Run saved":                                   "回测结果已保存",
+		"Failed to generate monthly report":           "生成月度报告失败",
+		"Failed to generate final position report":    "生成最终持仓报告失败",
+		"Failed to generate performance summary":      "生成业绩汇总报告失败",
+		"Failed to generate drawdown report":          "生成回撤分析报告失败",
+		"Failed to generate monthly returns table":    "生成月度收益率表失败",
+		"Failed to generate attribution report":       "生成归因报告失败",
+		"Failed to generate signal validation report": "生成信号校验报告失败",
+		"Failed to generate JSONL report":             "生成 JSONL 报告失败",
+		"Failed to generate Excel report":             "生成 Excel 报告失败",
+		"Failed to generate markdown summary":         "生成 Markdown 摘要失败",
+		"Failed to generate charts":                   "生成图表失败",
+		"Failed to generate tear sheet":               "生成单页报告失败",
+		"Failed to open database":                     "打开数据库失败",
+		"Failed to save run":                          "保存回测结果失败",
+		"Failed to write event study reports":         "写入事件研究报告失败",
+		"Failed to generate event study chart":        "生成事件研究图表失败",
+		"Failed to merge":                             "合并失败",
+		"Failed to write merge report":                "写入合并报告失败",
+		// 子命令：run、validate、compare、sweep、report、orders、serve
+		"Unexpected arguments: %s":   "多余的参数: %s",
+		"Failed to save run results": "保存回测结果文件失败",
+		"Level":                      "级别",
+		"Message":                    "说明",
+		"error":                      "错误",
+		"warning":                    "警告",
+		"Usage: tech-titans compare [flags] RUN_DIR[=LABEL] RUN_DIR[=LABEL] ...": "用法: tech-titans compare [参数] RUN_DIR[=LABEL] RUN_DIR[=LABEL] ...",
+		"Run":                                     "运行",
+		"Annual %":                                "年化 %",
+		"Δ Return":                                "Δ 收益率",
+		"Δ Max DD":                                "Δ 最大回撤",
+		"Failed to compare runs: %v":              "对比运行失败: %v",
+		"Failed to write comparison report: %v":   "写入对比报告失败: %v",
+		"\nComparison report written to %s\n":     "\n对比报告已写入 %s\n",
+		"\n=== A: %s vs B: %s ===\n":              "\n=== A: %s 对比 B: %s ===\n",
+		"A: %s vs B: %s":                          "A: %s 对比 B: %s",
+		"Run Comparison: %s":                      "运行对比: %s",
+		"Portfolio Value by Run":                  "各运行组合价值",
+		"Monthly values (%d of %d months differ)": "月度净值 (%d / %d 个月不同)",
+		"Trades only in %s (%d)":                  "仅 %s 中的交易 (%d)",
+		"Holdings differences in %s (%d)":         "%s 持仓差异 (%d)",
+		"Changed trades (%d)":                     "股数或金额不同的交易 (%d)",
+		"Value A":                                 "净值 A",
+		"Value B":                                 "净值 B",
+		"Δ Value":                                 "Δ 净值",
+		"Return A %":                              "收益率 A %",
+		"Return B %":                              "收益率 B %",
+		"Shares A":                                "股数 A",
+		"Shares B":                                "股数 B",
+		"Δ Shares":                                "Δ 股数",
+		"Notional A":                              "金额 A",
+		"Notional B":                              "金额 B",
+		"Δ Notional":                              "Δ 金额",
+		"Weight A %":                              "权重 A %",
+		"Weight B %":                              "权重 B %",
+		"Failed to load holdings: %v":             "加载持仓失败: %v",
+		"Invalid signal date: %v":                 "信号日无效: %v",
+		"Failed to find latest signal date: %v":   "查找最新信号日失败: %v",
+		"Invalid trade date: %v":                  "交易日无效: %v",
+		"Failed to generate orders: %v":           "生成订单失败: %v",
+		"%s (signals %s, estimated at the last close)": "%s（信号日 %s，按最近收盘价估算）",
+		"\nTarget portfolio\n\n":                       "\n目标组合\n\n",
+		"Total":                                        "合计",
+		"Usage: tech-titans sweep [flags] -param name=v1,v2,... [-param ...]": "用法: tech-titans sweep [参数] -param name=v1,v2,... [-param ...]",
+		"Cannot sweep parameter: %s":                                          "不能扫描该参数: %s",
+		"Invalid value for %s: %v":                                            "%s 的取值无效: %v",
+		"Running sweep combination":                                           "运行参数组合",
+		"Sweep combination failed":                                            "参数组合运行失败",
+		"Failed to write sweep report: %v":                                    "写入扫描报告失败: %v",
+		"\nSweep report written to %s\n":                                      "\n扫描报告已写入 %s\n",
+		"Usage: tech-titans report [flags] RUN_DIR":                           "用法: tech-titans report [参数] RUN_DIR",
+		"Usage: tech-titans orders [flags] RUN_DIR":                           "用法: tech-titans orders [参数] RUN_DIR",
+		"Invalid orders format: %s":                                           "无效的订单格式: %s",
+		"Failed to write orders: %v":                                          "写入订单失败: %v",
+		"Orders for %s (%d)\n\n":                                              "%s 的订单 (%d)\n\n",
+		"Orders written to %s\n":                                              "订单已写入 %s\n",
+		"Side":                                                                "方向",
+		"Notional":                                                            "金额",
+		"Usage: tech-titans serve [flags] [DIR]":                              "用法: tech-titans serve [参数] [DIR]",
+		"Not a directory: %s":                                                 "不是目录: %s",
+		"Serving %s on http://%s/\n":                                          "正在提供 %s，地址 http://%s/\n",
+		"Server failed: %v":                                                   "服务失败: %v",
+		"Runs":                                                                "运行列表",
+		"Directory":                                                           "目录",
+		"Files":                                                               "文件",
+		"No saved runs found":                                                 "没有找到已保存的运行",
+		"Order ID":                                                            "订单号",
+		"Type":                                                                "类型",
+		"Limit":                                                               "限价",
+		"TIF":                                                                 "有效期",
+		"Open/Close":                                                          "开平仓",
+		"Invalid broker orders: %v":                                           "无效的券商订单: %v",
+		"-check requires a broker format (ib-basket, fix42 or alpaca-json): %s": "-check 需要券商订单格式（ib-basket、fix42 或 alpaca-json）: %s",
+		"Failed to open order file: %v":                                         "打开订单文件失败: %v",
+		"Failed to parse order file: %v":                                        "解析订单文件失败: %v",
+		"%s: %d valid %s orders\n\n":                                            "%s: %d 个有效的 %s 订单\n\n",
+	},
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// CalculatePerformanceMetrics 根据月度报告计算绩效指标
+// 月度收益率不含首月（首月为建仓月）；波动率和夏普比率按月度收益率年化，无风险利率取 0
+func CalculatePerformanceMetrics(reports []*MonthlyReport) *PerformanceMetrics {
+	metrics := &PerformanceMetrics{}
+	if len(reports) == 0 {
+		return metrics
+	}
+
+	lastReport := reports[len(reports)-1]
+	metrics.TotalReturn = lastReport.CumulativeReturn
+
+	// 年化收益率 = (1 + 总收益率)^(1/年数) - 1
+	years := float64(len(reports)) / 12
+	if years > 0 {
+		growth := lastReport.CumulativeReturn.Add(decimal.NewFromInt(1)).InexactFloat64()
+		if growth > 0 {
+			metrics.AnnualizedReturn = decimal.NewFromFloat(math.Pow(growth, 1/years) - 1)
+		}
+	}
+
+	// 最大回撤
+	for _, drawdown := range drawdownSeries(reports) {
+		if drawdown.LessThan(metrics.MaxDrawdown) {
+			metrics.MaxDrawdown = drawdown
+		}
+	}
+
+	// 月度收益率统计
+	var monthlyReturns []float64
+	wins := 0
+	for _, report := range reports[1:] {
+		value := report.MonthlyReturn.InexactFloat64()
+		monthlyReturns = append(monthlyReturns, value)
+		if value > 0 {
+			wins++
+		}
+	}
+	if len(monthlyReturns) > 0 {
+		average := mean(monthlyReturns)
+		metrics.AverageReturn = decimal.NewFromFloat(average)
+		metrics.WinRate = decimal.NewFromInt(int64(wins)).Div(decimal.NewFromInt(int64(len(monthlyReturns))))
+
+		volatility := annualizedStdDev(monthlyReturns, 12)
+		metrics.Volatility = decimal.NewFromFloat(volatility)
+		if volatility > 0 {
+			metrics.SharpeRatio = decimal.NewFromFloat(average * 12 / volatility)
+		}
+	}
+
+	for _, report := range reports {
+		metrics.TotalTrades += len(report.TradingActions)
+	}
+
+	return metrics
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// MonthlyReturnGrid 按年 × 月排列的月度收益率
+type MonthlyReturnGrid struct {
+	Years   []int                           // 升序排列的年份
+	Returns map[int]map[int]decimal.Decimal // 年 -> 月(1-12) -> 月度收益率
+	YTD     map[int]decimal.Decimal         // 年 -> 年内累计收益率
+}
+
+// BuildMonthlyReturnGrid 将月度报告的收益率整理为年 × 月表格
+// 月份与报告日期一致（与收益率趋势图相同），YTD 为当年各月收益率的复利累计
+func BuildMonthlyReturnGrid(reports []*MonthlyReport) *MonthlyReturnGrid {
+	grid := &MonthlyReturnGrid{
+		Returns: make(map[int]map[int]decimal.Decimal),
+		YTD:     make(map[int]decimal.Decimal),
+	}
+
+	growth := make(map[int]decimal.Decimal)
+	for _, report := range reports {
+		year := report.Date.Year()
+		if _, exists := grid.Returns[year]; !exists {
+			grid.Returns[year] = make(map[int]decimal.Decimal)
+			grid.Years = append(grid.Years, year)
+			growth[year] = decimal.NewFromInt(1)
+		}
+		grid.Returns[year][int(report.Date.Month())] = report.MonthlyReturn
+		growth[year] = growth[year].Mul(report.MonthlyReturn.Add(decimal.NewFromInt(1)))
+	}
+
+	sort.Ints(grid.Years)
+	for year, g := range growth {
+		grid.YTD[year] = g.Sub(decimal.NewFromInt(1))
+	}
+
+	return grid
+}
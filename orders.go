@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunOrders 返回已保存运行中指定月份的交易，month 为空时取最后一个有交易的月份
+func RunOrders(reports []*MonthlyReport, month string) (time.Time, []TradingAction, error) {
+	if month != "" {
+		target, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf(T("无效的月份: %s"), month)
+		}
+		for _, report := range reports {
+			if report.Date.Year() == target.Year() && report.Date.Month() == target.Month() {
+				return report.Date, report.TradingActions, nil
+			}
+		}
+		return time.Time{}, nil, fmt.Errorf(T("运行中没有 %s 的月度报告"), month)
+	}
+
+	for i := len(reports) - 1; i >= 0; i-- {
+		if len(reports[i].TradingActions) > 0 {
+			return reports[i].Date, reports[i].TradingActions, nil
+		}
+	}
+	return time.Time{}, nil, nil
+}
+
+// WriteOrdersCSV 以 CSV 输出交易列表：日期、代码、方向、股数、价格、预估金额和原因
+func WriteOrdersCSV(w io.Writer, actions []TradingAction) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "symbol", "side", "shares", "price", "notional", "reason"}); err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+	for _, action := range actions {
+		err := writer.Write([]string{
+			action.Date.Format("2006-01-02"),
+			action.Symbol,
+			action.Action,
+			action.Shares.String(),
+			action.Price.String(),
+			action.Amount.StringFixed(2),
+			TradeReasonText(action.Reason),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PrintOrders 以表格输出交易列表
+func PrintOrders(w io.Writer, actions []TradingAction) {
+	fmt.Fprintf(w, "%-12s %-8s %-6s %12s %12s %14s  %s\n",
+		T("Date"), T("Symbol"), T("Side"), T("Shares"), T("Price"), T("Notional"), T("Reason"))
+	for _, action := range actions {
+		fmt.Fprintf(w, "%-12s %-8s %-6s %12s %12s %14s  %s\n",
+			action.Date.Format("2006-01-02"), action.Symbol, action.Action,
+			action.Shares.String(), action.Price.StringFixed(2), action.Amount.StringFixed(2), TradeReasonText(action.Reason))
+	}
+}
+
+// LoadHoldings 读取当前持仓 CSV，返回持仓和现金
+// 按标题识别列（不区分大小写，空格视为下划线）：symbol、shares 必填，buy_price、buy_date、cost_basis 可选，
+// 可以直接使用 final_position_report.csv；symbol 为 CASH 的行表示现金，金额写在 shares 列；shares 为空的行（如汇总行）忽略
+func LoadHoldings(filePath string) (map[string]*Position, decimal.Decimal, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, decimal.Zero, fmt.Errorf(T("无法打开持仓文件 %s: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, decimal.Zero, fmt.Errorf(T("读取持仓文件 %s 失败: %v"), filePath, err)
+	}
+	if len(records) == 0 {
+		return nil, decimal.Zero, fmt.Errorf(T("持仓文件 %s 为空"), filePath)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")] = i
+	}
+	for _, required := range []string{"symbol", "shares"} {
+		if _, ok := columns[required]; !ok {
+			return nil, decimal.Zero, fmt.Errorf(T("持仓文件 %s 缺少 %s 列"), filePath, required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	positions := make(map[string]*Position)
+	cash := decimal.Zero
+	for line, record := range records[1:] {
+		symbol := strings.ToUpper(field(record, "symbol"))
+		sharesText := field(record, "shares")
+		if symbol == "" || sharesText == "" {
+			continue
+		}
+		shares, err := parseDecimal(sharesText)
+		if err != nil {
+			return nil, decimal.Zero, fmt.Errorf(T("持仓文件第 %d 行的数量无效: %s"), line+2, sharesText)
+		}
+		if symbol == "CASH" {
+			cash = cash.Add(shares)
+			continue
+		}
+		if _, exists := positions[symbol]; exists {
+			return nil, decimal.Zero, fmt.Errorf(T("持仓文件中 %s 重复"), symbol)
+		}
+
+		position := &Position{Symbol: symbol, Shares: shares}
+		if text := field(record, "buy_price"); text != "" {
+			if position.BuyPrice, err = parseDecimal(text); err != nil {
+				return nil, decimal.Zero, fmt.Errorf(T("持仓文件第 %d 行的买入价格无效: %s"), line+2, text)
+			}
+			position.CostBasis = position.BuyPrice.Mul(shares)
+		}
+		if text := field(record, "cost_basis"); text != "" {
+			if position.CostBasis, err = parseDecimal(text); err != nil {
+				return nil, decimal.Zero, fmt.Errorf(T("持仓文件第 %d 行的成本无效: %s"), line+2, text)
+			}
+		}
+		if text := field(record, "buy_date"); text != "" {
+			if position.BuyDate, err = parseDate(text); err != nil {
+				return nil, decimal.Zero, err
+			}
+		}
+		positions[symbol] = position
+	}
+	return positions, cash, nil
+}
+
+// estimatedPriceSource 生成下一交易日订单时包装股价数据源：
+// 删除交易日所在月份的价格，并在交易日放入交易日（含）之前最后一个收盘价，
+// 使 processMonth 以该估算价格作为当月首个交易日的成交价
+type estimatedPriceSource struct {
+	source    PriceSource
+	tradeDate time.Time
+	cache     map[string]map[string]*StockPrice
+}
+
+// LoadStockPrice 实现 PriceSource
+func (source *estimatedPriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	if prices, ok := source.cache[symbol]; ok {
+		return prices, nil
+	}
+	original, err := source.source.LoadStockPrice(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	monthStart := time.Date(source.tradeDate.Year(), source.tradeDate.Month(), 1, 0, 0, 0, 0, time.UTC).Format("20060102")
+	prices := make(map[string]*StockPrice, len(original)+1)
+	for key, price := range original {
+		if key < monthStart {
+			prices[key] = price
+		}
+	}
+	if key, ok := lastDateBefore(original, source.tradeDate.AddDate(0, 0, 1)); ok {
+		estimate := *original[key]
+		estimate.Date = source.tradeDate
+		prices[source.tradeDate.Format("20060102")] = &estimate
+	}
+
+	source.cache[symbol] = prices
+	return prices, nil
+}
+
+// ListSymbols 实现 PriceSource
+func (source *estimatedPriceSource) ListSymbols() ([]string, error) {
+	return source.source.ListSymbols()
+}
+
+// LatestSignalDate 返回数据源中最新的信号日
+func LatestSignalDate(dataLoader *StockDataLoader) (time.Time, error) {
+	dates, err := dataLoader.ListSignalDates()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(dates) == 0 {
+		return time.Time{}, errors.New(T("没有交易信号"))
+	}
+	return dates[len(dates)-1], nil
+}
+
+// validateTradeDate 检查交易日：股价数据已覆盖到交易日时，交易日必须出现在股价数据中；
+// 交易日晚于股价数据时，必须是纽交所交易日
+func validateTradeDate(dataLoader *StockDataLoader, symbols []string, tradeDate time.Time) error {
+	key := tradeDate.Format("20060102")
+	covered := false
+	for _, symbol := range symbols {
+		prices, err := dataLoader.LoadStockPrice(symbol)
+		if err != nil {
+			continue
+		}
+		if prices[key] != nil {
+			return nil
+		}
+		for date := range prices {
+			if date > key {
+				covered = true
+				break
+			}
+		}
+	}
+	if covered {
+		return fmt.Errorf(T("交易日 %s 不在股价数据的交易日历中"), tradeDate.Format("2006-01-02"))
+	}
+	if !IsTradingDay(tradeDate) {
+		return fmt.Errorf(T("交易日 %s 不是纽交所交易日"), tradeDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// GenerateLiveOrders 按 processMonth 的逻辑，用当前持仓和现金处理 signalDate 的交易信号，生成 tradeDate 的订单，不运行完整回测
+// 成交价以 tradeDate（含）之前最后一个收盘价估算；持仓和现金取自券商账户，不再计提融资利息和融券费用；
+// 返回的月度报告中 TradingActions 为订单，Positions 为调仓后的目标组合
+func GenerateLiveOrders(config *Config, dataLoader *StockDataLoader, positions map[string]*Position, cash decimal.Decimal, signalDate, tradeDate time.Time) (*MonthlyReport, error) {
+	if tradeDate.Year() != signalDate.Year() || tradeDate.Month() != signalDate.Month() || tradeDate.Before(signalDate) {
+		return nil, fmt.Errorf(T("交易日 %s 应在信号日 %s 当月且不早于信号日"),
+			tradeDate.Format("2006-01-02"), signalDate.Format("2006-01-02"))
+	}
+
+	signals, err := dataLoader.LoadTradeSignals(signalDate)
+	if err != nil {
+		return nil, fmt.Errorf(T("加载交易信号失败: %v"), err)
+	}
+	symbols := make([]string, 0, len(signals)+len(positions))
+	for _, signal := range signals {
+		symbols = append(symbols, signal.Symbol)
+	}
+	for symbol := range positions {
+		symbols = append(symbols, symbol)
+	}
+	if err := validateTradeDate(dataLoader, symbols, tradeDate); err != nil {
+		return nil, err
+	}
+
+	dataLoader.SetPriceSource(&estimatedPriceSource{
+		source:    dataLoader.priceSource,
+		tradeDate: tradeDate,
+		cache:     make(map[string]map[string]*StockPrice),
+	})
+	strategy := NewTradingStrategy(dataLoader, config)
+	strategy.live = true
+	if err := strategy.prepare(); err != nil {
+		return nil, err
+	}
+
+	portfolio := &Portfolio{Cash: cash, Positions: positions, Value: cash}
+	if err := strategy.updatePortfolioValue(portfolio, signalDate); err != nil {
+		return nil, err
+	}
+	for symbol, position := range positions {
+		if position.CurrentPrice.IsZero() {
+			return nil, fmt.Errorf(T("无法估算 %s 的价格"), symbol)
+		}
+	}
+	// 没有上月的建仓比例，以当前多头仓位比例代替，目标比例更高时加仓已持有的多头
+	if longValue, _ := exposureValues(positions); portfolio.Value.IsPositive() {
+		portfolio.AllocationRatio = longValue.Div(portfolio.Value)
+	}
+
+	return strategy.processMonth(signalDate, portfolio, 0)
+}
+
+// PrintTargetPortfolio 输出调仓后的目标组合：按市值排序的持仓、现金和总价值
+func PrintTargetPortfolio(w io.Writer, report *MonthlyReport) {
+	var positions []*Position
+	for _, position := range report.Positions {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].MarketValue.GreaterThan(positions[j].MarketValue)
+	})
+
+	hundred := decimal.NewFromInt(100)
+	fmt.Fprintf(w, "%-8s %12s %12s %14s %10s\n", T("Symbol"), T("Shares"), T("Price"), T("Market Value"), T("Weight %"))
+	for _, position := range positions {
+		fmt.Fprintf(w, "%-8s %12s %12s %14s %10s\n", position.Symbol, position.Shares.String(),
+			position.CurrentPrice.StringFixed(2), position.MarketValue.StringFixed(2),
+			position.Weight.Mul(hundred).StringFixed(2))
+	}
+	fmt.Fprintf(w, "\n%-8s %40s\n", T("Cash"), report.Cash.StringFixed(2))
+	fmt.Fprintf(w, "%-8s %40s\n", T("Total"), report.TotalValue.StringFixed(2))
+}
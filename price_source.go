@@ -0,0 +1,650 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 股价数据格式
+const (
+	PriceFormatYahooCSV = "yahoo-csv" // DIR/SYMBOL.csv，Yahoo 导出格式（倒序、成交量含千位分隔符）
+	PriceFormatISOCSV   = "iso-csv"   // DIR/SYMBOL.csv，ISO 日期升序，小写列名，无千位分隔符
+	PriceFormatJSON     = "json"      // DIR/SYMBOL.json，按日期升序的记录数组
+	PriceFormatLongCSV  = "long-csv"  // 单个 CSV 文件，列为 date,symbol,open,high,low,close,adj_close,volume
+	PriceFormatSQLite   = "sqlite"    // SQLite 数据库中的个股股价（由 import 命令导入）
+)
+
+// isoPriceHeader iso-csv 与 long-csv 共用的列名（long-csv 在 date 之后多一列 symbol）
+var isoPriceHeader = []string{"date", "open", "high", "low", "close", "adj_close", "volume"}
+
+// PriceSource 股价数据源
+type PriceSource interface {
+	// LoadStockPrice 加载指定股票的价格数据，键为 YYYYMMDD
+	LoadStockPrice(symbol string) (map[string]*StockPrice, error)
+	// ListSymbols 列出数据源中的所有股票代码（升序）
+	ListSymbols() ([]string, error)
+}
+
+// PriceWriter 股价数据写入器，用于格式转换
+type PriceWriter interface {
+	// WriteStockPrice 写入单只股票的价格数据
+	WriteStockPrice(symbol string, prices map[string]*StockPrice) error
+	// Close 完成写入
+	Close() error
+}
+
+// NewPriceSource 根据格式创建股价数据源，path 为目录（long-csv 为文件，sqlite 为数据库）
+func NewPriceSource(format, path string) (PriceSource, error) {
+	switch format {
+	case "", PriceFormatYahooCSV:
+		return NewYahooCSVPriceSource(path), nil
+	case PriceFormatISOCSV:
+		return NewISOCSVPriceSource(path), nil
+	case PriceFormatJSON:
+		return NewJSONPriceSource(path), nil
+	case PriceFormatLongCSV:
+		return NewLongCSVPriceSource(path), nil
+	case PriceFormatSQLite:
+		store, err := OpenStore(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewStorePriceSource(store, StorePriceSourceStock), nil
+	default:
+		return nil, fmt.Errorf(T("不支持的股价格式: %s"), format)
+	}
+}
+
+// NewPriceWriter 根据格式创建股价数据写入器，path 为目录（long-csv 为文件）
+func NewPriceWriter(format, path string) (PriceWriter, error) {
+	switch format {
+	case PriceFormatYahooCSV, PriceFormatISOCSV, PriceFormatJSON:
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf(T("创建输出目录失败: %v"), err)
+		}
+		return &dirPriceWriter{format: format, dir: path}, nil
+	case PriceFormatLongCSV:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf(T("创建输出目录失败: %v"), err)
+		}
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf(T("创建文件 %s 失败: %v"), path, err)
+		}
+		writer := csv.NewWriter(file)
+		header := append([]string{"date", "symbol"}, isoPriceHeader[1:]...)
+		if err := writer.Write(header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf(T("写入标题失败: %v"), err)
+		}
+		return &longCSVPriceWriter{file: file, writer: writer}, nil
+	default:
+		return nil, fmt.Errorf(T("不支持的股价格式: %s"), format)
+	}
+}
+
+// YahooCSVPriceSource Yahoo 导出的 CSV 股价文件目录
+type YahooCSVPriceSource struct {
+	dir string
+}
+
+// NewYahooCSVPriceSource 创建 Yahoo CSV 股价数据源
+func NewYahooCSVPriceSource(dir string) *YahooCSVPriceSource {
+	return &YahooCSVPriceSource{dir: dir}
+}
+
+// LoadStockPrice 加载指定股票的价格数据
+func (source *YahooCSVPriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	return loadYahooCSV(filepath.Join(source.dir, symbol+".csv"))
+}
+
+// ListSymbols 列出目录中所有 CSV 文件对应的股票代码
+func (source *YahooCSVPriceSource) ListSymbols() ([]string, error) {
+	return listSymbolFiles(source.dir, ".csv")
+}
+
+// loadYahooCSV 读取单个 Yahoo 导出的 CSV 股价文件
+func loadYahooCSV(filePath string) (map[string]*StockPrice, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("无法打开股价文件 %s: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// 设置CSV reader不严格检查字段数量，因为Volume字段可能包含逗号
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read() // 读取表头
+	if err != nil {
+		return nil, fmt.Errorf(T("读取CSV表头失败: %v"), err)
+	}
+
+	// 查找列索引
+	columnIndex := make(map[string]int)
+	for i, col := range header {
+		columnIndex[col] = i
+	}
+
+	prices := make(map[string]*StockPrice)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(T("读取CSV记录失败: %v"), err)
+		}
+
+		// 解析日期
+		dateStr := strings.Trim(record[columnIndex["Date"]], `"`)
+		date, err := parseDate(dateStr)
+		if err != nil {
+			slog.Warn(T("无法解析日期，跳过该行"), "file", filePath, "date", dateStr, "err", err)
+			continue
+		}
+
+		// 处理Volume字段可能被逗号分割的情况
+		volumeIdx := columnIndex["Volume"]
+		if len(record) > len(header) {
+			// 如果字段数量超过表头，说明Volume字段被分割了
+			extraFields := len(record) - len(header)
+			// 将Volume及其后续字段重新组合
+			volumeStr := ""
+			for i := 0; i <= extraFields; i++ {
+				if volumeIdx+i < len(record) {
+					volumeStr += record[volumeIdx+i]
+				}
+			}
+			// 创建修正后的记录
+			correctedRecord := make([]string, len(header))
+			copy(correctedRecord, record[:volumeIdx])
+			correctedRecord[volumeIdx] = volumeStr
+			record = correctedRecord
+		}
+
+		// 检查记录字段数量是否匹配表头
+		if len(record) != len(header) {
+			slog.Warn(T("字段数量不匹配，跳过该行"), "file", filePath, "line", len(prices)+2)
+			continue
+		}
+
+		// 解析价格数据
+		open, _ := parseDecimal(record[columnIndex["Open"]])
+		high, _ := parseDecimal(record[columnIndex["High"]])
+		low, _ := parseDecimal(record[columnIndex["Low"]])
+		close, _ := parseDecimal(record[columnIndex["Close"]])
+
+		// 处理调整后收盘价
+		adjClose := close // 默认使用 Close 的值
+		if adjCloseIdx, exists := columnIndex["Adj Close"]; exists && adjCloseIdx < len(record) {
+			// 如果存在 Adj Close 字段，则使用该字段的值
+			if adjCloseVal, err := parseDecimal(record[adjCloseIdx]); err == nil && !adjCloseVal.IsZero() {
+				adjClose = adjCloseVal
+			}
+		}
+		// 注意：对于没有 Adj Close 字段的文件（如 UCTT.csv），adjClose 会使用 close 的值
+
+		// 解析成交量
+		volume, _ := parseVolume(record[columnIndex["Volume"]])
+
+		// 跳过无效数据行（如分红等情况）
+		if close.IsZero() || adjClose.IsZero() {
+			continue
+		}
+
+		stockPrice := &StockPrice{
+			Date:     date,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    close,
+			AdjClose: adjClose,
+			Volume:   volume,
+		}
+
+		dateKey := date.Format("20060102")
+		prices[dateKey] = stockPrice
+	}
+
+	return prices, nil
+}
+
+// ISOCSVPriceSource ISO 日期 CSV 股价文件目录
+type ISOCSVPriceSource struct {
+	dir string
+}
+
+// NewISOCSVPriceSource 创建 ISO CSV 股价数据源
+func NewISOCSVPriceSource(dir string) *ISOCSVPriceSource {
+	return &ISOCSVPriceSource{dir: dir}
+}
+
+// LoadStockPrice 加载指定股票的价格数据
+func (source *ISOCSVPriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	filePath := filepath.Join(source.dir, symbol+".csv")
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("无法打开股价文件 %s: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	prices := make(map[string]*StockPrice)
+	err = readISOPriceCSV(file, filePath, func(_ string, price *StockPrice) {
+		prices[price.Date.Format("20060102")] = price
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// ListSymbols 列出目录中所有 CSV 文件对应的股票代码
+func (source *ISOCSVPriceSource) ListSymbols() ([]string, error) {
+	return listSymbolFiles(source.dir, ".csv")
+}
+
+// JSONPriceSource JSON 股价文件目录
+type JSONPriceSource struct {
+	dir string
+}
+
+// jsonPrice JSON 股价记录
+type jsonPrice struct {
+	Date     string          `json:"date"`
+	Open     decimal.Decimal `json:"open"`
+	High     decimal.Decimal `json:"high"`
+	Low      decimal.Decimal `json:"low"`
+	Close    decimal.Decimal `json:"close"`
+	AdjClose decimal.Decimal `json:"adj_close"`
+	Volume   int64           `json:"volume"`
+}
+
+// NewJSONPriceSource 创建 JSON 股价数据源
+func NewJSONPriceSource(dir string) *JSONPriceSource {
+	return &JSONPriceSource{dir: dir}
+}
+
+// LoadStockPrice 加载指定股票的价格数据
+func (source *JSONPriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	filePath := filepath.Join(source.dir, symbol+".json")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("无法打开股价文件 %s: %v"), filePath, err)
+	}
+
+	var records []jsonPrice
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf(T("解析股价文件 %s 失败: %v"), filePath, err)
+	}
+
+	prices := make(map[string]*StockPrice)
+	for i, record := range records {
+		date, err := time.Parse("2006-01-02", record.Date)
+		if err != nil {
+			return nil, fmt.Errorf(T("股价文件 %s 第 %d 条记录: %v"), filePath, i+1, err)
+		}
+		if record.Close.IsZero() {
+			continue
+		}
+		adjClose := record.AdjClose
+		if adjClose.IsZero() {
+			adjClose = record.Close
+		}
+		prices[date.Format("20060102")] = &StockPrice{
+			Date:     date,
+			Open:     record.Open,
+			High:     record.High,
+			Low:      record.Low,
+			Close:    record.Close,
+			AdjClose: adjClose,
+			Volume:   record.Volume,
+		}
+	}
+
+	return prices, nil
+}
+
+// ListSymbols 列出目录中所有 JSON 文件对应的股票代码
+func (source *JSONPriceSource) ListSymbols() ([]string, error) {
+	return listSymbolFiles(source.dir, ".json")
+}
+
+// LongCSVPriceSource 单个多股票长格式 CSV 文件
+type LongCSVPriceSource struct {
+	filePath string
+	prices   map[string]map[string]*StockPrice // 股票代码 -> 日期键 -> 价格
+}
+
+// NewLongCSVPriceSource 创建长格式 CSV 股价数据源
+func NewLongCSVPriceSource(filePath string) *LongCSVPriceSource {
+	return &LongCSVPriceSource{filePath: filePath}
+}
+
+// LoadStockPrice 加载指定股票的价格数据
+func (source *LongCSVPriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	prices, exists := source.prices[symbol]
+	if !exists {
+		return nil, fmt.Errorf(T("股价文件 %s 中没有 %s 的数据"), source.filePath, symbol)
+	}
+	return prices, nil
+}
+
+// ListSymbols 列出文件中的所有股票代码
+func (source *LongCSVPriceSource) ListSymbols() ([]string, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	symbols := make([]string, 0, len(source.prices))
+	for symbol := range source.prices {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// load 首次使用时读取整个文件并按股票代码分组
+func (source *LongCSVPriceSource) load() error {
+	if source.prices != nil {
+		return nil
+	}
+
+	file, err := os.Open(source.filePath)
+	if err != nil {
+		return fmt.Errorf(T("无法打开股价文件 %s: %v"), source.filePath, err)
+	}
+	defer file.Close()
+
+	prices := make(map[string]map[string]*StockPrice)
+	err = readISOPriceCSV(file, source.filePath, func(symbol string, price *StockPrice) {
+		if prices[symbol] == nil {
+			prices[symbol] = make(map[string]*StockPrice)
+		}
+		prices[symbol][price.Date.Format("20060102")] = price
+	})
+	if err != nil {
+		return err
+	}
+
+	source.prices = prices
+	return nil
+}
+
+// readISOPriceCSV 读取 iso-csv 或 long-csv 格式的股价，symbol 列不存在时传入空字符串
+func readISOPriceCSV(r io.Reader, filePath string, handle func(symbol string, price *StockPrice)) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read() // 读取表头
+	if err != nil {
+		return fmt.Errorf(T("读取CSV表头失败: %v"), err)
+	}
+
+	// 查找列索引
+	columnIndex := make(map[string]int)
+	for i, col := range header {
+		columnIndex[strings.TrimSpace(col)] = i
+	}
+	for _, col := range []string{"date", "open", "high", "low", "close", "volume"} {
+		if _, exists := columnIndex[col]; !exists {
+			return fmt.Errorf(T("股价文件 %s 缺少 %s 列"), filePath, col)
+		}
+	}
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf(T("读取CSV记录失败: %v"), err)
+		}
+		line++
+
+		date, err := time.Parse("2006-01-02", record[columnIndex["date"]])
+		if err != nil {
+			return fmt.Errorf(T("股价文件 %s 第 %d 行: %v"), filePath, line, err)
+		}
+
+		open, _ := parseDecimal(record[columnIndex["open"]])
+		high, _ := parseDecimal(record[columnIndex["high"]])
+		low, _ := parseDecimal(record[columnIndex["low"]])
+		close, _ := parseDecimal(record[columnIndex["close"]])
+		volume, _ := parseVolume(record[columnIndex["volume"]])
+		if close.IsZero() {
+			continue
+		}
+
+		adjClose := close
+		if adjCloseIdx, exists := columnIndex["adj_close"]; exists {
+			if adjCloseVal, err := parseDecimal(record[adjCloseIdx]); err == nil && !adjCloseVal.IsZero() {
+				adjClose = adjCloseVal
+			}
+		}
+
+		symbol := ""
+		if symbolIdx, exists := columnIndex["symbol"]; exists {
+			symbol = strings.TrimSpace(record[symbolIdx])
+		}
+
+		handle(symbol, &StockPrice{
+			Date:     date,
+			Open:     open,
+			High:     high,
+			Low:      low,
+			Close:    close,
+			AdjClose: adjClose,
+			Volume:   volume,
+		})
+	}
+
+	return nil
+}
+
+// listSymbolFiles 列出目录中指定扩展名的文件对应的股票代码（升序）
+func listSymbolFiles(dir, ext string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return nil, fmt.Errorf(T("查找股价文件失败: %v"), err)
+	}
+
+	symbols := make([]string, 0, len(files))
+	for _, file := range files {
+		symbols = append(symbols, strings.TrimSuffix(filepath.Base(file), ext))
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// sortedPriceKeys 返回按日期升序排列的日期键
+func sortedPriceKeys(prices map[string]*StockPrice) []string {
+	keys := make([]string, 0, len(prices))
+	for key := range prices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isoPriceRecord 将股价转换为 iso-csv 的字段（不含 symbol 列）
+func isoPriceRecord(price *StockPrice) []string {
+	return []string{
+		price.Date.Format("2006-01-02"),
+		formatPrice(price.Open),
+		formatPrice(price.High),
+		formatPrice(price.Low),
+		formatPrice(price.Close),
+		formatPrice(price.AdjClose),
+		strconv.FormatInt(price.Volume, 10),
+	}
+}
+
+// formatPrice 按原始小数位数格式化价格，保留 "40.90" 这类尾随零
+func formatPrice(price decimal.Decimal) string {
+	if price.Exponent() < 0 {
+		return price.StringFixed(-price.Exponent())
+	}
+	return price.String()
+}
+
+// writeYahooCSV 按 Yahoo 导出格式写入股价：按日期倒序，成交量带千位分隔符并加引号
+func writeYahooCSV(w io.Writer, prices map[string]*StockPrice) error {
+	keys := sortedPriceKeys(prices)
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Date", "Open", "High", "Low", "Close", "Adj Close", "Volume"}); err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		record := isoPriceRecord(prices[keys[i]])
+		record[6] = formatThousands(prices[keys[i]].Volume)
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf(T("写入股价数据失败: %v"), err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// dirPriceWriter 按股票分文件写入的股价写入器（yahoo-csv / iso-csv / json）
+type dirPriceWriter struct {
+	format string
+	dir    string
+}
+
+// WriteStockPrice 写入单只股票的价格文件
+func (w *dirPriceWriter) WriteStockPrice(symbol string, prices map[string]*StockPrice) error {
+	keys := sortedPriceKeys(prices)
+
+	if w.format == PriceFormatJSON {
+		records := make([]jsonPrice, 0, len(keys))
+		for _, key := range keys {
+			price := prices[key]
+			records = append(records, jsonPrice{
+				Date:     price.Date.Format("2006-01-02"),
+				Open:     price.Open,
+				High:     price.High,
+				Low:      price.Low,
+				Close:    price.Close,
+				AdjClose: price.AdjClose,
+				Volume:   price.Volume,
+			})
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf(T("序列化 %s 股价失败: %v"), symbol, err)
+		}
+		return os.WriteFile(filepath.Join(w.dir, symbol+".json"), data, 0644)
+	}
+
+	filePath := filepath.Join(w.dir, symbol+".csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	if w.format == PriceFormatYahooCSV {
+		return writeYahooCSV(file, prices)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(isoPriceHeader); err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+	for _, key := range keys {
+		if err := writer.Write(isoPriceRecord(prices[key])); err != nil {
+			return fmt.Errorf(T("写入股价数据失败: %v"), err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Close 按股票分文件写入时无需额外操作
+func (w *dirPriceWriter) Close() error {
+	return nil
+}
+
+// longCSVPriceWriter 写入单个多股票长格式 CSV 文件
+type longCSVPriceWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// WriteStockPrice 追加单只股票的价格数据
+func (w *longCSVPriceWriter) WriteStockPrice(symbol string, prices map[string]*StockPrice) error {
+	for _, key := range sortedPriceKeys(prices) {
+		record := isoPriceRecord(prices[key])
+		record = append([]string{record[0], symbol}, record[1:]...)
+		if err := w.writer.Write(record); err != nil {
+			return fmt.Errorf(T("写入股价数据失败: %v"), err)
+		}
+	}
+	return nil
+}
+
+// Close 刷新缓冲并关闭文件
+func (w *longCSVPriceWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// formatThousands 格式化带千位分隔符的整数
+func formatThousands(value int64) string {
+	digits := strconv.FormatInt(value, 10)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var builder strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteRune(digit)
+	}
+	if negative {
+		return "-" + builder.String()
+	}
+	return builder.String()
+}
+
+// ConvertPrices 将股价数据源中的所有股票写入目标格式，返回转换的股票数量
+func ConvertPrices(source PriceSource, writer PriceWriter) (int, error) {
+	symbols, err := source.ListSymbols()
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, symbol := range symbols {
+		prices, err := source.LoadStockPrice(symbol)
+		if err != nil {
+			slog.Warn(T("跳过股票"), "symbol", symbol, "err", err)
+			continue
+		}
+		if err := writer.WriteStockPrice(symbol, prices); err != nil {
+			writer.Close()
+			return converted, err
+		}
+		converted++
+	}
+
+	return converted, writer.Close()
+}
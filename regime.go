@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 市场状态
+const (
+	RegimeRiskOn  = "risk-on"
+	RegimeRiskOff = "risk-off"
+)
+
+// 市场状态判断规则
+const (
+	RegimeRuleMovingAverage = "ma"       // 指数价格低于N日均线时为 risk-off
+	RegimeRuleDrawdown      = "drawdown" // 指数自N日高点回撤超过阈值时为 risk-off
+)
+
+// RegimeState 某个月的市场状态
+type RegimeState struct {
+	Date       time.Time       // 指数交易日
+	State      string          // RegimeRiskOn 或 RegimeRiskOff
+	Scale      decimal.Decimal // 对建仓比例的缩放系数
+	IndexPrice decimal.Decimal // 指数收盘价
+	Reference  decimal.Decimal // 参考值：均线价格或回撤幅度
+}
+
+// RegimeFilter 基于指数走势的市场状态过滤器
+type RegimeFilter struct {
+	config     *Config
+	dataLoader *StockDataLoader
+	prices     map[string]*StockPrice
+	dates      []string // 升序排列的日期键
+}
+
+// NewRegimeFilter 创建市场状态过滤器，从指数目录加载基准指数数据
+func NewRegimeFilter(config *Config) (*RegimeFilter, error) {
+	switch config.RegimeRule {
+	case RegimeRuleMovingAverage, RegimeRuleDrawdown:
+	default:
+		return nil, fmt.Errorf(T("不支持的市场状态规则: %s"), config.RegimeRule)
+	}
+	if config.RegimeWindow <= 0 {
+		return nil, fmt.Errorf(T("市场状态窗口必须大于0: %d"), config.RegimeWindow)
+	}
+
+	dataLoader := NewStockDataLoader(config.IndexPriceDir, config.HistoryDir)
+	indexSource, err := newIndexPriceSource(config)
+	if err != nil {
+		return nil, err
+	}
+	dataLoader.SetPriceSource(indexSource)
+	prices, err := dataLoader.LoadStockPrice(config.RegimeSymbol)
+	if err != nil {
+		return nil, fmt.Errorf(T("加载指数 %s 数据失败: %v"), config.RegimeSymbol, err)
+	}
+
+	dates := make([]string, 0, len(prices))
+	for dateKey := range prices {
+		dates = append(dates, dateKey)
+	}
+	sort.Strings(dates)
+
+	return &RegimeFilter{
+		config:     config,
+		dataLoader: dataLoader,
+		prices:     prices,
+		dates:      dates,
+	}, nil
+}
+
+// Evaluate 计算指定月份首个交易日的市场状态
+func (rf *RegimeFilter) Evaluate(date time.Time) (*RegimeState, error) {
+	tradingDay, err := rf.dataLoader.GetFirstTradingDay(date.Year(), int(date.Month()), rf.prices)
+	if err != nil {
+		return nil, err
+	}
+
+	dateKey := tradingDay.Format("20060102")
+	index := sort.SearchStrings(rf.dates, dateKey)
+	start := index - rf.config.RegimeWindow + 1
+	if start < 0 {
+		start = 0
+	}
+	window := rf.dates[start : index+1]
+	price := rf.prices[dateKey].Close
+
+	state := &RegimeState{
+		Date:       tradingDay,
+		State:      RegimeRiskOn,
+		Scale:      decimal.NewFromInt(1),
+		IndexPrice: price,
+	}
+
+	riskOff := false
+	switch rf.config.RegimeRule {
+	case RegimeRuleMovingAverage:
+		sum := decimal.Zero
+		for _, key := range window {
+			sum = sum.Add(rf.prices[key].Close)
+		}
+		average := sum.Div(decimal.NewFromInt(int64(len(window))))
+		state.Reference = average
+		riskOff = price.LessThan(average)
+	case RegimeRuleDrawdown:
+		peak := decimal.Zero
+		for _, key := range window {
+			if rf.prices[key].Close.GreaterThan(peak) {
+				peak = rf.prices[key].Close
+			}
+		}
+		drawdown := price.Div(peak).Sub(decimal.NewFromInt(1))
+		state.Reference = drawdown
+		riskOff = drawdown.LessThan(decimal.NewFromFloat(-rf.config.RegimeDrawdownLimit))
+	}
+
+	if riskOff {
+		state.State = RegimeRiskOff
+		state.Scale = decimal.NewFromFloat(rf.config.RegimeRiskOffScale)
+	}
+
+	return state, nil
+}
+
+// evaluateRegime 计算当月市场状态，未启用过滤器时返回 nil
+func (strategy *TradingStrategy) evaluateRegime(date time.Time) *RegimeState {
+	if strategy.regime == nil {
+		return nil
+	}
+
+	state, err := strategy.regime.Evaluate(date)
+	if err != nil {
+		slog.Warn(T("计算市场状态失败"), "month", date.Format("2006-01"), "err", err)
+		return nil
+	}
+
+	slog.Info(T("市场状态"), "month", date.Format("2006-01"), "state", state.State,
+		"index", strategy.config.RegimeSymbol, "price", state.IndexPrice.StringFixed(2),
+		"reference", state.Reference.StringFixed(4), "scale", state.Scale.StringFixed(2))
+	return state
+}
+
+// regimeLabel 返回报告中使用的市场状态标签
+func regimeLabel(state *RegimeState) string {
+	if state == nil {
+		return ""
+	}
+	return state.State
+}
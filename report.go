@@ -2,12 +2,15 @@ package main
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -30,7 +33,7 @@ func (rg *ReportGenerator) GenerateMonthlyReport(report *MonthlyReport) error {
 	outputDir := filepath.Join(rg.config.OutputDir, "monthly_reports")
 	err := os.MkdirAll(outputDir, 0755)
 	if err != nil {
-		return fmt.Errorf("创建输出目录失败: %v", err)
+		return fmt.Errorf(T("创建输出目录失败: %v"), err)
 	}
 
 	// 生成文件名
@@ -41,7 +44,7 @@ func (rg *ReportGenerator) GenerateMonthlyReport(report *MonthlyReport) error {
 	// 创建CSV文件
 	file, err := os.Create(filePath)
 	if err != nil {
-		return fmt.Errorf("创建报告文件失败: %v", err)
+		return fmt.Errorf(T("创建报告文件失败: %v"), err)
 	}
 	defer file.Close()
 
@@ -56,27 +59,40 @@ func (rg *ReportGenerator) GenerateMonthlyReport(report *MonthlyReport) error {
 	}
 	err = writer.Write(headers)
 	if err != nil {
-		return fmt.Errorf("写入标题失败: %v", err)
+		return fmt.Errorf(T("写入标题失败: %v"), err)
 	}
 
-	// 写入持仓数据
+	// 写入多头持仓数据
+	var shortPositions []*Position
 	for _, position := range report.Positions {
-		row := []string{
-			position.Symbol,
-			position.BuyDate.Format("2006-01-02"),
-			position.BuyPrice.StringFixed(2),
-			position.CurrentPrice.StringFixed(2),
-			strconv.Itoa(position.Shares),
-			position.MarketValue.StringFixed(2),
-			position.CostBasis.StringFixed(2),
-			position.PnL.StringFixed(2),
-			position.PnLPercent.Mul(decimal.NewFromInt(100)).StringFixed(2),
-			position.Weight.Mul(decimal.NewFromInt(100)).StringFixed(2),
-			rg.formatTradingActions(report.TradingActions, position.Symbol),
+		if isShort(position) {
+			shortPositions = append(shortPositions, position)
+			continue
 		}
-		err = writer.Write(row)
+		err = writer.Write(rg.monthlyPositionRow(report, position))
 		if err != nil {
-			return fmt.Errorf("写入持仓数据失败: %v", err)
+			return fmt.Errorf(T("写入持仓数据失败: %v"), err)
+		}
+	}
+
+	// 写入空头持仓数据
+	if len(shortPositions) > 0 {
+		sectionRows := [][]string{
+			{"", "", "", "", "", "", "", "", "", "", ""},
+			{"Short Positions", "", "", "", "", "", "", "", "", "", ""},
+			headers,
+		}
+		for _, row := range sectionRows {
+			err = writer.Write(row)
+			if err != nil {
+				return fmt.Errorf(T("写入空头标题失败: %v"), err)
+			}
+		}
+		for _, position := range shortPositions {
+			err = writer.Write(rg.monthlyPositionRow(report, position))
+			if err != nil {
+				return fmt.Errorf(T("写入持仓数据失败: %v"), err)
+			}
 		}
 	}
 
@@ -90,40 +106,86 @@ func (rg *ReportGenerator) GenerateMonthlyReport(report *MonthlyReport) error {
 		{"Monthly Return %", report.MonthlyReturn.Mul(decimal.NewFromInt(100)).StringFixed(2), "", "", "", "", "", "", "", "", ""},
 		{"Cumulative Return %", report.CumulativeReturn.Mul(decimal.NewFromInt(100)).StringFixed(2), "", "", "", "", "", "", "", "", ""},
 	}
+	// 融资、多空、市场状态和波动率目标的字段仅在开启时追加在后面
+	for _, field := range rg.summaryFields() {
+		summaryRows = append(summaryRows, []string{field.csvName(), field.csvValue(report), "", "", "", "", "", "", "", "", ""})
+	}
 
 	for _, row := range summaryRows {
 		err = writer.Write(row)
 		if err != nil {
-			return fmt.Errorf("写入汇总信息失败: %v", err)
+			return fmt.Errorf(T("写入汇总信息失败: %v"), err)
+		}
+	}
+
+	// 写入板块权重
+	if len(report.SectorWeights) > 0 {
+		var sectors []string
+		for sector := range report.SectorWeights {
+			sectors = append(sectors, sector)
+		}
+		sort.Strings(sectors)
+
+		sectorRows := [][]string{
+			{"", "", "", "", "", "", "", "", "", "", ""},
+			{"Sector Weights %", "", "", "", "", "", "", "", "", "", ""},
+		}
+		for _, sector := range sectors {
+			sectorRows = append(sectorRows, []string{
+				sector, report.SectorWeights[sector].Mul(decimal.NewFromInt(100)).StringFixed(2), "", "", "", "", "", "", "", "", "",
+			})
+		}
+		for _, row := range sectorRows {
+			err = writer.Write(row)
+			if err != nil {
+				return fmt.Errorf(T("写入板块权重失败: %v"), err)
+			}
 		}
 	}
 
-	fmt.Printf("月度报告已生成: %s\n", filePath)
+	slog.Info(T("月度报告已生成"), "path", filePath)
 	return nil
 }
 
+// monthlyPositionRow 生成月度报告中的单行持仓数据
+func (rg *ReportGenerator) monthlyPositionRow(report *MonthlyReport, position *Position) []string {
+	return []string{
+		position.Symbol,
+		position.BuyDate.Format("2006-01-02"),
+		position.BuyPrice.StringFixed(2),
+		position.CurrentPrice.StringFixed(2),
+		position.Shares.String(),
+		position.MarketValue.StringFixed(2),
+		position.CostBasis.StringFixed(2),
+		position.PnL.StringFixed(2),
+		position.PnLPercent.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		position.Weight.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		rg.formatTradingActions(report.TradingActions, position.Symbol),
+	}
+}
+
 // GenerateFinalReport 生成最终持仓报告
 func (rg *ReportGenerator) GenerateFinalReport(reports []*MonthlyReport) error {
 	if len(reports) == 0 {
-		return fmt.Errorf("没有报告数据")
+		return errors.New(T("没有报告数据"))
 	}
 
 	// 创建输出目录
 	err := os.MkdirAll(rg.config.OutputDir, 0755)
 	if err != nil {
-		return fmt.Errorf("创建输出目录失败: %v", err)
+		return fmt.Errorf(T("创建输出目录失败: %v"), err)
 	}
 
 	// 生成最终持仓报告
 	err = rg.generateFinalPositionReport(reports)
 	if err != nil {
-		return fmt.Errorf("生成最终持仓报告失败: %v", err)
+		return fmt.Errorf(T("生成最终持仓报告失败: %v"), err)
 	}
 
 	// 生成业绩汇总报告
 	err = rg.generatePerformanceSummary(reports)
 	if err != nil {
-		return fmt.Errorf("生成业绩汇总报告失败: %v", err)
+		return fmt.Errorf(T("生成业绩汇总报告失败: %v"), err)
 	}
 
 	return nil
@@ -136,7 +198,7 @@ func (rg *ReportGenerator) generateFinalPositionReport(reports []*MonthlyReport)
 
 	file, err := os.Create(filePath)
 	if err != nil {
-		return fmt.Errorf("创建最终持仓报告文件失败: %v", err)
+		return fmt.Errorf(T("创建最终持仓报告文件失败: %v"), err)
 	}
 	defer file.Close()
 
@@ -151,7 +213,7 @@ func (rg *ReportGenerator) generateFinalPositionReport(reports []*MonthlyReport)
 	}
 	err = writer.Write(headers)
 	if err != nil {
-		return fmt.Errorf("写入标题失败: %v", err)
+		return fmt.Errorf(T("写入标题失败: %v"), err)
 	}
 
 	// 按市值排序持仓
@@ -170,7 +232,7 @@ func (rg *ReportGenerator) generateFinalPositionReport(reports []*MonthlyReport)
 			position.BuyDate.Format("2006-01-02"),
 			position.BuyPrice.StringFixed(2),
 			position.CurrentPrice.StringFixed(2),
-			strconv.Itoa(position.Shares),
+			position.Shares.String(),
 			position.MarketValue.StringFixed(2),
 			position.CostBasis.StringFixed(2),
 			position.PnL.StringFixed(2),
@@ -179,7 +241,7 @@ func (rg *ReportGenerator) generateFinalPositionReport(reports []*MonthlyReport)
 		}
 		err = writer.Write(row)
 		if err != nil {
-			return fmt.Errorf("写入持仓数据失败: %v", err)
+			return fmt.Errorf(T("写入持仓数据失败: %v"), err)
 		}
 	}
 
@@ -197,11 +259,11 @@ func (rg *ReportGenerator) generateFinalPositionReport(reports []*MonthlyReport)
 	for _, row := range summaryRows {
 		err = writer.Write(row)
 		if err != nil {
-			return fmt.Errorf("写入汇总信息失败: %v", err)
+			return fmt.Errorf(T("写入汇总信息失败: %v"), err)
 		}
 	}
 
-	fmt.Printf("最终持仓报告已生成: %s\n", filePath)
+	slog.Info(T("最终持仓报告已生成"), "path", filePath)
 	return nil
 }
 
@@ -211,7 +273,7 @@ func (rg *ReportGenerator) generatePerformanceSummary(reports []*MonthlyReport)
 
 	file, err := os.Create(filePath)
 	if err != nil {
-		return fmt.Errorf("创建业绩汇总报告文件失败: %v", err)
+		return fmt.Errorf(T("创建业绩汇总报告文件失败: %v"), err)
 	}
 	defer file.Close()
 
@@ -223,9 +285,14 @@ func (rg *ReportGenerator) generatePerformanceSummary(reports []*MonthlyReport)
 		"Date", "Total Value", "Cash", "Stock Value", 
 		"Monthly Return %", "Cumulative Return %", "Number of Positions",
 	}
+	// 融资、多空、市场状态和波动率目标的列仅在开启时追加在原有列之后
+	fields := rg.summaryFields()
+	for _, field := range fields {
+		headers = append(headers, field.csvName())
+	}
 	err = writer.Write(headers)
 	if err != nil {
-		return fmt.Errorf("写入标题失败: %v", err)
+		return fmt.Errorf(T("写入标题失败: %v"), err)
 	}
 
 	// 写入每月业绩数据
@@ -239,13 +306,361 @@ func (rg *ReportGenerator) generatePerformanceSummary(reports []*MonthlyReport)
 			report.CumulativeReturn.Mul(decimal.NewFromInt(100)).StringFixed(2),
 			strconv.Itoa(len(report.Positions)),
 		}
+		for _, field := range fields {
+			row = append(row, field.csvValue(report))
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return fmt.Errorf(T("写入业绩数据失败: %v"), err)
+		}
+	}
+
+	slog.Info(T("业绩汇总报告已生成"), "path", filePath)
+	return nil
+}
+
+// 月度汇总字段的数值类型
+const (
+	summaryCurrency = "currency" // 金额
+	summaryNumber   = "number"   // 普通数值
+	summaryPercent  = "percent"  // 比例，CSV 中以百分数输出
+	summaryText     = "text"     // 文本
+)
+
+// summaryField 随功能开启输出的月度汇总字段
+type summaryField struct {
+	name  string                                      // 列名，百分比字段在 CSV 中附加 " %"
+	kind  string                                      // 数值类型
+	width float64                                     // Excel 列宽
+	value func(report *MonthlyReport) decimal.Decimal // 数值字段的取值
+	text  func(report *MonthlyReport) string          // 文本字段的取值
+}
+
+// csvName 返回 CSV 列名
+func (field summaryField) csvName() string {
+	if field.kind == summaryPercent {
+		return field.name + " %"
+	}
+	return field.name
+}
+
+// csvValue 返回 CSV 中的取值，数值保留两位小数
+func (field summaryField) csvValue(report *MonthlyReport) string {
+	switch field.kind {
+	case summaryText:
+		return field.text(report)
+	case summaryPercent:
+		return field.value(report).Mul(decimal.NewFromInt(100)).StringFixed(2)
+	default:
+		return field.value(report).StringFixed(2)
+	}
+}
+
+// summaryFields 返回已开启功能对应的月度汇总字段：可能融资或做空时输出借款、利息和杠杆，
+// 多空模式输出多空市值和融券费用，启用市场状态过滤时输出市场状态和建仓比例，波动率目标模式输出建仓比例和预估波动率
+func (rg *ReportGenerator) summaryFields() []summaryField {
+	config := rg.config
+	var fields []summaryField
+	if config.LongShort || config.maxGrossExposure() > 1 {
+		fields = append(fields,
+			summaryField{name: "Borrowed", kind: summaryCurrency, width: 14, value: func(r *MonthlyReport) decimal.Decimal { return r.Borrowed }},
+			summaryField{name: "Interest Charged", kind: summaryCurrency, width: 16, value: func(r *MonthlyReport) decimal.Decimal { return r.InterestCharged }},
+			summaryField{name: "Leverage", kind: summaryNumber, width: 10, value: func(r *MonthlyReport) decimal.Decimal { return r.Leverage }},
+		)
+	}
+	if config.LongShort {
+		fields = append(fields,
+			summaryField{name: "Long Value", kind: summaryCurrency, width: 16, value: func(r *MonthlyReport) decimal.Decimal { return r.LongValue }},
+			summaryField{name: "Short Value", kind: summaryCurrency, width: 16, value: func(r *MonthlyReport) decimal.Decimal { return r.ShortValue }},
+			summaryField{name: "Borrow Fee", kind: summaryCurrency, width: 12, value: func(r *MonthlyReport) decimal.Decimal { return r.BorrowFee }},
+		)
+	}
+	if config.RegimeEnabled {
+		fields = append(fields,
+			summaryField{name: "Regime", kind: summaryText, width: 10, text: func(r *MonthlyReport) string { return r.Regime }})
+	}
+	volTarget := config.AllocationMode == AllocationModeVolTarget
+	if config.RegimeEnabled || volTarget {
+		fields = append(fields,
+			summaryField{name: "Allocation Ratio", kind: summaryPercent, width: 16, value: func(r *MonthlyReport) decimal.Decimal { return r.AllocationRatio }})
+	}
+	if volTarget {
+		fields = append(fields,
+			summaryField{name: "Ex-Ante Volatility", kind: summaryPercent, width: 18, value: func(r *MonthlyReport) decimal.Decimal { return r.ExAnteVolatility }})
+	}
+	return fields
+}
+
+// GenerateDrawdownReport 生成回撤分析报告，输出回撤幅度最大的前N个区间
+func (rg *ReportGenerator) GenerateDrawdownReport(reports []*MonthlyReport) error {
+	if len(reports) == 0 {
+		return errors.New(T("没有报告数据"))
+	}
+
+	filePath := filepath.Join(rg.config.OutputDir, "drawdowns.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建回撤报告文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// 写入标题
+	headers := []string{
+		"Rank", "Peak Date", "Trough Date", "Recovery Date",
+		"Peak Value", "Trough Value", "Depth %",
+		"Decline Days", "Recovery Days", "Total Days",
+	}
+	err = writer.Write(headers)
+	if err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	episodes := AnalyzeDrawdowns(reports)
+	lastDate := reports[len(reports)-1].Date
+
+	for i, episode := range episodes {
+		if rg.config.TopDrawdowns > 0 && i >= rg.config.TopDrawdowns {
+			break
+		}
+
+		recoveryDate := ""
+		recoveryDays := ""
+		endDate := lastDate
+		if episode.Recovered {
+			recoveryDate = episode.RecoveryDate.Format("2006-01-02")
+			recoveryDays = strconv.Itoa(durationDays(episode.TroughDate, episode.RecoveryDate))
+			endDate = episode.RecoveryDate
+		}
+
+		row := []string{
+			strconv.Itoa(i + 1),
+			episode.PeakDate.Format("2006-01-02"),
+			episode.TroughDate.Format("2006-01-02"),
+			recoveryDate,
+			episode.PeakValue.StringFixed(2),
+			episode.TroughValue.StringFixed(2),
+			episode.Depth.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			strconv.Itoa(durationDays(episode.PeakDate, episode.TroughDate)),
+			recoveryDays,
+			strconv.Itoa(durationDays(episode.PeakDate, endDate)),
+		}
 		err = writer.Write(row)
 		if err != nil {
-			return fmt.Errorf("写入业绩数据失败: %v", err)
+			return fmt.Errorf(T("写入回撤数据失败: %v"), err)
 		}
 	}
 
-	fmt.Printf("业绩汇总报告已生成: %s\n", filePath)
+	slog.Info(T("回撤分析报告已生成"), "path", filePath)
+	return nil
+}
+
+// GenerateMonthlyReturnsTable 生成年 × 月的月度收益率表
+func (rg *ReportGenerator) GenerateMonthlyReturnsTable(reports []*MonthlyReport) error {
+	filePath := filepath.Join(rg.config.OutputDir, "monthly_returns.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建月度收益率表文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// 写入标题
+	headers := []string{"Year"}
+	for month := time.January; month <= time.December; month++ {
+		headers = append(headers, month.String()[:3])
+	}
+	headers = append(headers, "YTD")
+	err = writer.Write(headers)
+	if err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	// 写入每年的月度收益率（%）
+	grid := BuildMonthlyReturnGrid(reports)
+	for _, year := range grid.Years {
+		row := []string{strconv.Itoa(year)}
+		for month := 1; month <= 12; month++ {
+			value := ""
+			if monthlyReturn, exists := grid.Returns[year][month]; exists {
+				value = monthlyReturn.Mul(decimal.NewFromInt(100)).StringFixed(2)
+			}
+			row = append(row, value)
+		}
+		row = append(row, grid.YTD[year].Mul(decimal.NewFromInt(100)).StringFixed(2))
+		err = writer.Write(row)
+		if err != nil {
+			return fmt.Errorf(T("写入月度收益率失败: %v"), err)
+		}
+	}
+
+	slog.Info(T("月度收益率表已生成"), "path", filePath)
+	return nil
+}
+
+// GenerateSignalValidationReport 生成信号校验报告 signal_validation.csv
+// 列出信号参考价格无法解析、与股价文件不一致或成交价超出容差的记录
+func (rg *ReportGenerator) GenerateSignalValidationReport(reports []*MonthlyReport) error {
+	filePath := filepath.Join(rg.config.OutputDir, "signal_validation.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建信号校验报告文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{
+		"Date", "Symbol", "Status", "Issue", "Signal Price",
+		"File Price", "Fill Price", "Deviation %", "Skipped",
+	}
+	err = writer.Write(headers)
+	if err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	for _, report := range reports {
+		for _, issue := range report.SignalIssues {
+			filePrice := ""
+			if issue.FilePrice.IsPositive() {
+				filePrice = issue.FilePrice.StringFixed(2)
+			}
+			fillPrice := ""
+			if issue.FillPrice.IsPositive() {
+				fillPrice = issue.FillPrice.StringFixed(2)
+			}
+			deviation := ""
+			if issue.Issue != SignalIssueInvalidPrice {
+				deviation = issue.Deviation.Mul(decimal.NewFromInt(100)).StringFixed(2)
+			}
+			row := []string{
+				issue.Date.Format("2006-01-02"),
+				issue.Symbol,
+				issue.Status,
+				issue.Issue,
+				issue.SignalPrice,
+				filePrice,
+				fillPrice,
+				deviation,
+				strconv.FormatBool(issue.Skipped),
+			}
+			err = writer.Write(row)
+			if err != nil {
+				return fmt.Errorf(T("写入信号校验数据失败: %v"), err)
+			}
+		}
+	}
+
+	slog.Info(T("信号校验报告已生成"), "path", filePath)
+	return nil
+}
+
+// GenerateAttributionReport 生成收益归因报告
+// attribution.csv 按累计盈亏排序列出每只股票（含已清仓股票）的贡献，
+// attribution_periods.csv 列出每个月度区间的股票贡献、现金拖累和费用
+func (rg *ReportGenerator) GenerateAttributionReport(reports []*MonthlyReport) error {
+	if len(reports) == 0 {
+		return errors.New(T("没有报告数据"))
+	}
+
+	initialCapital := decimal.NewFromFloat(rg.config.InitialCapital)
+	result := AnalyzeAttribution(reports, initialCapital)
+
+	// 股票累计贡献
+	filePath := filepath.Join(rg.config.OutputDir, "attribution.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建归因报告文件失败: %v"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	headers := []string{
+		"Rank", "Symbol", "Total P&L", "Contribution %",
+		"Periods Held", "Average Weight %", "Status",
+	}
+	err = writer.Write(headers)
+	if err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	for i, attribution := range result.Symbols {
+		status := "Closed"
+		if attribution.Open {
+			status = "Open"
+		}
+		row := []string{
+			strconv.Itoa(i + 1),
+			attribution.Symbol,
+			attribution.PnL.StringFixed(2),
+			attribution.Contribution.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			strconv.Itoa(attribution.PeriodsHeld),
+			attribution.AverageWeight.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			status,
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return fmt.Errorf(T("写入归因数据失败: %v"), err)
+		}
+	}
+
+	// 月度区间归因
+	periodPath := filepath.Join(rg.config.OutputDir, "attribution_periods.csv")
+	periodFile, err := os.Create(periodPath)
+	if err != nil {
+		return fmt.Errorf(T("创建区间归因报告文件失败: %v"), err)
+	}
+	defer periodFile.Close()
+
+	periodWriter := csv.NewWriter(periodFile)
+	defer periodWriter.Flush()
+
+	periodHeaders := []string{
+		"Date", "Portfolio Return %", "Stock Contribution %", "Costs %",
+		"Residual %", "Cash Weight %", "Cash Drag %", "Top Contributor", "Bottom Contributor",
+	}
+	err = periodWriter.Write(periodHeaders)
+	if err != nil {
+		return fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	for _, period := range result.Periods {
+		top, bottom := "", ""
+		var topValue, bottomValue decimal.Decimal
+		for symbol, contribution := range period.Contributions {
+			if top == "" || contribution.GreaterThan(topValue) || (contribution.Equal(topValue) && symbol < top) {
+				top, topValue = symbol, contribution
+			}
+			if bottom == "" || contribution.LessThan(bottomValue) || (contribution.Equal(bottomValue) && symbol < bottom) {
+				bottom, bottomValue = symbol, contribution
+			}
+		}
+
+		row := []string{
+			period.Date.Format("2006-01-02"),
+			period.PortfolioReturn.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			period.StockContribution.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			period.Costs.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			period.Residual.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			period.CashWeight.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			period.CashDrag.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			top,
+			bottom,
+		}
+		err = periodWriter.Write(row)
+		if err != nil {
+			return fmt.Errorf(T("写入区间归因数据失败: %v"), err)
+		}
+	}
+
+	slog.Info(T("归因报告已生成"), "path", filePath, "periods", periodPath)
 	return nil
 }
 
@@ -257,8 +672,8 @@ func (rg *ReportGenerator) formatTradingActions(actions []TradingAction, symbol
 			if result != "" {
 				result += "; "
 			}
-			result += fmt.Sprintf("%s %d shares at $%s (%s)", 
-				action.Action, action.Shares, action.Price.StringFixed(2), action.Reason)
+			result += fmt.Sprintf("%s %s shares at $%s (%s)", 
+				action.Action, action.Shares.String(), action.Price.StringFixed(2), TradeReasonText(action.Reason))
 		}
 	}
 	return result
@@ -267,24 +682,71 @@ func (rg *ReportGenerator) formatTradingActions(actions []TradingAction, symbol
 // PrintSummary 打印汇总信息到控制台
 func (rg *ReportGenerator) PrintSummary(reports []*MonthlyReport) {
 	if len(reports) == 0 {
-		fmt.Println("没有报告数据")
+		fmt.Println(T("没有报告数据"))
 		return
 	}
 
 	lastReport := reports[len(reports)-1]
 	initialCapital := decimal.NewFromFloat(rg.config.InitialCapital)
 
-	fmt.Println("\n=== 投资策略执行汇总 ===")
-	fmt.Printf("执行周期: %s 至 %s\n", 
+	fmt.Println(T("\n=== 投资策略执行汇总 ==="))
+	fmt.Printf(T("执行周期: %s 至 %s\n"), 
 		rg.config.StartDate.Format("2006-01-02"), 
 		rg.config.EndDate.Format("2006-01-02"))
-	fmt.Printf("初始资金: $%s\n", initialCapital.StringFixed(2))
-	fmt.Printf("最终价值: $%s\n", lastReport.TotalValue.StringFixed(2))
-	fmt.Printf("现金余额: $%s\n", lastReport.Cash.StringFixed(2))
-	fmt.Printf("股票市值: $%s\n", lastReport.StockValue.StringFixed(2))
-	fmt.Printf("总收益率: %s%%\n", lastReport.CumulativeReturn.Mul(decimal.NewFromInt(100)).StringFixed(2))
-	fmt.Printf("持仓数量: %d\n", len(lastReport.Positions))
-	fmt.Printf("总交易月数: %d\n", len(reports))
+	fmt.Printf(T("初始资金: $%s\n"), initialCapital.StringFixed(2))
+	fmt.Printf(T("最终价值: $%s\n"), lastReport.TotalValue.StringFixed(2))
+	fmt.Printf(T("现金余额: $%s\n"), lastReport.Cash.StringFixed(2))
+	fmt.Printf(T("股票市值: $%s\n"), lastReport.StockValue.StringFixed(2))
+	if rg.config.AllocationRatio > 1 {
+		totalInterest := decimal.Zero
+		for _, report := range reports {
+			totalInterest = totalInterest.Add(report.InterestCharged)
+		}
+		fmt.Printf(T("融资借款: $%s\n"), lastReport.Borrowed.StringFixed(2))
+		fmt.Printf(T("累计利息: $%s\n"), totalInterest.StringFixed(2))
+		fmt.Printf(T("杠杆率: %s\n"), lastReport.Leverage.StringFixed(2))
+	}
+	if rg.config.LongShort {
+		totalFee := decimal.Zero
+		for _, report := range reports {
+			totalFee = totalFee.Add(report.BorrowFee)
+		}
+		fmt.Printf(T("多头市值: $%s\n"), lastReport.LongValue.StringFixed(2))
+		fmt.Printf(T("空头市值: $%s\n"), lastReport.ShortValue.StringFixed(2))
+		fmt.Printf(T("累计融券费用: $%s\n"), totalFee.StringFixed(2))
+	}
+	fmt.Printf(T("总收益率: %s%%\n"), lastReport.CumulativeReturn.Mul(decimal.NewFromInt(100)).StringFixed(2))
+	fmt.Printf(T("持仓数量: %d\n"), len(lastReport.Positions))
+	if rg.config.RegimeEnabled {
+		riskOffMonths := 0
+		for _, report := range reports {
+			if report.Regime == RegimeRiskOff {
+				riskOffMonths++
+			}
+		}
+		fmt.Printf(T("risk-off 月数: %d\n"), riskOffMonths)
+	}
+	if rg.config.AllocationMode == AllocationModeVolTarget {
+		var monthlyReturns []float64
+		for _, report := range reports[1:] {
+			monthlyReturns = append(monthlyReturns, report.MonthlyReturn.InexactFloat64())
+		}
+		realized := annualizedStdDev(monthlyReturns, 12)
+		fmt.Printf(T("实现波动率: %.2f%% (目标 %.2f%%)\n"), realized*100, rg.config.TargetVolatility*100)
+	}
+	fmt.Printf(T("总交易月数: %d\n"), len(reports))
+	signalIssues, skippedBuys := 0, 0
+	for _, report := range reports {
+		for _, issue := range report.SignalIssues {
+			signalIssues++
+			if issue.Skipped {
+				skippedBuys++
+			}
+		}
+	}
+	if signalIssues > 0 {
+		fmt.Printf(T("信号校验问题: %d (跳过买入 %d)\n"), signalIssues, skippedBuys)
+	}
 
 	// 计算年化收益率
 	if len(reports) > 0 {
@@ -295,11 +757,20 @@ func (rg *ReportGenerator) PrintSummary(reports []*MonthlyReport) {
 			annualizedReturn := lastReport.CumulativeReturn.Add(decimal.NewFromInt(1))
 			annualizedReturn = decimal.NewFromFloat(math.Pow(annualizedReturn.InexactFloat64(), 1.0/years.InexactFloat64()))
 			annualizedReturn = annualizedReturn.Sub(decimal.NewFromInt(1))
-			fmt.Printf("年化收益率: %s%%\n", annualizedReturn.Mul(decimal.NewFromInt(100)).StringFixed(2))
+			fmt.Printf(T("年化收益率: %s%%\n"), annualizedReturn.Mul(decimal.NewFromInt(100)).StringFixed(2))
+		}
+	}
+
+	// 最大回撤
+	maxDrawdown := decimal.Zero
+	for _, drawdown := range drawdownSeries(reports) {
+		if drawdown.LessThan(maxDrawdown) {
+			maxDrawdown = drawdown
 		}
 	}
+	fmt.Printf(T("最大回撤: %s%%\n"), maxDrawdown.Mul(decimal.NewFromInt(100)).StringFixed(2))
 
-	fmt.Println("\n=== 前10大持仓 ===")
+	fmt.Println(T("\n=== 前10大持仓 ==="))
 	var positions []*Position
 	for _, position := range lastReport.Positions {
 		positions = append(positions, position)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RunResults 运行目录中保存的回测结果，由 run.json 还原
+type RunResults struct {
+	Config  *Config
+	Reports []*MonthlyReport
+}
+
+// SaveRunResults 将配置、绩效指标和月度报告写入输出目录下的 run.json
+func SaveRunResults(config *Config, reports []*MonthlyReport) error {
+	filePath := filepath.Join(config.OutputDir, RunDocumentFile)
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(newRunDocument(config, reports)); err != nil {
+		return fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	return nil
+}
+
+// LoadRunResults 读取运行目录（或 run.json 文件路径）中保存的回测结果，
+// 输出目录设置为结果所在目录，便于直接在原位置重新生成报告
+func LoadRunResults(path string) (*RunResults, error) {
+	filePath := path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		filePath = filepath.Join(path, RunDocumentFile)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf(T("%s 中没有回测结果 %s"), path, RunDocumentFile)
+		}
+		return nil, err
+	}
+
+	var document jsonRunDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf(T("解析回测结果 %s 失败: %v"), filePath, err)
+	}
+	if document.Schema != RunSchemaVersion {
+		return nil, fmt.Errorf(T("不支持的回测结果版本: %s"), document.Schema)
+	}
+	results, err := document.runResults()
+	if err != nil {
+		return nil, fmt.Errorf(T("解析回测结果 %s 失败: %v"), filePath, err)
+	}
+	if len(results.Reports) == 0 {
+		return nil, fmt.Errorf(T("回测结果 %s 中没有报告数据"), filePath)
+	}
+
+	dir := filepath.Dir(filePath)
+	results.Config.OutputDir = dir
+	results.Config.ChartsDir = filepath.Join(dir, "charts")
+	results.Config.ReportsDir = filepath.Join(dir, "reports")
+	return results, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxSharePrecision 碎股模式下股数最多保留的小数位数
+const maxSharePrecision = 8
+
+// runOptions run、validate、sweep 子命令共用的回测参数
+type runOptions struct {
+	initialCapital *float64
+	startDate      *string
+	endDate        *string
+	stockPriceDir  *string
+	priceFormat    *string
+	historyDir     *string
+	outputDir      *string
+	signalFormat   *string
+	signalFile     *string
+	dbPath         *string
+	runID          *string
+	fractional     *bool
+	sharePrecision *int
+	allocation     *float64
+	marginRate     *float64
+	maintenance    *float64
+	longShort      *bool
+	grossExposure  *float64
+	netExposure    *float64
+	borrowFee      *float64
+	indexDir       *string
+	regime         *bool
+	regimeSymbol   *string
+	regimeRule     *string
+	regimeWindow   *int
+	regimeDrawdown *float64
+	regimeScale    *float64
+	allocationMode *string
+	targetVol      *float64
+	volLookback    *int
+	minExposure    *float64
+	maxExposure    *float64
+	topDrawdowns   *int
+	metadataFile   *string
+	sectorCap      *float64
+	priceTolerance *float64
+	priceAction    *string
+	weighting      *string
+	benchmark      *string
+	assetsDir      *string
+	assetsCDN      *bool
+	outputFormat   *string
+	mdTemplate     *string
+}
+
+// addRunFlags 在命令参数中注册回测参数
+func addRunFlags(fs *flag.FlagSet) *runOptions {
+	return &runOptions{
+		initialCapital: fs.Float64("capital", 100000, "Initial capital in USD"),
+		startDate:      fs.String("start", "20230101", "Start date (YYYYMMDD)"),
+		endDate:        fs.String("end", "20250831", "End date (YYYYMMDD)"),
+		stockPriceDir:  fs.String("stock-dir", "stock_price", "Stock price data directory (file for long-csv)"),
+		priceFormat:    fs.String("price-format", "yahoo-csv", "Stock price format: yahoo-csv, iso-csv, json, long-csv or sqlite"),
+		historyDir:     fs.String("history-dir", "history", "Trading history directory"),
+		outputDir:      fs.String("output-dir", "output", "Output directory"),
+		signalFormat:   fs.String("signal-format", "csv-dir", "Signal source format: csv-dir, long-csv, json or sqlite"),
+		signalFile:     fs.String("signal-file", "", "Signal file for long-csv / json formats"),
+		dbPath:         fs.String("db", "", "SQLite database for sqlite formats; when set, run results are saved to it"),
+		runID:          fs.String("run-id", "", "Run ID for saved results (default: current timestamp)"),
+		fractional:     fs.Bool("fractional", false, "Allow fractional share quantities"),
+		sharePrecision: fs.Int("share-precision", 4, "Decimal places kept for fractional shares (0-8)"),
+		allocation:     fs.Float64("allocation", 0.9, "Target invested ratio of portfolio value (>1 uses margin)"),
+		marginRate:     fs.Float64("margin-rate", 0.07, "Annual margin interest rate"),
+		maintenance:    fs.Float64("maintenance-margin", 0.25, "Maintenance margin requirement (equity / gross exposure)"),
+		longShort:      fs.Bool("long-short", false, "Short excluded (剔除) names instead of only closing longs"),
+		grossExposure:  fs.Float64("gross-exposure", 1.3, "Gross exposure (long + short) in long/short mode"),
+		netExposure:    fs.Float64("net-exposure", 0.5, "Net exposure (long - short) in long/short mode"),
+		borrowFee:      fs.Float64("borrow-fee", 0.03, "Annual short borrow fee rate"),
+		indexDir:       fs.String("index-dir", "all_time_stock_price", "Index price data directory"),
+		regime:         fs.Bool("regime", false, "Enable market regime filter"),
+		regimeSymbol:   fs.String("regime-symbol", "SPY", "Index symbol used for the regime filter"),
+		regimeRule:     fs.String("regime-rule", "ma", "Regime rule: ma (price vs moving average) or drawdown"),
+		regimeWindow:   fs.Int("regime-window", 200, "Lookback window in trading days for the regime rule"),
+		regimeDrawdown: fs.Float64("regime-drawdown", 0.1, "Drawdown from peak that triggers risk-off (drawdown rule)"),
+		regimeScale:    fs.Float64("regime-scale", 0.5, "Allocation scale when risk-off (0 moves to cash)"),
+		allocationMode: fs.String("allocation-mode", "fixed", "Allocation mode: fixed or vol-target"),
+		targetVol:      fs.Float64("target-vol", 0.2, "Target annualized volatility (vol-target mode)"),
+		volLookback:    fs.Int("vol-lookback", 63, "Trailing trading days used to estimate volatility"),
+		minExposure:    fs.Float64("min-exposure", 0.3, "Minimum total exposure (vol-target mode)"),
+		maxExposure:    fs.Float64("max-exposure", 1.0, "Maximum total exposure (vol-target mode)"),
+		topDrawdowns:   fs.Int("top-drawdowns", 10, "Number of drawdown episodes written to drawdowns.csv (0 for all)"),
+		metadataFile:   fs.String("metadata", "metadata/symbols.csv", "Symbol metadata CSV (sector, industry, market cap); empty to disable"),
+		sectorCap:      fs.Float64("sector-cap", 0, "Maximum portfolio weight per sector (0 for no cap)"),
+		priceTolerance: fs.Float64("signal-price-tolerance", 0, "Tolerance for signal reference prices vs price files and fills, e.g. 0.05 (0 disables checks)"),
+		priceAction:    fs.String("signal-price-action", "flag", "Action when a buy fills above the signal price beyond tolerance: flag or skip"),
+		weighting:      fs.String("weighting", "equal", "Buy allocation weighting: equal or pl (signal pl column as score)"),
+		benchmark:      fs.String("benchmark", "SPY", "Benchmark symbol shown in report.html, loaded from -index-dir (empty to disable)"),
+		assetsDir:      fs.String("assets-dir", "", "Local go-echarts assets directory to inline into report.html (default: built-in assets)"),
+		assetsCDN:      fs.Bool("assets-cdn", false, "Load go-echarts scripts in report.html from the CDN instead of inlining them"),
+		outputFormat:   fs.String("output-format", "csv", "Comma-separated report formats: csv, json, jsonl, xlsx"),
+		mdTemplate:     fs.String("md-template", "", "Custom text/template file for summary.md (default: built-in template)"),
+	}
+}
+
+// config 根据参数创建并校验配置，未指定运行 ID 时使用当前时间
+func (options *runOptions) config() (*Config, error) {
+	startTime, err := time.Parse("20060102", *options.startDate)
+	if err != nil {
+		return nil, fmt.Errorf(T("Invalid start date format: %v"), err)
+	}
+	endTime, err := time.Parse("20060102", *options.endDate)
+	if err != nil {
+		return nil, fmt.Errorf(T("Invalid end date format: %v"), err)
+	}
+
+	config := &Config{
+		InitialCapital: *options.initialCapital,
+		StartDate:      startTime,
+		EndDate:        endTime,
+		StockPriceDir:  *options.stockPriceDir,
+		PriceFormat:    *options.priceFormat,
+		HistoryDir:     *options.historyDir,
+		SignalFormat:   *options.signalFormat,
+		SignalFile:     *options.signalFile,
+		StorePath:      *options.dbPath,
+		RunID:          *options.runID,
+		OutputDir:      *options.outputDir,
+		ChartsDir:      filepath.Join(*options.outputDir, "charts"),
+		ReportsDir:     filepath.Join(*options.outputDir, "reports"),
+
+		FractionalShares: *options.fractional,
+		SharePrecision:   int32(*options.sharePrecision),
+
+		AllocationRatio:    *options.allocation,
+		MarginInterestRate: *options.marginRate,
+		MaintenanceMargin:  *options.maintenance,
+
+		LongShort:      *options.longShort,
+		GrossExposure:  *options.grossExposure,
+		NetExposure:    *options.netExposure,
+		ShortBorrowFee: *options.borrowFee,
+
+		IndexPriceDir:       *options.indexDir,
+		RegimeEnabled:       *options.regime,
+		RegimeSymbol:        *options.regimeSymbol,
+		RegimeRule:          *options.regimeRule,
+		RegimeWindow:        *options.regimeWindow,
+		RegimeDrawdownLimit: *options.regimeDrawdown,
+		RegimeRiskOffScale:  *options.regimeScale,
+
+		AllocationMode:   *options.allocationMode,
+		TargetVolatility: *options.targetVol,
+		VolLookback:      *options.volLookback,
+		MinExposure:      *options.minExposure,
+		MaxExposure:      *options.maxExposure,
+
+		TopDrawdowns: *options.topDrawdowns,
+
+		MetadataFile: *options.metadataFile,
+		SectorCap:    *options.sectorCap,
+
+		SignalPriceTolerance: *options.priceTolerance,
+		SignalPriceAction:    *options.priceAction,
+		SignalWeighting:      *options.weighting,
+
+		Benchmark: *options.benchmark,
+		AssetsDir: *options.assetsDir,
+		AssetsCDN: *options.assetsCDN,
+
+		MarkdownTemplate: *options.mdTemplate,
+	}
+
+	if config.RunID == "" {
+		config.RunID = time.Now().Format("20060102-150405")
+	}
+
+	if *options.sharePrecision < 0 || *options.sharePrecision > maxSharePrecision {
+		return nil, fmt.Errorf(T("Invalid share precision: %d (must be 0-%d)"), *options.sharePrecision, maxSharePrecision)
+	}
+	if config.AllocationRatio < 0 {
+		return nil, fmt.Errorf(T("Invalid allocation: %.4g (must be at least 0)"), config.AllocationRatio)
+	}
+	if config.MarginInterestRate < 0 {
+		return nil, fmt.Errorf(T("Invalid margin rate: %.4g (must be at least 0)"), config.MarginInterestRate)
+	}
+	// 满仓时 净值 / 总敞口 = 1 / 目标敞口，维持保证金不低于该比例时建仓后立即追加保证金
+	if exposure := config.maxGrossExposure(); config.MaintenanceMargin < 0 || config.MaintenanceMargin*exposure >= 1 {
+		return nil, fmt.Errorf(T("Invalid maintenance margin: %.4g (must be below 1 / target exposure %.4g)"), config.MaintenanceMargin, exposure)
+	}
+
+	if config.LongShort && (config.GrossExposure <= 0 || config.GrossExposure < math.Abs(config.NetExposure)) {
+		return nil, fmt.Errorf(T("Invalid long/short exposure: gross %.4g must be positive and at least |net %.4g|"), config.GrossExposure, config.NetExposure)
+	}
+	if config.RegimeRule != RegimeRuleMovingAverage && config.RegimeRule != RegimeRuleDrawdown {
+		return nil, fmt.Errorf(T("Invalid regime rule: %s"), config.RegimeRule)
+	}
+	if config.RegimeWindow <= 0 {
+		return nil, fmt.Errorf(T("Invalid regime window: %d (must be positive)"), config.RegimeWindow)
+	}
+	if config.RegimeRule == RegimeRuleDrawdown && (config.RegimeDrawdownLimit <= 0 || config.RegimeDrawdownLimit >= 1) {
+		return nil, fmt.Errorf(T("Invalid regime drawdown: %.4g (must be between 0 and 1)"), config.RegimeDrawdownLimit)
+	}
+	if config.RegimeRiskOffScale < 0 || config.RegimeRiskOffScale > 1 {
+		return nil, fmt.Errorf(T("Invalid regime scale: %.4g (must be 0-1)"), config.RegimeRiskOffScale)
+	}
+	if config.AllocationMode != AllocationModeFixed && config.AllocationMode != AllocationModeVolTarget {
+		return nil, fmt.Errorf(T("Invalid allocation mode: %s"), config.AllocationMode)
+	}
+	// 多空模式的敞口由 -gross-exposure / -net-exposure 决定，不使用波动率目标
+	if config.LongShort && config.AllocationMode == AllocationModeVolTarget {
+		return nil, errors.New(T("-allocation-mode vol-target cannot be combined with -long-short"))
+	}
+	if config.AllocationMode == AllocationModeVolTarget {
+		if config.TargetVolatility <= 0 {
+			return nil, fmt.Errorf(T("Invalid target volatility: %.4g (must be positive)"), config.TargetVolatility)
+		}
+		if config.VolLookback < 2 {
+			return nil, fmt.Errorf(T("Invalid volatility lookback: %d (must be at least 2)"), config.VolLookback)
+		}
+		if config.MinExposure < 0 || config.MaxExposure <= 0 || config.MinExposure > config.MaxExposure {
+			return nil, fmt.Errorf(T("Invalid exposure range: %.4g - %.4g (need 0 <= min <= max, max > 0)"), config.MinExposure, config.MaxExposure)
+		}
+	}
+	if config.SignalPriceAction != SignalPriceActionFlag && config.SignalPriceAction != SignalPriceActionSkip {
+		return nil, fmt.Errorf(T("Invalid signal price action: %s"), config.SignalPriceAction)
+	}
+	if config.SignalWeighting != SignalWeightingEqual && config.SignalWeighting != SignalWeightingPL {
+		return nil, fmt.Errorf(T("Invalid weighting: %s"), config.SignalWeighting)
+	}
+	if config.OutputFormats, err = parseOutputFormats(*options.outputFormat); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseOutputFormats 解析逗号分隔的报告格式
+func parseOutputFormats(value string) ([]string, error) {
+	formats := strings.Split(value, ",")
+	for i, format := range formats {
+		formats[i] = strings.TrimSpace(format)
+		switch formats[i] {
+		case OutputFormatCSV, OutputFormatJSON, OutputFormatJSONL, OutputFormatXLSX:
+		default:
+			return nil, fmt.Errorf(T("Invalid output format: %s"), format)
+		}
+	}
+	return formats, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// unclassifiedSector 元数据中缺失的股票所属板块
+const unclassifiedSector = "Unclassified"
+
+// LoadSymbolMetadata 加载股票分类元数据文件（symbol,sector,industry,market_cap）
+func LoadSymbolMetadata(filePath string) (map[string]*SymbolMetadata, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("无法打开元数据文件 %s: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read() // 读取表头
+	if err != nil {
+		return nil, fmt.Errorf(T("读取CSV表头失败: %v"), err)
+	}
+
+	// 查找列索引
+	columnIndex := make(map[string]int)
+	for i, col := range header {
+		columnIndex[strings.TrimSpace(col)] = i
+	}
+	for _, col := range []string{"symbol", "sector", "industry", "market_cap"} {
+		if _, exists := columnIndex[col]; !exists {
+			return nil, fmt.Errorf(T("元数据文件缺少列: %s"), col)
+		}
+	}
+
+	metadata := make(map[string]*SymbolMetadata)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(T("读取CSV记录失败: %v"), err)
+		}
+
+		symbol := strings.TrimSpace(record[columnIndex["symbol"]])
+		if symbol == "" {
+			continue
+		}
+
+		metadata[symbol] = &SymbolMetadata{
+			Symbol:    symbol,
+			Sector:    strings.TrimSpace(record[columnIndex["sector"]]),
+			Industry:  strings.TrimSpace(record[columnIndex["industry"]]),
+			MarketCap: strings.TrimSpace(record[columnIndex["market_cap"]]),
+		}
+	}
+
+	return metadata, nil
+}
+
+// sectorOf 返回股票所属板块，未分类时返回 unclassifiedSector
+func sectorOf(metadata map[string]*SymbolMetadata, symbol string) string {
+	if meta, exists := metadata[symbol]; exists && meta.Sector != "" {
+		return meta.Sector
+	}
+	return unclassifiedSector
+}
+
+// sectorWeights 按板块汇总持仓权重（市值 / 总价值）
+func sectorWeights(metadata map[string]*SymbolMetadata, positions map[string]*Position, totalValue decimal.Decimal) map[string]decimal.Decimal {
+	weights := make(map[string]decimal.Decimal)
+	if metadata == nil || !totalValue.IsPositive() {
+		return weights
+	}
+	for symbol, position := range positions {
+		sector := sectorOf(metadata, symbol)
+		weights[sector] = weights[sector].Add(position.MarketValue.Div(totalValue))
+	}
+	return weights
+}
+
+// sortedSectors 返回报告中出现过的所有板块名称（升序）
+func sortedSectors(reports []*MonthlyReport) []string {
+	seen := make(map[string]bool)
+	var sectors []string
+	for _, report := range reports {
+		for sector := range report.SectorWeights {
+			if !seen[sector] {
+				seen[sector] = true
+				sectors = append(sectors, sector)
+			}
+		}
+	}
+	sort.Strings(sectors)
+	return sectors
+}
+
+// sectorRoom 计算板块上限下该股票所属板块仍可买入的金额，未设置上限时返回 ok=false
+func (strategy *TradingStrategy) sectorRoom(symbol string, portfolio *Portfolio) (decimal.Decimal, bool) {
+	if strategy.metadata == nil || strategy.config.SectorCap <= 0 {
+		return decimal.Zero, false
+	}
+
+	sector := sectorOf(strategy.metadata, symbol)
+	sectorValue := decimal.Zero
+	for held, position := range portfolio.Positions {
+		if sectorOf(strategy.metadata, held) == sector {
+			sectorValue = sectorValue.Add(position.MarketValue)
+		}
+	}
+
+	room := portfolio.Value.Mul(decimal.NewFromFloat(strategy.config.SectorCap)).Sub(sectorValue)
+	if room.IsNegative() {
+		room = decimal.Zero
+	}
+	return room, true
+}
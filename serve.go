@@ -0,0 +1,153 @@
+package main
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// servedRun 首页中列出的运行
+type servedRun struct {
+	Path        string // 相对根目录的路径（斜杠分隔），根目录本身为空
+	URL         string // 运行目录的 URL，以 / 结尾
+	RunID       string
+	Period      string
+	FinalValue  string
+	TotalReturn string
+	MaxDrawdown string
+	Sharpe      string
+	HasReport   bool // 是否有 report.html
+	HasSummary  bool // 是否有 summary.md
+}
+
+// runServer 提供运行目录中报告和图表的 HTTP 服务
+type runServer struct {
+	root  string
+	files http.Handler
+}
+
+// NewRunServer 返回 HTTP 处理器：根路径列出 root 及其子目录中保存的运行，其他路径直接提供 root 下的文件
+func NewRunServer(root string) http.Handler {
+	return &runServer{root: root, files: http.FileServer(http.Dir(root))}
+}
+
+// ServeHTTP 实现 http.Handler
+func (server *runServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		server.files.ServeHTTP(w, r)
+		return
+	}
+
+	runs, err := server.findRuns()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Lang string
+		Root string
+		Runs []servedRun
+	}{Language(), server.root, runs}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := serveIndexTemplate.Execute(w, data); err != nil {
+		slog.Error(T("生成运行列表失败"), "err", err)
+	}
+}
+
+// findRuns 查找根目录下所有包含 run.json 的运行目录，按路径排序
+func (server *runServer) findRuns() ([]servedRun, error) {
+	var runs []servedRun
+	hundred := decimal.NewFromInt(100)
+
+	err := filepath.WalkDir(server.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || entry.Name() != RunDocumentFile {
+			return nil
+		}
+		results, err := LoadRunResults(path)
+		if err != nil {
+			slog.Warn(T("跳过无法读取的运行"), "path", path, "err", err)
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		rel, _ := filepath.Rel(server.root, dir)
+		if rel == "." {
+			rel = ""
+		}
+		metrics := CalculatePerformanceMetrics(results.Reports)
+		run := servedRun{
+			Path:        filepath.ToSlash(rel),
+			URL:         "/",
+			RunID:       results.Config.RunID,
+			Period:      results.Config.StartDate.Format("2006-01-02") + " ~ " + results.Config.EndDate.Format("2006-01-02"),
+			FinalValue:  results.Reports[len(results.Reports)-1].TotalValue.StringFixed(2),
+			TotalReturn: metrics.TotalReturn.Mul(hundred).StringFixed(2),
+			MaxDrawdown: metrics.MaxDrawdown.Mul(hundred).StringFixed(2),
+			Sharpe:      metrics.SharpeRatio.StringFixed(2),
+		}
+		if run.Path != "" {
+			run.URL = "/" + run.Path + "/"
+		}
+		if _, err := os.Stat(filepath.Join(dir, "report.html")); err == nil {
+			run.HasReport = true
+		}
+		if _, err := os.Stat(filepath.Join(dir, "summary.md")); err == nil {
+			run.HasSummary = true
+		}
+		runs = append(runs, run)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Path < runs[j].Path })
+	return runs, nil
+}
+
+// serveIndexTemplate 运行列表页
+var serveIndexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{"T": T}).Parse(`<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+<meta charset="utf-8">
+<title>Tech Titans - {{ T "Runs" }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; }
+th, td { padding: 6px 12px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child, td.text { text-align: left; }
+th { background: #f5f5f5; }
+</style>
+</head>
+<body>
+<h1>{{ T "Runs" }}</h1>
+<p>{{ .Root }}</p>
+{{ if .Runs }}
+<table>
+<tr><th>{{ T "Directory" }}</th><th>{{ T "Run ID" }}</th><th>{{ T "Period" }}</th><th>{{ T "Final Value" }}</th><th>{{ T "Return %" }}</th><th>{{ T "Max DD %" }}</th><th>{{ T "Sharpe" }}</th><th>{{ T "Files" }}</th></tr>
+{{ range .Runs }}
+<tr>
+<td>{{ if .Path }}{{ .Path }}{{ else }}.{{ end }}</td>
+<td class="text">{{ .RunID }}</td>
+<td class="text">{{ .Period }}</td>
+<td>{{ .FinalValue }}</td>
+<td>{{ .TotalReturn }}</td>
+<td>{{ .MaxDrawdown }}</td>
+<td>{{ .Sharpe }}</td>
+<td class="text">{{ if .HasReport }}<a href="{{ .URL }}report.html">report.html</a> {{ end }}{{ if .HasSummary }}<a href="{{ .URL }}summary.md">summary.md</a> {{ end }}{{ if .Path }}<a href="{{ .URL }}">{{ T "Files" }}</a>{{ end }}</td>
+</tr>
+{{ end }}
+</table>
+{{ else }}
+<p>{{ T "No saved runs found" }}</p>
+{{ end }}
+</body>
+</html>
+`))
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// longShortRatios 根据总敞口和净敞口计算多头与空头的目标比例
+// 多头 = (总敞口 + 净敞口) / 2，空头 = (总敞口 - 净敞口) / 2
+func (strategy *TradingStrategy) longShortRatios() (decimal.Decimal, decimal.Decimal) {
+	gross := decimal.NewFromFloat(strategy.config.GrossExposure)
+	net := decimal.NewFromFloat(strategy.config.NetExposure)
+	two := decimal.NewFromInt(2)
+	return gross.Add(net).Div(two), gross.Sub(net).Div(two)
+}
+
+// isShort 判断持仓是否为空头
+func isShort(position *Position) bool {
+	return position.Shares.IsNegative()
+}
+
+// exposureValues 计算多头市值与空头市值（空头返回正数）
+func exposureValues(positions map[string]*Position) (decimal.Decimal, decimal.Decimal) {
+	longValue := decimal.Zero
+	shortValue := decimal.Zero
+	for _, position := range positions {
+		if isShort(position) {
+			shortValue = shortValue.Add(position.MarketValue.Neg())
+		} else {
+			longValue = longValue.Add(position.MarketValue)
+		}
+	}
+	return longValue, shortValue
+}
+
+// chargeBorrowFee 按月计提融券费用，按空头市值从现金中扣除
+func (strategy *TradingStrategy) chargeBorrowFee(portfolio *Portfolio) decimal.Decimal {
+	if strategy.config.ShortBorrowFee <= 0 {
+		return decimal.Zero
+	}
+
+	_, shortValue := exposureValues(portfolio.Positions)
+	if shortValue.IsZero() {
+		return decimal.Zero
+	}
+
+	monthlyRate := decimal.NewFromFloat(strategy.config.ShortBorrowFee).Div(decimal.NewFromInt(12))
+	fee := shortValue.Mul(monthlyRate)
+	portfolio.Cash = portfolio.Cash.Sub(fee)
+
+	slog.Info(T("计提融券费用"), "short_value", shortValue.StringFixed(2), "fee", fee.StringFixed(2))
+	return fee
+}
+
+// shortStocks 对剔除股票建立空头仓位，等分空头剩余额度
+func (strategy *TradingStrategy) shortStocks(stocksToShort []*TradeSignal, portfolio *Portfolio, date time.Time) ([]TradingAction, error) {
+	var actions []TradingAction
+
+	if len(stocksToShort) == 0 {
+		return actions, nil
+	}
+
+	_, shortRatio := strategy.longShortRatios()
+	_, currentShort := exposureValues(portfolio.Positions)
+	availableShort := portfolio.Value.Mul(shortRatio).Sub(currentShort)
+	if !availableShort.IsPositive() {
+		return actions, errors.New(T("空头敞口已达上限"))
+	}
+
+	amountPerStock := availableShort.Div(decimal.NewFromInt(int64(len(stocksToShort))))
+
+	slog.Debug(T("分配空头额度"), "month", date.Format("2006-01"), "available", availableShort, "per_stock", amountPerStock)
+
+	for _, signal := range stocksToShort {
+		action, err := strategy.shortStock(signal.Symbol, amountPerStock, portfolio, date)
+		if err != nil {
+			slog.Warn(T("做空股票失败"), "month", date.Format("2006-01"), "symbol", signal.Symbol, "err", err)
+			continue
+		}
+		actions = append(actions, *action)
+	}
+
+	return actions, nil
+}
+
+// shortStock 做空单只股票，卖空所得计入现金
+func (strategy *TradingStrategy) shortStock(symbol string, amount decimal.Decimal, portfolio *Portfolio, date time.Time) (*TradingAction, error) {
+	tradingDay, stockPrice, err := strategy.tradingDayPrice(symbol, date)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := strategy.calculateShares(amount, stockPrice.Close)
+	if !shares.IsPositive() {
+		return nil, fmt.Errorf(T("额度不足以做空 %s"), symbol)
+	}
+
+	proceeds := stockPrice.Close.Mul(shares)
+	portfolio.Cash = portfolio.Cash.Add(proceeds)
+
+	portfolio.Positions[symbol] = &Position{
+		Symbol:       symbol,
+		Shares:       shares.Neg(),
+		BuyPrice:     stockPrice.Close,
+		BuyDate:      tradingDay,
+		CurrentPrice: stockPrice.Close,
+		MarketValue:  proceeds.Neg(),
+		CostBasis:    proceeds.Neg(),
+		PnL:          decimal.Zero,
+		PnLPercent:   decimal.Zero,
+	}
+
+	action := &TradingAction{
+		Date:   tradingDay,
+		Symbol: symbol,
+		Action: "SHORT",
+		Shares: shares,
+		Price:  stockPrice.Close,
+		Amount: proceeds,
+		Reason: TradeReasonExcluded,
+	}
+
+	logTrade(action)
+
+	return action, nil
+}
+
+// coverStock 回补空头仓位
+func (strategy *TradingStrategy) coverStock(symbol string, position *Position, portfolio *Portfolio, date time.Time) (*TradingAction, error) {
+	tradingDay, stockPrice, err := strategy.tradingDayPrice(symbol, date)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := position.Shares.Neg()
+	coverAmount := stockPrice.Close.Mul(shares)
+
+	portfolio.Cash = portfolio.Cash.Sub(coverAmount)
+	delete(portfolio.Positions, symbol)
+
+	action := &TradingAction{
+		Date:   tradingDay,
+		Symbol: symbol,
+		Action: "COVER",
+		Shares: shares,
+		Price:  stockPrice.Close,
+		Amount: coverAmount,
+		Reason: TradeReasonIncluded,
+	}
+
+	logTrade(action)
+
+	return action, nil
+}
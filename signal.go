@@ -0,0 +1,203 @@
+package main
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 限价检查未通过时的处理方式
+const (
+	SignalPriceActionFlag = "flag" // 仅记录到信号校验报告
+	SignalPriceActionSkip = "skip" // 跳过该笔买入
+)
+
+// 买入资金的分配方式
+const (
+	SignalWeightingEqual = "equal" // 等分
+	SignalWeightingPL    = "pl"    // 按信号 pl 列的数值加权
+)
+
+// 信号校验问题类型
+const (
+	SignalIssueInvalidPrice  = "invalid price"  // price 列无法解析
+	SignalIssuePriceMismatch = "price mismatch" // price 列与信号日前最后收盘价不一致
+	SignalIssueLimitExceeded = "limit exceeded" // 成交价高于参考价超过容差
+)
+
+// ReferencePrice 解析信号中的参考价格，如 "284.26$"，为空或无法解析时返回 false
+func (signal *TradeSignal) ReferencePrice() (decimal.Decimal, bool) {
+	str := strings.TrimSpace(signal.Price)
+	str = strings.Trim(str, "$")
+	str = strings.ReplaceAll(str, ",", "")
+	if str == "" {
+		return decimal.Zero, false
+	}
+	price, err := decimal.NewFromString(str)
+	if err != nil || !price.IsPositive() {
+		return decimal.Zero, false
+	}
+	return price, true
+}
+
+// Score 解析信号中的 pl 列，如 "185.8%" 返回 1.858，为空或非数值（如 "实时"）时返回 false
+func (signal *TradeSignal) Score() (decimal.Decimal, bool) {
+	str := strings.TrimSpace(signal.PL)
+	percent := strings.HasSuffix(str, "%")
+	str = strings.TrimSuffix(str, "%")
+	str = strings.ReplaceAll(str, ",", "")
+	if str == "" {
+		return decimal.Zero, false
+	}
+	score, err := decimal.NewFromString(str)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	if percent {
+		score = score.Div(decimal.NewFromInt(100))
+	}
+	return score, true
+}
+
+// validateSignals 校验信号中的参考价格
+// 参考价格与信号日前最后一个收盘价偏差超过容差时记录 price mismatch；
+// 待买入股票的成交价高于参考价格超过容差时记录 limit exceeded，skip 模式下同时返回需跳过的股票
+func (strategy *TradingStrategy) validateSignals(signals []*TradeSignal, portfolio *Portfolio, date time.Time) ([]SignalIssue, map[string]bool) {
+	var issues []SignalIssue
+	skipped := make(map[string]bool)
+
+	tolerance := decimal.NewFromFloat(strategy.config.SignalPriceTolerance)
+	if !tolerance.IsPositive() {
+		return issues, skipped
+	}
+
+	for _, signal := range signals {
+		if strings.TrimSpace(signal.Price) == "" {
+			continue
+		}
+
+		issue := SignalIssue{
+			Date:        date,
+			Symbol:      signal.Symbol,
+			Status:      signal.Status,
+			SignalPrice: signal.Price,
+		}
+
+		reference, ok := signal.ReferencePrice()
+		if !ok {
+			issue.Issue = SignalIssueInvalidPrice
+			issues = append(issues, issue)
+			continue
+		}
+
+		stockPrices, err := strategy.dataLoader.LoadStockPrice(signal.Symbol)
+		if err != nil {
+			continue
+		}
+
+		// 参考价格应与信号日前最后一个收盘价一致
+		if closeKey, ok := lastDateBefore(stockPrices, date); ok {
+			filePrice := stockPrices[closeKey].Close
+			deviation := filePrice.Div(reference).Sub(decimal.NewFromInt(1))
+			if deviation.Abs().GreaterThan(tolerance) {
+				mismatch := issue
+				mismatch.Issue = SignalIssuePriceMismatch
+				mismatch.FilePrice = filePrice
+				mismatch.Deviation = deviation
+				issues = append(issues, mismatch)
+			}
+		}
+
+		// 仅对将要买入的股票检查成交价
+		if signal.Status != "纳入" {
+			continue
+		}
+		if position, exists := portfolio.Positions[signal.Symbol]; exists && !isShort(position) {
+			continue
+		}
+
+		_, fill, err := strategy.tradingDayPrice(signal.Symbol, date)
+		if err != nil {
+			continue
+		}
+		deviation := fill.Close.Div(reference).Sub(decimal.NewFromInt(1))
+		if deviation.GreaterThan(tolerance) {
+			issue.Issue = SignalIssueLimitExceeded
+			issue.FillPrice = fill.Close
+			issue.Deviation = deviation
+			issue.Skipped = strategy.config.SignalPriceAction == SignalPriceActionSkip
+			if issue.Skipped {
+				skipped[signal.Symbol] = true
+				slog.Warn(T("成交价高于信号参考价，跳过买入"), "month", date.Format("2006-01"),
+					"symbol", signal.Symbol, "fill", fill.Close, "reference", reference,
+					"deviation_pct", deviation.Mul(decimal.NewFromInt(100)).StringFixed(2))
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	if len(issues) > 0 {
+		slog.Warn(T("信号校验发现问题"), "month", date.Format("2006-01"), "issues", len(issues))
+	}
+
+	return issues, skipped
+}
+
+// lastDateBefore 返回指定日期之前最后一个交易日的日期键
+func lastDateBefore(stockPrices map[string]*StockPrice, date time.Time) (string, bool) {
+	cutoff := date.Format("20060102")
+	var dates []string
+	for dateKey := range stockPrices {
+		if dateKey < cutoff {
+			dates = append(dates, dateKey)
+		}
+	}
+	if len(dates) == 0 {
+		return "", false
+	}
+	sort.Strings(dates)
+	return dates[len(dates)-1], true
+}
+
+// signalAllocations 计算每只待买入股票分配的资金
+// 默认等分；pl 加权模式下按正的 pl 数值分配，缺失或非正数的股票使用其余股票的平均分数，全部缺失时等分
+func (strategy *TradingStrategy) signalAllocations(stocksToBuy []*TradeSignal, availableCash decimal.Decimal) []decimal.Decimal {
+	amounts := make([]decimal.Decimal, len(stocksToBuy))
+	cashPerStock := availableCash.Div(decimal.NewFromInt(int64(len(stocksToBuy))))
+	for i := range amounts {
+		amounts[i] = cashPerStock
+	}
+	if strategy.config.SignalWeighting != SignalWeightingPL {
+		return amounts
+	}
+
+	scores := make([]decimal.Decimal, len(stocksToBuy))
+	scored := 0
+	scoreSum := decimal.Zero
+	for i, signal := range stocksToBuy {
+		if score, ok := signal.Score(); ok && score.IsPositive() {
+			scores[i] = score
+			scored++
+			scoreSum = scoreSum.Add(score)
+		}
+	}
+	if scored == 0 {
+		return amounts
+	}
+
+	average := scoreSum.Div(decimal.NewFromInt(int64(scored)))
+	total := decimal.Zero
+	for i := range scores {
+		if !scores[i].IsPositive() {
+			scores[i] = average
+		}
+		total = total.Add(scores[i])
+	}
+	for i := range scores {
+		amounts[i] = availableCash.Mul(scores[i]).Div(total)
+	}
+	return amounts
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 交易信号数据格式
+const (
+	SignalFormatCSVDir  = "csv-dir"  // history/YYYY/YYYYMMDD.csv，每个信号日一个文件
+	SignalFormatLongCSV = "long-csv" // 单个 CSV 文件，带 date 列
+	SignalFormatJSON    = "json"     // 单个 JSON 数组或 JSONL 文件，每条记录带 date 字段
+	SignalFormatSQLite  = "sqlite"   // SQLite 数据库中的信号（由 import 命令导入）
+)
+
+// SignalSource 交易信号数据源
+type SignalSource interface {
+	// LoadTradeSignals 加载指定信号日的交易信号
+	LoadTradeSignals(date time.Time) ([]*TradeSignal, error)
+	// ListSignalDates 列出所有信号日（升序）
+	ListSignalDates() ([]time.Time, error)
+}
+
+// NewSignalSource 根据配置创建交易信号数据源
+func NewSignalSource(config *Config) (SignalSource, error) {
+	switch config.SignalFormat {
+	case "", SignalFormatCSVDir:
+		return NewCSVDirSignalSource(config.HistoryDir), nil
+	case SignalFormatLongCSV:
+		if config.SignalFile == "" {
+			return nil, fmt.Errorf(T("%s 格式需要指定信号文件"), config.SignalFormat)
+		}
+		return NewLongCSVSignalSource(config.SignalFile), nil
+	case SignalFormatJSON:
+		if config.SignalFile == "" {
+			return nil, fmt.Errorf(T("%s 格式需要指定信号文件"), config.SignalFormat)
+		}
+		return NewJSONSignalSource(config.SignalFile), nil
+	case SignalFormatSQLite:
+		return OpenStore(config.StorePath)
+	default:
+		return nil, fmt.Errorf(T("不支持的信号格式: %s"), config.SignalFormat)
+	}
+}
+
+// CSVDirSignalSource 按信号日分文件存放的 CSV 信号：historyDir/YYYY/YYYYMMDD.csv
+type CSVDirSignalSource struct {
+	historyDir string
+}
+
+// NewCSVDirSignalSource 创建按日期分文件的 CSV 信号数据源
+func NewCSVDirSignalSource(historyDir string) *CSVDirSignalSource {
+	return &CSVDirSignalSource{historyDir: historyDir}
+}
+
+// LoadTradeSignals 加载指定日期的交易信号文件
+func (source *CSVDirSignalSource) LoadTradeSignals(date time.Time) ([]*TradeSignal, error) {
+	year := date.Format("2006")
+	dateStr := date.Format("20060102")
+	filePath := filepath.Join(source.historyDir, year, dateStr+".csv")
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("无法打开交易信号文件 %s: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	signals := make([]*TradeSignal, 0)
+	err = readSignalCSV(file, func(record []string, columnIndex map[string]int) error {
+		signals = append(signals, signalFromRecord(record, columnIndex))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signals, nil
+}
+
+// ListSignalDates 列出交易信号目录中所有 YYYY/YYYYMMDD.csv 文件对应的日期（升序）
+func (source *CSVDirSignalSource) ListSignalDates() ([]time.Time, error) {
+	files, err := filepath.Glob(filepath.Join(source.historyDir, "*", "*.csv"))
+	if err != nil {
+		return nil, fmt.Errorf(T("查找交易信号文件失败: %v"), err)
+	}
+
+	var dates []time.Time
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".csv")
+		date, err := time.Parse("20060102", name)
+		if err != nil {
+			continue // 跳过非日期命名的文件
+		}
+		if filepath.Base(filepath.Dir(file)) != date.Format("2006") {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	sortDates(dates)
+	return dates, nil
+}
+
+// signalIndex 按信号日分组的交易信号，供单文件数据源使用
+type signalIndex map[string][]*TradeSignal
+
+// loadTradeSignals 返回指定日期的交易信号，没有该日期时返回错误
+func (index signalIndex) loadTradeSignals(date time.Time, filePath string) ([]*TradeSignal, error) {
+	signals, exists := index[date.Format("20060102")]
+	if !exists {
+		return nil, fmt.Errorf(T("信号文件 %s 中没有 %s 的交易信号"), filePath, date.Format("2006-01-02"))
+	}
+	return signals, nil
+}
+
+// listSignalDates 返回所有信号日（升序）
+func (index signalIndex) listSignalDates() []time.Time {
+	dates := make([]time.Time, 0, len(index))
+	for dateKey := range index {
+		date, _ := time.Parse("20060102", dateKey)
+		dates = append(dates, date)
+	}
+	sortDates(dates)
+	return dates
+}
+
+// LongCSVSignalSource 单个 CSV 文件中的长格式信号，列为 date,symbol,name,price,pl,status
+type LongCSVSignalSource struct {
+	filePath string
+	index    signalIndex
+}
+
+// NewLongCSVSignalSource 创建长格式 CSV 信号数据源
+func NewLongCSVSignalSource(filePath string) *LongCSVSignalSource {
+	return &LongCSVSignalSource{filePath: filePath}
+}
+
+// LoadTradeSignals 加载指定日期的交易信号
+func (source *LongCSVSignalSource) LoadTradeSignals(date time.Time) ([]*TradeSignal, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	return source.index.loadTradeSignals(date, source.filePath)
+}
+
+// ListSignalDates 列出文件中所有信号日（升序）
+func (source *LongCSVSignalSource) ListSignalDates() ([]time.Time, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	return source.index.listSignalDates(), nil
+}
+
+// load 首次使用时读取整个文件并按日期分组
+func (source *LongCSVSignalSource) load() error {
+	if source.index != nil {
+		return nil
+	}
+
+	file, err := os.Open(source.filePath)
+	if err != nil {
+		return fmt.Errorf(T("无法打开交易信号文件 %s: %v"), source.filePath, err)
+	}
+	defer file.Close()
+
+	index := make(signalIndex)
+	line := 1
+	err = readSignalCSV(file, func(record []string, columnIndex map[string]int) error {
+		line++
+		dateColumn, ok := columnIndex["date"]
+		if !ok {
+			return fmt.Errorf(T("信号文件 %s 缺少 date 列"), source.filePath)
+		}
+		date, err := parseDate(strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return fmt.Errorf(T("信号文件 %s 第 %d 行: %v"), source.filePath, line, err)
+		}
+		dateKey := date.Format("20060102")
+		index[dateKey] = append(index[dateKey], signalFromRecord(record, columnIndex))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	source.index = index
+	return nil
+}
+
+// JSONSignalSource JSON 数组或 JSONL 格式的信号文件
+type JSONSignalSource struct {
+	filePath string
+	index    signalIndex
+}
+
+// jsonSignal JSON 信号记录，price 和 pl 可以是字符串或数字
+type jsonSignal struct {
+	Date   string          `json:"date"`
+	Symbol string          `json:"symbol"`
+	Name   string          `json:"name"`
+	Price  json.RawMessage `json:"price"`
+	PL     json.RawMessage `json:"pl"`
+	Status string          `json:"status"`
+}
+
+// NewJSONSignalSource 创建 JSON/JSONL 信号数据源
+func NewJSONSignalSource(filePath string) *JSONSignalSource {
+	return &JSONSignalSource{filePath: filePath}
+}
+
+// LoadTradeSignals 加载指定日期的交易信号
+func (source *JSONSignalSource) LoadTradeSignals(date time.Time) ([]*TradeSignal, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	return source.index.loadTradeSignals(date, source.filePath)
+}
+
+// ListSignalDates 列出文件中所有信号日（升序）
+func (source *JSONSignalSource) ListSignalDates() ([]time.Time, error) {
+	if err := source.load(); err != nil {
+		return nil, err
+	}
+	return source.index.listSignalDates(), nil
+}
+
+// load 首次使用时读取整个文件并按日期分组，以 '[' 开头的文件按 JSON 数组解析，否则按 JSONL 逐行解析
+func (source *JSONSignalSource) load() error {
+	if source.index != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(source.filePath)
+	if err != nil {
+		return fmt.Errorf(T("无法打开交易信号文件 %s: %v"), source.filePath, err)
+	}
+
+	var records []jsonSignal
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return fmt.Errorf(T("解析信号文件 %s 失败: %v"), source.filePath, err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			var record jsonSignal
+			if err := json.Unmarshal(text, &record); err != nil {
+				return fmt.Errorf(T("信号文件 %s 第 %d 行: %v"), source.filePath, line, err)
+			}
+			records = append(records, record)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf(T("读取信号文件 %s 失败: %v"), source.filePath, err)
+		}
+	}
+
+	index := make(signalIndex)
+	for i, record := range records {
+		date, err := parseDate(strings.TrimSpace(record.Date))
+		if err != nil {
+			return fmt.Errorf(T("信号文件 %s 第 %d 条记录: %v"), source.filePath, i+1, err)
+		}
+		dateKey := date.Format("20060102")
+		index[dateKey] = append(index[dateKey], &TradeSignal{
+			Symbol: strings.TrimSpace(record.Symbol),
+			Name:   record.Name,
+			Price:  jsonText(record.Price),
+			PL:     jsonText(record.PL),
+			Status: strings.TrimSpace(record.Status),
+		})
+	}
+
+	source.index = index
+	return nil
+}
+
+// jsonText 将字符串或数字形式的 JSON 值转换为文本，null 或缺失时返回空字符串
+func jsonText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
+
+// readSignalCSV 读取带表头的信号 CSV，对每条记录调用 handle
+func readSignalCSV(r io.Reader, handle func(record []string, columnIndex map[string]int) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read() // 读取表头
+	if err != nil {
+		return fmt.Errorf(T("读取CSV表头失败: %v"), err)
+	}
+
+	// 查找列索引
+	columnIndex := make(map[string]int)
+	for i, col := range header {
+		columnIndex[strings.TrimSpace(col)] = i
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf(T("读取CSV记录失败: %v"), err)
+		}
+		if err := handle(record, columnIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// signalFromRecord 根据列索引构造交易信号，缺失的列视为空
+func signalFromRecord(record []string, columnIndex map[string]int) *TradeSignal {
+	field := func(name string) string {
+		i, ok := columnIndex[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	return &TradeSignal{
+		Symbol: field("symbol"),
+		Name:   field("name"),
+		Price:  field("price"),
+		PL:     field("pl"),
+		Status: field("status"),
+	}
+}
+
+// sortDates 按时间升序排列日期
+func sortDates(dates []time.Time) {
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+}
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	_ "modernc.org/sqlite"
+)
+
+// 数据库中股价的来源分类
+const (
+	StorePriceSourceStock = "stock" // 个股（stock_price/）
+	StorePriceSourceIndex = "index" // 指数（all_time_stock_price/）
+)
+
+// storeSchema 数据库表结构，金额和价格以十进制字符串保存以保留精度
+const storeSchema = `
+CREATE TABLE IF NOT EXISTS prices (
+	source    TEXT NOT NULL,
+	symbol    TEXT NOT NULL,
+	date      TEXT NOT NULL,
+	open      TEXT NOT NULL,
+	high      TEXT NOT NULL,
+	low       TEXT NOT NULL,
+	close     TEXT NOT NULL,
+	adj_close TEXT NOT NULL,
+	volume    INTEGER NOT NULL,
+	PRIMARY KEY (source, symbol, date)
+);
+CREATE TABLE IF NOT EXISTS signals (
+	date   TEXT NOT NULL,
+	seq    INTEGER NOT NULL,
+	symbol TEXT NOT NULL,
+	name   TEXT NOT NULL,
+	price  TEXT NOT NULL,
+	pl     TEXT NOT NULL,
+	status TEXT NOT NULL,
+	PRIMARY KEY (date, seq)
+);
+CREATE TABLE IF NOT EXISTS runs (
+	run_id            TEXT PRIMARY KEY,
+	created_at        TEXT NOT NULL,
+	config            TEXT NOT NULL,
+	start_date        TEXT NOT NULL,
+	end_date          TEXT NOT NULL,
+	initial_capital   TEXT NOT NULL,
+	final_value       TEXT NOT NULL,
+	total_return      TEXT NOT NULL,
+	annualized_return TEXT NOT NULL,
+	max_drawdown      TEXT NOT NULL,
+	sharpe_ratio      TEXT NOT NULL,
+	volatility        TEXT NOT NULL,
+	win_rate          TEXT NOT NULL,
+	average_return    TEXT NOT NULL,
+	total_trades      INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS run_reports (
+	run_id             TEXT NOT NULL,
+	date               TEXT NOT NULL,
+	total_value        TEXT NOT NULL,
+	cash               TEXT NOT NULL,
+	stock_value        TEXT NOT NULL,
+	borrowed           TEXT NOT NULL,
+	interest_charged   TEXT NOT NULL,
+	leverage           TEXT NOT NULL,
+	long_value         TEXT NOT NULL,
+	short_value        TEXT NOT NULL,
+	borrow_fee         TEXT NOT NULL,
+	regime             TEXT NOT NULL,
+	allocation_ratio   TEXT NOT NULL,
+	ex_ante_volatility TEXT NOT NULL,
+	monthly_return     TEXT NOT NULL,
+	cumulative_return  TEXT NOT NULL,
+	positions          INTEGER NOT NULL,
+	sector_weights     TEXT NOT NULL,
+	PRIMARY KEY (run_id, date)
+);
+CREATE TABLE IF NOT EXISTS run_positions (
+	run_id        TEXT NOT NULL,
+	date          TEXT NOT NULL,
+	symbol        TEXT NOT NULL,
+	shares        TEXT NOT NULL,
+	buy_price     TEXT NOT NULL,
+	buy_date      TEXT NOT NULL,
+	current_price TEXT NOT NULL,
+	market_value  TEXT NOT NULL,
+	cost_basis    TEXT NOT NULL,
+	pnl           TEXT NOT NULL,
+	PRIMARY KEY (run_id, date, symbol)
+);
+CREATE TABLE IF NOT EXISTS run_trades (
+	run_id TEXT NOT NULL,
+	seq    INTEGER NOT NULL,
+	date   TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	action TEXT NOT NULL,
+	shares TEXT NOT NULL,
+	price  TEXT NOT NULL,
+	amount TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	PRIMARY KEY (run_id, seq)
+);
+`
+
+// storeMigrations 旧版数据库缺少的列，打开数据库时补齐；旧运行的这些字段按默认值（零、无板块权重）读取
+var storeMigrations = []struct {
+	table, column, definition string
+}{
+	{"run_reports", "interest_charged", "TEXT NOT NULL DEFAULT '0'"},
+	{"run_reports", "borrow_fee", "TEXT NOT NULL DEFAULT '0'"},
+	{"run_reports", "ex_ante_volatility", "TEXT NOT NULL DEFAULT '0'"},
+	{"run_reports", "sector_weights", "TEXT NOT NULL DEFAULT '{}'"},
+}
+
+// Store 基于 SQLite 的股价、信号和回测结果存储
+type Store struct {
+	db *sql.DB
+}
+
+// RunSummary 已保存的回测运行摘要
+type RunSummary struct {
+	RunID       string    // 运行 ID
+	CreatedAt   time.Time // 保存时间
+	StartDate   string    // 回测开始日期
+	EndDate     string    // 回测结束日期
+	FinalValue  decimal.Decimal
+	TotalReturn decimal.Decimal
+	MaxDrawdown decimal.Decimal
+	SharpeRatio decimal.Decimal
+	TotalTrades int
+}
+
+// newIndexPriceSource 创建指数价格数据源：sqlite 格式从数据库的 index 分类读取，否则读取指数目录
+func newIndexPriceSource(config *Config) (PriceSource, error) {
+	if config.PriceFormat == PriceFormatSQLite {
+		store, err := OpenStore(config.StorePath)
+		if err != nil {
+			return nil, err
+		}
+		return NewStorePriceSource(store, StorePriceSourceIndex), nil
+	}
+	return NewYahooCSVPriceSource(config.IndexPriceDir), nil
+}
+
+// OpenStore 打开（必要时创建）SQLite 数据库并初始化表结构
+func OpenStore(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New(T("未指定数据库路径"))
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf(T("打开数据库 %s 失败: %v"), path, err)
+	}
+	// SQLite 单写入者，使用单连接避免锁冲突
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(storeSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf(T("初始化数据库 %s 失败: %v"), path, err)
+	}
+	if err := migrateStore(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf(T("初始化数据库 %s 失败: %v"), path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// migrateStore 为旧版数据库补齐 storeMigrations 中缺少的列
+func migrateStore(db *sql.DB) error {
+	columns := make(map[string]map[string]bool)
+	for _, migration := range storeMigrations {
+		if columns[migration.table] == nil {
+			existing, err := tableColumns(db, migration.table)
+			if err != nil {
+				return err
+			}
+			columns[migration.table] = existing
+		}
+		if columns[migration.table][migration.column] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + migration.table + ` ADD COLUMN ` +
+			migration.column + ` ` + migration.definition); err != nil {
+			return err
+		}
+		columns[migration.table][migration.column] = true
+	}
+	return nil
+}
+
+// tableColumns 返回表中已有的列名
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// Close 关闭数据库
+func (store *Store) Close() error {
+	return store.db.Close()
+}
+
+// ImportPrices 将股价数据源中的所有股票导入指定来源分类，返回股票数和记录数
+func (store *Store) ImportPrices(source string, prices PriceSource) (int, int, error) {
+	symbols, err := prices.ListSymbols()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO prices
+		(source, symbol, date, open, high, low, close, adj_close, volume)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer stmt.Close()
+
+	imported, rows := 0, 0
+	for _, symbol := range symbols {
+		stockPrices, err := prices.LoadStockPrice(symbol)
+		if err != nil {
+			slog.Warn(T("跳过股票"), "symbol", symbol, "err", err)
+			continue
+		}
+		for _, key := range sortedPriceKeys(stockPrices) {
+			price := stockPrices[key]
+			_, err := stmt.Exec(source, symbol, price.Date.Format("2006-01-02"),
+				price.Open.String(), price.High.String(), price.Low.String(),
+				price.Close.String(), price.AdjClose.String(), price.Volume)
+			if err != nil {
+				return imported, rows, fmt.Errorf(T("写入 %s 股价失败: %v"), symbol, err)
+			}
+			rows++
+		}
+		imported++
+	}
+
+	return imported, rows, tx.Commit()
+}
+
+// ImportSignals 导入信号数据源中的所有交易信号，同一信号日的已有信号会被替换，返回信号日数和记录数
+func (store *Store) ImportSignals(signals SignalSource) (int, int, error) {
+	dates, err := signals.ListSignalDates()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	imported, rows := 0, 0
+	for _, date := range dates {
+		tradeSignals, err := signals.LoadTradeSignals(date)
+		if err != nil {
+			slog.Warn(T("跳过信号日"), "date", date.Format("2006-01-02"), "err", err)
+			continue
+		}
+
+		dateKey := date.Format("2006-01-02")
+		if _, err := tx.Exec(`DELETE FROM signals WHERE date = ?`, dateKey); err != nil {
+			return imported, rows, err
+		}
+		for i, signal := range tradeSignals {
+			_, err := tx.Exec(`INSERT INTO signals (date, seq, symbol, name, price, pl, status)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				dateKey, i, signal.Symbol, signal.Name, signal.Price, signal.PL, signal.Status)
+			if err != nil {
+				return imported, rows, fmt.Errorf(T("写入 %s 交易信号失败: %v"), dateKey, err)
+			}
+			rows++
+		}
+		imported++
+	}
+
+	return imported, rows, tx.Commit()
+}
+
+// loadPrices 加载指定来源分类和股票的价格数据
+func (store *Store) loadPrices(source, symbol string) (map[string]*StockPrice, error) {
+	rows, err := store.db.Query(`SELECT date, open, high, low, close, adj_close, volume
+		FROM prices WHERE source = ? AND symbol = ?`, source, symbol)
+	if err != nil {
+		return nil, fmt.Errorf(T("查询 %s 股价失败: %v"), symbol, err)
+	}
+	defer rows.Close()
+
+	prices := make(map[string]*StockPrice)
+	for rows.Next() {
+		var dateStr, open, high, low, close, adjClose string
+		var volume int64
+		if err := rows.Scan(&dateStr, &open, &high, &low, &close, &adjClose, &volume); err != nil {
+			return nil, err
+		}
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, err
+		}
+		price := &StockPrice{Date: date, Volume: volume}
+		price.Open, _ = decimal.NewFromString(open)
+		price.High, _ = decimal.NewFromString(high)
+		price.Low, _ = decimal.NewFromString(low)
+		price.Close, _ = decimal.NewFromString(close)
+		price.AdjClose, _ = decimal.NewFromString(adjClose)
+		prices[date.Format("20060102")] = price
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf(T("数据库中没有 %s 的股价数据"), symbol)
+	}
+
+	return prices, nil
+}
+
+// listSymbols 列出指定来源分类中的所有股票代码
+func (store *Store) listSymbols(source string) ([]string, error) {
+	rows, err := store.db.Query(`SELECT DISTINCT symbol FROM prices WHERE source = ? ORDER BY symbol`, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, rows.Err()
+}
+
+// LoadTradeSignals 加载指定信号日的交易信号
+func (store *Store) LoadTradeSignals(date time.Time) ([]*TradeSignal, error) {
+	dateKey := date.Format("2006-01-02")
+	rows, err := store.db.Query(`SELECT symbol, name, price, pl, status
+		FROM signals WHERE date = ? ORDER BY seq`, dateKey)
+	if err != nil {
+		return nil, fmt.Errorf(T("查询 %s 交易信号失败: %v"), dateKey, err)
+	}
+	defer rows.Close()
+
+	var signals []*TradeSignal
+	for rows.Next() {
+		signal := &TradeSignal{}
+		if err := rows.Scan(&signal.Symbol, &signal.Name, &signal.Price, &signal.PL, &signal.Status); err != nil {
+			return nil, err
+		}
+		signals = append(signals, signal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if signals == nil {
+		return nil, fmt.Errorf(T("数据库中没有 %s 的交易信号"), dateKey)
+	}
+
+	return signals, nil
+}
+
+// ListSignalDates 列出数据库中所有信号日（升序）
+func (store *Store) ListSignalDates() ([]time.Time, error) {
+	rows, err := store.db.Query(`SELECT DISTINCT date FROM signals ORDER BY date`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var dateStr string
+		if err := rows.Scan(&dateStr); err != nil {
+			return nil, err
+		}
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+// SaveRun 保存一次回测的配置、绩效指标、月度报告、持仓和交易，相同运行 ID 的旧结果会被替换
+func (store *Store) SaveRun(runID string, config *Config, reports []*MonthlyReport) error {
+	if len(reports) == 0 {
+		return errors.New(T("没有报告数据"))
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf(T("序列化配置失败: %v"), err)
+	}
+	metrics := CalculatePerformanceMetrics(reports)
+	lastReport := reports[len(reports)-1]
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"runs", "run_reports", "run_positions", "run_trades"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE run_id = ?`, runID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO runs (run_id, created_at, config, start_date, end_date, initial_capital,
+		final_value, total_return, annualized_return, max_drawdown, sharpe_ratio, volatility,
+		win_rate, average_return, total_trades)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		runID, time.Now().Format(time.RFC3339), string(configJSON),
+		config.StartDate.Format("2006-01-02"), config.EndDate.Format("2006-01-02"),
+		decimal.NewFromFloat(config.InitialCapital).String(),
+		lastReport.TotalValue.String(), metrics.TotalReturn.String(), metrics.AnnualizedReturn.String(),
+		metrics.MaxDrawdown.String(), metrics.SharpeRatio.String(), metrics.Volatility.String(),
+		metrics.WinRate.String(), metrics.AverageReturn.String(), metrics.TotalTrades)
+	if err != nil {
+		return fmt.Errorf(T("保存运行 %s 失败: %v"), runID, err)
+	}
+
+	seq := 0
+	for _, report := range reports {
+		dateKey := report.Date.Format("2006-01-02")
+		sectorWeights := report.SectorWeights
+		if sectorWeights == nil {
+			sectorWeights = map[string]decimal.Decimal{}
+		}
+		sectorJSON, err := json.Marshal(sectorWeights)
+		if err != nil {
+			return fmt.Errorf(T("保存 %s 月度报告失败: %v"), dateKey, err)
+		}
+		_, err = tx.Exec(`INSERT INTO run_reports (run_id, date, total_value, cash, stock_value, borrowed,
+			interest_charged, leverage, long_value, short_value, borrow_fee, regime, allocation_ratio,
+			ex_ante_volatility, monthly_return, cumulative_return, positions, sector_weights)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			runID, dateKey, report.TotalValue.String(), report.Cash.String(), report.StockValue.String(),
+			report.Borrowed.String(), report.InterestCharged.String(), report.Leverage.String(),
+			report.LongValue.String(), report.ShortValue.String(), report.BorrowFee.String(),
+			report.Regime, report.AllocationRatio.String(), report.ExAnteVolatility.String(),
+			report.MonthlyReturn.String(), report.CumulativeReturn.String(), len(report.Positions),
+			string(sectorJSON))
+		if err != nil {
+			return fmt.Errorf(T("保存 %s 月度报告失败: %v"), dateKey, err)
+		}
+
+		symbols := make([]string, 0, len(report.Positions))
+		for symbol := range report.Positions {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		for _, symbol := range symbols {
+			position := report.Positions[symbol]
+			_, err := tx.Exec(`INSERT INTO run_positions (run_id, date, symbol, shares, buy_price, buy_date,
+				current_price, market_value, cost_basis, pnl)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, dateKey, symbol, position.Shares.String(), position.BuyPrice.String(),
+				position.BuyDate.Format("2006-01-02"), position.CurrentPrice.String(),
+				position.MarketValue.String(), position.CostBasis.String(), position.PnL.String())
+			if err != nil {
+				return fmt.Errorf(T("保存 %s 持仓失败: %v"), dateKey, err)
+			}
+		}
+
+		for _, action := range report.TradingActions {
+			_, err := tx.Exec(`INSERT INTO run_trades (run_id, seq, date, symbol, action, shares, price, amount, reason)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, seq, action.Date.Format("2006-01-02"), action.Symbol, action.Action,
+				action.Shares.String(), action.Price.String(), action.Amount.String(), action.Reason)
+			if err != nil {
+				return fmt.Errorf(T("保存 %s 交易失败: %v"), dateKey, err)
+			}
+			seq++
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListRuns 列出已保存的回测运行（按保存时间升序）
+func (store *Store) ListRuns() ([]RunSummary, error) {
+	rows, err := store.db.Query(`SELECT run_id, created_at, start_date, end_date, final_value,
+		total_return, max_drawdown, sharpe_ratio, total_trades FROM runs ORDER BY created_at, run_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []RunSummary
+	for rows.Next() {
+		var run RunSummary
+		var createdAt, finalValue, totalReturn, maxDrawdown, sharpeRatio string
+		if err := rows.Scan(&run.RunID, &createdAt, &run.StartDate, &run.EndDate, &finalValue,
+			&totalReturn, &maxDrawdown, &sharpeRatio, &run.TotalTrades); err != nil {
+			return nil, err
+		}
+		run.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		run.FinalValue, _ = decimal.NewFromString(finalValue)
+		run.TotalReturn, _ = decimal.NewFromString(totalReturn)
+		run.MaxDrawdown, _ = decimal.NewFromString(maxDrawdown)
+		run.SharpeRatio, _ = decimal.NewFromString(sharpeRatio)
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// SavedRun 从数据库还原的回测运行
+type SavedRun struct {
+	RunID     string           // 运行 ID
+	CreatedAt time.Time        // 保存时间
+	Config    *Config          // 运行时的配置
+	Reports   []*MonthlyReport // 月度报告（含持仓和交易，不含信号校验明细）
+}
+
+// LoadRun 读取已保存的回测运行，还原配置和月度报告
+func (store *Store) LoadRun(runID string) (*SavedRun, error) {
+	var createdAt, configJSON string
+	err := store.db.QueryRow(`SELECT created_at, config FROM runs WHERE run_id = ?`, runID).Scan(&createdAt, &configJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf(T("数据库中没有运行 %s"), runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	run := &SavedRun{RunID: runID, Config: &Config{}}
+	run.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	if err := json.Unmarshal([]byte(configJSON), run.Config); err != nil {
+		return nil, fmt.Errorf(T("解析运行 %s 的配置失败: %v"), runID, err)
+	}
+
+	rows, err := store.db.Query(`SELECT date, total_value, cash, stock_value, borrowed, interest_charged,
+		leverage, long_value, short_value, borrow_fee, regime, allocation_ratio, ex_ante_volatility,
+		monthly_return, cumulative_return, sector_weights
+		FROM run_reports WHERE run_id = ? ORDER BY date`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reportsByDate := make(map[string]*MonthlyReport)
+	reportsByMonth := make(map[string]*MonthlyReport)
+	for rows.Next() {
+		var dateKey, sectorJSON string
+		var fields [13]string
+		report := &MonthlyReport{Positions: make(map[string]*Position)}
+		if err := rows.Scan(&dateKey, &fields[0], &fields[1], &fields[2], &fields[3], &fields[4],
+			&fields[5], &fields[6], &fields[7], &fields[8], &report.Regime, &fields[9], &fields[10],
+			&fields[11], &fields[12], &sectorJSON); err != nil {
+			return nil, err
+		}
+		report.Date, err = time.Parse("2006-01-02", dateKey)
+		if err != nil {
+			return nil, fmt.Errorf(T("运行 %s 的报告日期无效: %s"), runID, dateKey)
+		}
+		for i, target := range []*decimal.Decimal{&report.TotalValue, &report.Cash, &report.StockValue,
+			&report.Borrowed, &report.InterestCharged, &report.Leverage, &report.LongValue,
+			&report.ShortValue, &report.BorrowFee, &report.AllocationRatio, &report.ExAnteVolatility,
+			&report.MonthlyReturn, &report.CumulativeReturn} {
+			*target, _ = decimal.NewFromString(fields[i])
+		}
+		if err := json.Unmarshal([]byte(sectorJSON), &report.SectorWeights); err != nil {
+			return nil, fmt.Errorf(T("运行 %s 的板块权重无效: %s"), runID, dateKey)
+		}
+		run.Reports = append(run.Reports, report)
+		reportsByDate[dateKey] = report
+		reportsByMonth[report.Date.Format("2006-01")] = report
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	positionRows, err := store.db.Query(`SELECT date, symbol, shares, buy_price, buy_date, current_price,
+		market_value, cost_basis, pnl FROM run_positions WHERE run_id = ?`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer positionRows.Close()
+
+	for positionRows.Next() {
+		var dateKey, buyDate string
+		var fields [6]string
+		position := &Position{}
+		if err := positionRows.Scan(&dateKey, &position.Symbol, &fields[0], &fields[1], &buyDate,
+			&fields[2], &fields[3], &fields[4], &fields[5]); err != nil {
+			return nil, err
+		}
+		report, exists := reportsByDate[dateKey]
+		if !exists {
+			continue
+		}
+		for i, target := range []*decimal.Decimal{&position.Shares, &position.BuyPrice, &position.CurrentPrice,
+			&position.MarketValue, &position.CostBasis, &position.PnL} {
+			*target, _ = decimal.NewFromString(fields[i])
+		}
+		position.BuyDate, _ = time.Parse("2006-01-02", buyDate)
+		if !position.CostBasis.IsZero() {
+			position.PnLPercent = position.PnL.Div(position.CostBasis.Abs())
+		}
+		if report.TotalValue.IsPositive() {
+			position.Weight = position.MarketValue.Div(report.TotalValue)
+		}
+		report.Positions[position.Symbol] = position
+	}
+	if err := positionRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 交易日期为当月首个交易日，按月份归入对应的月度报告
+	tradeRows, err := store.db.Query(`SELECT date, symbol, action, shares, price, amount, reason
+		FROM run_trades WHERE run_id = ? ORDER BY seq`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer tradeRows.Close()
+
+	for tradeRows.Next() {
+		var dateKey string
+		var fields [3]string
+		action := TradingAction{}
+		if err := tradeRows.Scan(&dateKey, &action.Symbol, &action.Action, &fields[0], &fields[1],
+			&fields[2], &action.Reason); err != nil {
+			return nil, err
+		}
+		action.Date, _ = time.Parse("2006-01-02", dateKey)
+		action.Shares, _ = decimal.NewFromString(fields[0])
+		action.Price, _ = decimal.NewFromString(fields[1])
+		action.Amount, _ = decimal.NewFromString(fields[2])
+		if report, exists := reportsByMonth[action.Date.Format("2006-01")]; exists {
+			report.TradingActions = append(report.TradingActions, action)
+		}
+	}
+	if err := tradeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// StorePriceSource 数据库中某个来源分类的股价数据源
+type StorePriceSource struct {
+	store  *Store
+	source string
+}
+
+// NewStorePriceSource 创建数据库股价数据源
+func NewStorePriceSource(store *Store, source string) *StorePriceSource {
+	return &StorePriceSource{store: store, source: source}
+}
+
+// LoadStockPrice 加载指定股票的价格数据
+func (source *StorePriceSource) LoadStockPrice(symbol string) (map[string]*StockPrice, error) {
+	return source.store.loadPrices(source.source, symbol)
+}
+
+// ListSymbols 列出该来源分类中的所有股票代码
+func (source *StorePriceSource) ListSymbols() ([]string, error) {
+	return source.store.listSymbols(source.source)
+}
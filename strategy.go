@@ -1,8 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"math"
+	"log/slog"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -12,6 +13,9 @@ import (
 type TradingStrategy struct {
 	dataLoader *StockDataLoader
 	config     *Config
+	regime     *RegimeFilter // 市场状态过滤器，未启用时为 nil
+	metadata   map[string]*SymbolMetadata // 股票分类元数据，未加载时为 nil
+	live       bool                       // 按实际账户生成订单，现金已扣除券商计提的利息和费用
 }
 
 // NewTradingStrategy 创建新的交易策略
@@ -25,6 +29,11 @@ func NewTradingStrategy(dataLoader *StockDataLoader, config *Config) *TradingStr
 // ExecuteStrategy 执行交易策略
 func (strategy *TradingStrategy) ExecuteStrategy() ([]*MonthlyReport, error) {
 	var reports []*MonthlyReport
+
+	if err := strategy.prepare(); err != nil {
+		return nil, err
+	}
+
 	cash := decimal.NewFromFloat(strategy.config.InitialCapital)
 	portfolio := &Portfolio{
 		Cash:      cash,
@@ -37,12 +46,12 @@ func (strategy *TradingStrategy) ExecuteStrategy() ([]*MonthlyReport, error) {
 	monthIndex := 0
 
 	for currentDate.Before(strategy.config.EndDate) || currentDate.Equal(strategy.config.EndDate) {
-		fmt.Printf("处理 %d年%d月...\n", currentDate.Year(), int(currentDate.Month()))
+		slog.Info(T("处理月份"), "month", currentDate.Format("2006-01"))
 		
 		// 处理当月交易
 		report, err := strategy.processMonth(currentDate, portfolio, monthIndex)
 		if err != nil {
-			fmt.Printf("警告: 处理 %d年%d月 时出错: %v\n", currentDate.Year(), int(currentDate.Month()), err)
+			slog.Warn(T("处理月份失败"), "month", currentDate.Format("2006-01"), "err", err)
 			// 继续处理下一个月
 		} else {
 			reports = append(reports, report)
@@ -56,51 +65,148 @@ func (strategy *TradingStrategy) ExecuteStrategy() ([]*MonthlyReport, error) {
 	return reports, nil
 }
 
+// prepare 初始化市场状态过滤器并加载股票分类元数据
+func (strategy *TradingStrategy) prepare() error {
+	// 初始化市场状态过滤器
+	if strategy.config.RegimeEnabled {
+		regime, err := NewRegimeFilter(strategy.config)
+		if err != nil {
+			return fmt.Errorf(T("初始化市场状态过滤器失败: %v"), err)
+		}
+		strategy.regime = regime
+	}
+
+	// 加载股票分类元数据，设置板块上限时必须加载成功
+	if strategy.config.MetadataFile != "" {
+		metadata, err := LoadSymbolMetadata(strategy.config.MetadataFile)
+		if err != nil {
+			if strategy.config.SectorCap > 0 {
+				return fmt.Errorf(T("加载股票分类元数据失败: %v"), err)
+			}
+			slog.Warn(T("加载股票分类元数据失败，跳过板块统计"), "file", strategy.config.MetadataFile, "err", err)
+		} else {
+			strategy.metadata = metadata
+		}
+	}
+	return nil
+}
+
 // processMonth 处理单个月的交易
 func (strategy *TradingStrategy) processMonth(date time.Time, portfolio *Portfolio, monthIndex int) (*MonthlyReport, error) {
 	
 	// 加载交易信号
 	signals, err := strategy.dataLoader.LoadTradeSignals(date)
 	if err != nil {
-		return nil, fmt.Errorf("加载交易信号失败: %v", err)
+		return nil, fmt.Errorf(T("加载交易信号失败: %v"), err)
 	}
 
 	var tradingActions []TradingAction
 	previousValue := portfolio.Value
 
+	// 0. 校验信号参考价格
+	signalIssues, skippedBuys := strategy.validateSignals(signals, portfolio, date)
+
+	// 0.1 计提融资利息和融券费用，实盘订单的现金已由券商扣除
+	interestCharged, borrowFee := decimal.Zero, decimal.Zero
+	if !strategy.live {
+		interestCharged = strategy.chargeMarginInterest(portfolio)
+		borrowFee = strategy.chargeBorrowFee(portfolio)
+	}
+
 	// 1. 处理剔除股票
+	var stocksToShort []*TradeSignal
 	for _, signal := range signals {
 		if signal.Status == "剔除" {
-			if position, exists := portfolio.Positions[signal.Symbol]; exists {
+			delete(portfolio.Sidelined, signal.Symbol)
+			position, exists := portfolio.Positions[signal.Symbol]
+			if exists && isShort(position) {
+				continue
+			}
+			if exists {
 				action, err := strategy.sellStock(signal.Symbol, position, portfolio, date)
 				if err != nil {
-					fmt.Printf("警告: 卖出股票 %s 失败: %v\n", signal.Symbol, err)
+					slog.Warn(T("卖出股票失败"), "month", date.Format("2006-01"), "symbol", signal.Symbol, "err", err)
 					continue
 				}
 				tradingActions = append(tradingActions, *action)
 			}
+			if strategy.config.LongShort {
+				stocksToShort = append(stocksToShort, signal)
+			}
 		}
 	}
 
-	// 2. 计算渐进式建仓比例
-	allocationRatio := strategy.calculateAllocationRatio(monthIndex)
+	// 1.1 多空模式下做空剔除股票
+	if len(stocksToShort) > 0 {
+		shortActions, err := strategy.shortStocks(stocksToShort, portfolio, date)
+		if err != nil {
+			slog.Warn(T("做空股票失败"), "month", date.Format("2006-01"), "err", err)
+		} else {
+			tradingActions = append(tradingActions, shortActions...)
+		}
+	}
+
+	// 2. 计算渐进式建仓比例，risk-off 或波动率目标下调时将超出部分转为现金
+	regime := strategy.evaluateRegime(date)
+	volTarget := strategy.evaluateVolTarget(portfolio, signals, date)
+	allocationRatio := strategy.calculateAllocationRatio(monthIndex, regime, volTarget)
+	riskOff := regime != nil && regime.State == RegimeRiskOff
+	if riskOff || volTarget != nil {
+		reason := TradeReasonVolTarget
+		if riskOff {
+			reason = TradeReasonRiskOff
+		}
+		reduceActions, err := strategy.reduceToTarget(portfolio, date, allocationRatio, reason)
+		if err != nil {
+			slog.Warn(T("减仓至目标仓位失败"), "month", date.Format("2006-01"), "err", err)
+		} else {
+			tradingActions = append(tradingActions, reduceActions...)
+		}
+	}
 	
 	// 3. 处理纳入股票
 	var stocksToBuy []*TradeSignal
 	for _, signal := range signals {
 		if signal.Status == "纳入" {
-			// 检查是否已持有该股票
-			if _, exists := portfolio.Positions[signal.Symbol]; !exists {
+			// 检查是否已持有该股票，空头仓位先回补再买入
+			position, exists := portfolio.Positions[signal.Symbol]
+			if exists && isShort(position) {
+				action, err := strategy.coverStock(signal.Symbol, position, portfolio, date)
+				if err != nil {
+					slog.Warn(T("回补股票失败"), "month", date.Format("2006-01"), "symbol", signal.Symbol, "err", err)
+					continue
+				}
+				tradingActions = append(tradingActions, *action)
+				exists = false
+			}
+			if !exists && !skippedBuys[signal.Symbol] {
+				// 减仓时清空的股票再次纳入时按新纳入买入，不再单独买回
+				delete(portfolio.Sidelined, signal.Symbol)
 				stocksToBuy = append(stocksToBuy, signal)
 			}
 		}
 	}
 
+	// 3.1 市场状态或波动率目标使建仓比例上调时，先加仓已持有的多头并买回减仓时清空的股票，
+	// 按等权为本月新纳入的股票预留份额
+	scaledUp := false
+	if (strategy.regime != nil || volTarget != nil) && allocationRatio.GreaterThan(portfolio.AllocationRatio) {
+		increaseActions, err := strategy.increaseToTarget(portfolio, date, allocationRatio, len(stocksToBuy))
+		if err != nil {
+			slog.Warn(T("加仓至目标仓位失败"), "month", date.Format("2006-01"), "err", err)
+		} else {
+			tradingActions = append(tradingActions, increaseActions...)
+			scaledUp = len(increaseActions) > 0
+		}
+	}
+	portfolio.AllocationRatio = allocationRatio
+
 	// 4. 执行买入操作
 	if len(stocksToBuy) > 0 {
-		buyActions, err := strategy.buyStocks(stocksToBuy, portfolio, date, allocationRatio)
+		limitToTarget := strategy.config.LongShort || riskOff || volTarget != nil || scaledUp
+		buyActions, err := strategy.buyStocks(stocksToBuy, portfolio, date, allocationRatio, limitToTarget)
 		if err != nil {
-			fmt.Printf("警告: 买入股票失败: %v\n", err)
+			slog.Warn(T("买入股票失败"), "month", date.Format("2006-01"), "err", err)
 		} else {
 			tradingActions = append(tradingActions, buyActions...)
 		}
@@ -109,7 +215,16 @@ func (strategy *TradingStrategy) processMonth(date time.Time, portfolio *Portfol
 	// 5. 更新投资组合价值
 	err = strategy.updatePortfolioValue(portfolio, date)
 	if err != nil {
-		return nil, fmt.Errorf("更新投资组合价值失败: %v", err)
+		return nil, fmt.Errorf(T("更新投资组合价值失败: %v"), err)
+	}
+
+	// 5.1 检查维持保证金，必要时强制平仓
+	if marginActions := strategy.checkMarginCall(portfolio, date); len(marginActions) > 0 {
+		tradingActions = append(tradingActions, marginActions...)
+		err = strategy.updatePortfolioValue(portfolio, date)
+		if err != nil {
+			return nil, fmt.Errorf(T("更新投资组合价值失败: %v"), err)
+		}
 	}
 
 	// 6. 计算收益率
@@ -123,11 +238,24 @@ func (strategy *TradingStrategy) processMonth(date time.Time, portfolio *Portfol
 	}
 
 	// 7. 生成月度报告
+	borrowed := borrowedAmount(portfolio)
+	longValue, shortValue := exposureValues(portfolio.Positions)
 	report := &MonthlyReport{
 		Date:             date,
 		TotalValue:       portfolio.Value,
-		Cash:             portfolio.Cash,
+		Cash:             portfolio.Cash.Add(borrowed),
 		StockValue:       portfolio.Value.Sub(portfolio.Cash),
+		Borrowed:         borrowed,
+		InterestCharged:  interestCharged,
+		Leverage:         leverageRatio(portfolio),
+		LongValue:        longValue,
+		ShortValue:       shortValue,
+		BorrowFee:        borrowFee,
+		Regime:           regimeLabel(regime),
+		AllocationRatio:  allocationRatio,
+		ExAnteVolatility: exAnteVolatility(volTarget),
+		SectorWeights:    sectorWeights(strategy.metadata, portfolio.Positions, portfolio.Value),
+		SignalIssues:     signalIssues,
 		MonthlyReturn:    monthlyReturn,
 		CumulativeReturn: cumulativeReturn,
 		Positions:        copyPositions(portfolio.Positions),
@@ -138,35 +266,64 @@ func (strategy *TradingStrategy) processMonth(date time.Time, portfolio *Portfol
 }
 
 // calculateAllocationRatio 计算满仓建仓比例
-func (strategy *TradingStrategy) calculateAllocationRatio(monthIndex int) decimal.Decimal {
-	// 初始满仓策略：直接使用90%资金建仓（保留10%现金）
-	maxRatio := 0.9 // 始终保持90%资金投资，10%现金
+// volTarget 不为 nil 时以目标波动率仓位替代固定比例，regime 不为 nil 时按市场状态缩放
+func (strategy *TradingStrategy) calculateAllocationRatio(monthIndex int, regime *RegimeState, volTarget *VolTargetState) decimal.Decimal {
+	// 多空模式下多头比例由总敞口和净敞口决定
+	if strategy.config.LongShort {
+		longRatio, _ := strategy.longShortRatios()
+		return scaleByRegime(longRatio, regime)
+	}
+
+	// 波动率目标模式：总仓位 = 目标波动率 / 预估波动率
+	if volTarget != nil {
+		return scaleByRegime(volTarget.Exposure, regime)
+	}
+
+	// 初始满仓策略：默认使用90%资金建仓（保留10%现金），大于1时表示融资加杠杆
+	maxRatio := strategy.config.AllocationRatio
 	
-	return decimal.NewFromFloat(maxRatio)
+	return scaleByRegime(decimal.NewFromFloat(maxRatio), regime)
 }
 
-// sellStock 卖出股票
-func (strategy *TradingStrategy) sellStock(symbol string, position *Position, portfolio *Portfolio, date time.Time) (*TradingAction, error) {
-	// 获取当前股价
+// scaleByRegime 按市场状态缩放建仓比例
+func scaleByRegime(ratio decimal.Decimal, regime *RegimeState) decimal.Decimal {
+	if regime == nil {
+		return ratio
+	}
+	return ratio.Mul(regime.Scale)
+}
+
+// tradingDayPrice 获取指定股票在当月第一个交易日的日期和股价
+func (strategy *TradingStrategy) tradingDayPrice(symbol string, date time.Time) (time.Time, *StockPrice, error) {
 	stockPrices, err := strategy.dataLoader.LoadStockPrice(symbol)
 	if err != nil {
-		return nil, fmt.Errorf("加载股价数据失败: %v", err)
+		return time.Time{}, nil, fmt.Errorf(T("加载股价数据失败: %v"), err)
 	}
 
-	// 获取当月第一个交易日
 	tradingDay, err := strategy.dataLoader.GetFirstTradingDay(date.Year(), int(date.Month()), stockPrices)
 	if err != nil {
-		return nil, fmt.Errorf("获取交易日失败: %v", err)
+		return time.Time{}, nil, fmt.Errorf(T("获取交易日失败: %v"), err)
 	}
 
 	tradingDayKey := tradingDay.Format("20060102")
 	stockPrice, exists := stockPrices[tradingDayKey]
 	if !exists {
-		return nil, fmt.Errorf("未找到 %s 在 %s 的股价数据", symbol, tradingDayKey)
+		return time.Time{}, nil, fmt.Errorf(T("未找到 %s 在 %s 的股价数据"), symbol, tradingDayKey)
+	}
+
+	return tradingDay, stockPrice, nil
+}
+
+// sellStock 卖出股票
+func (strategy *TradingStrategy) sellStock(symbol string, position *Position, portfolio *Portfolio, date time.Time) (*TradingAction, error) {
+	// 获取当月第一个交易日的股价
+	tradingDay, stockPrice, err := strategy.tradingDayPrice(symbol, date)
+	if err != nil {
+		return nil, err
 	}
 
 	// 计算卖出金额
-	sellAmount := stockPrice.Close.Mul(decimal.NewFromInt(int64(position.Shares)))
+	sellAmount := stockPrice.Close.Mul(position.Shares)
 	
 	// 更新现金和持仓
 	portfolio.Cash = portfolio.Cash.Add(sellAmount)
@@ -180,17 +337,17 @@ func (strategy *TradingStrategy) sellStock(symbol string, position *Position, po
 		Shares: position.Shares,
 		Price:  stockPrice.Close,
 		Amount: sellAmount,
-		Reason: "股票被剔除",
+		Reason: TradeReasonExcluded,
 	}
 
-	fmt.Printf("卖出: %s, 股数: %d, 价格: %s, 金额: %s\n", 
-		symbol, position.Shares, stockPrice.Close.String(), sellAmount.String())
+	logTrade(action)
 
 	return action, nil
 }
 
 // buyStocks 买入股票
-func (strategy *TradingStrategy) buyStocks(stocksToBuy []*TradeSignal, portfolio *Portfolio, date time.Time, allocationRatio decimal.Decimal) ([]TradingAction, error) {
+// limitToTarget 为 true 时多头仅补足至 总价值 × 建仓比例
+func (strategy *TradingStrategy) buyStocks(stocksToBuy []*TradeSignal, portfolio *Portfolio, date time.Time, allocationRatio decimal.Decimal, limitToTarget bool) ([]TradingAction, error) {
 	var actions []TradingAction
 	
 	if len(stocksToBuy) == 0 {
@@ -201,20 +358,42 @@ func (strategy *TradingStrategy) buyStocks(stocksToBuy []*TradeSignal, portfolio
 	totalValue := portfolio.Value
 	availableCash := totalValue.Mul(allocationRatio)
 	
-	// 如果可用资金超过当前现金，使用当前现金
-	if availableCash.GreaterThan(portfolio.Cash) {
-		availableCash = portfolio.Cash
+	// 如果可用资金超过当前购买力（现金及可融资额度），使用当前购买力
+	buyingPower := strategy.buyingPower(portfolio, allocationRatio)
+	if availableCash.GreaterThan(buyingPower) {
+		availableCash = buyingPower
 	}
 
-	// 等分分配给所有要买入的股票
-	cashPerStock := availableCash.Div(decimal.NewFromInt(int64(len(stocksToBuy))))
+	// 多空模式下现金包含卖空所得、减仓后现金包含减仓所得，多头仅补足至目标多头敞口
+	if limitToTarget {
+		longValue, _ := exposureValues(portfolio.Positions)
+		longRoom := totalValue.Mul(allocationRatio).Sub(longValue)
+		if availableCash.GreaterThan(longRoom) {
+			availableCash = longRoom
+		}
+		if !availableCash.IsPositive() {
+			return actions, errors.New(T("多头敞口已达上限"))
+		}
+	}
+
+	// 分配给所有要买入的股票（默认等分）
+	amounts := strategy.signalAllocations(stocksToBuy, availableCash)
 	
-	fmt.Printf("可用资金: %s, 每只股票分配: %s\n", availableCash.String(), cashPerStock.String())
+	slog.Debug(T("分配买入资金"), "month", date.Format("2006-01"), "available", availableCash,
+		"weighting", strategy.config.SignalWeighting, "stocks", len(stocksToBuy))
+
+	for i, signal := range stocksToBuy {
+		// 板块上限：不超过所属板块剩余额度
+		amount := amounts[i]
+		if room, ok := strategy.sectorRoom(signal.Symbol, portfolio); ok && room.LessThan(amount) {
+			slog.Info(T("板块上限调整买入资金"), "symbol", signal.Symbol, "sector", sectorOf(strategy.metadata, signal.Symbol),
+				"amount", amount.StringFixed(2), "capped", room.StringFixed(2))
+			amount = room
+		}
 
-	for _, signal := range stocksToBuy {
-		action, err := strategy.buyStock(signal.Symbol, cashPerStock, portfolio, date)
+		action, err := strategy.buyStock(signal.Symbol, amount, portfolio, date, allocationRatio)
 		if err != nil {
-			fmt.Printf("警告: 买入股票 %s 失败: %v\n", signal.Symbol, err)
+			slog.Warn(T("买入股票失败"), "month", date.Format("2006-01"), "symbol", signal.Symbol, "err", err)
 			continue
 		}
 		actions = append(actions, *action)
@@ -224,37 +403,25 @@ func (strategy *TradingStrategy) buyStocks(stocksToBuy []*TradeSignal, portfolio
 }
 
 // buyStock 买入单只股票
-func (strategy *TradingStrategy) buyStock(symbol string, cashAmount decimal.Decimal, portfolio *Portfolio, date time.Time) (*TradingAction, error) {
-	// 获取股价数据
-	stockPrices, err := strategy.dataLoader.LoadStockPrice(symbol)
+func (strategy *TradingStrategy) buyStock(symbol string, cashAmount decimal.Decimal, portfolio *Portfolio, date time.Time, allocationRatio decimal.Decimal) (*TradingAction, error) {
+	// 获取当月第一个交易日的股价
+	tradingDay, stockPrice, err := strategy.tradingDayPrice(symbol, date)
 	if err != nil {
-		return nil, fmt.Errorf("加载股价数据失败: %v", err)
+		return nil, err
 	}
 
-	// 获取当月第一个交易日
-	tradingDay, err := strategy.dataLoader.GetFirstTradingDay(date.Year(), int(date.Month()), stockPrices)
-	if err != nil {
-		return nil, fmt.Errorf("获取交易日失败: %v", err)
-	}
-
-	tradingDayKey := tradingDay.Format("20060102")
-	stockPrice, exists := stockPrices[tradingDayKey]
-	if !exists {
-		return nil, fmt.Errorf("未找到 %s 在 %s 的股价数据", symbol, tradingDayKey)
-	}
-
-	// 计算可买入的整数股数
-	shares := int(math.Floor(cashAmount.Div(stockPrice.Close).InexactFloat64()))
-	if shares <= 0 {
-		return nil, fmt.Errorf("资金不足以买入 %s", symbol)
+	// 计算可买入股数
+	shares := strategy.calculateShares(cashAmount, stockPrice.Close)
+	if !shares.IsPositive() {
+		return nil, fmt.Errorf(T("资金不足以买入 %s"), symbol)
 	}
 
 	// 计算实际花费金额
-	actualAmount := stockPrice.Close.Mul(decimal.NewFromInt(int64(shares)))
+	actualAmount := stockPrice.Close.Mul(shares)
 	
-	// 检查现金是否足够
-	if actualAmount.GreaterThan(portfolio.Cash) {
-		return nil, fmt.Errorf("现金不足以买入 %s", symbol)
+	// 检查现金（含可融资额度）是否足够
+	if actualAmount.GreaterThan(strategy.buyingPower(portfolio, allocationRatio)) {
+		return nil, fmt.Errorf(T("现金不足以买入 %s"), symbol)
 	}
 
 	// 更新现金和持仓
@@ -282,15 +449,27 @@ func (strategy *TradingStrategy) buyStock(symbol string, cashAmount decimal.Deci
 		Shares: shares,
 		Price:  stockPrice.Close,
 		Amount: actualAmount,
-		Reason: "股票被纳入",
+		Reason: TradeReasonIncluded,
 	}
 
-	fmt.Printf("买入: %s, 股数: %d, 价格: %s, 金额: %s\n", 
-		symbol, shares, stockPrice.Close.String(), actualAmount.String())
+	logTrade(action)
 
 	return action, nil
 }
 
+// calculateShares 计算给定资金可买入的股数
+// 默认向下取整到整数股；开启碎股模式时按配置精度向下截断
+func (strategy *TradingStrategy) calculateShares(cashAmount, price decimal.Decimal) decimal.Decimal {
+	if price.IsZero() {
+		return decimal.Zero
+	}
+	shares := cashAmount.Div(price)
+	if strategy.config.FractionalShares {
+		return shares.Truncate(strategy.config.SharePrecision)
+	}
+	return shares.Floor()
+}
+
 // updatePortfolioValue 更新投资组合价值
 func (strategy *TradingStrategy) updatePortfolioValue(portfolio *Portfolio, date time.Time) error {
 	totalStockValue := decimal.Zero
@@ -299,30 +478,30 @@ func (strategy *TradingStrategy) updatePortfolioValue(portfolio *Portfolio, date
 		// 获取当前股价
 		stockPrices, err := strategy.dataLoader.LoadStockPrice(symbol)
 		if err != nil {
-			fmt.Printf("警告: 无法加载 %s 的股价数据: %v\n", symbol, err)
+			slog.Warn(T("无法加载股价数据"), "symbol", symbol, "err", err)
 			continue
 		}
 
 		// 获取当月第一个交易日的价格
 		tradingDay, err := strategy.dataLoader.GetFirstTradingDay(date.Year(), int(date.Month()), stockPrices)
 		if err != nil {
-			fmt.Printf("警告: 无法获取 %s 的交易日: %v\n", symbol, err)
+			slog.Warn(T("无法获取交易日"), "symbol", symbol, "month", date.Format("2006-01"), "err", err)
 			continue
 		}
 
 		tradingDayKey := tradingDay.Format("20060102")
 		stockPrice, exists := stockPrices[tradingDayKey]
 		if !exists {
-			fmt.Printf("警告: 未找到 %s 在 %s 的股价数据\n", symbol, tradingDayKey)
+			slog.Warn(T("未找到股价数据"), "symbol", symbol, "date", tradingDay.Format("2006-01-02"))
 			continue
 		}
 
 		// 更新持仓信息
 		position.CurrentPrice = stockPrice.Close
-		position.MarketValue = stockPrice.Close.Mul(decimal.NewFromInt(int64(position.Shares)))
+		position.MarketValue = stockPrice.Close.Mul(position.Shares)
 		position.PnL = position.MarketValue.Sub(position.CostBasis)
 		if !position.CostBasis.IsZero() {
-			position.PnLPercent = position.PnL.Div(position.CostBasis)
+			position.PnLPercent = position.PnL.Div(position.CostBasis.Abs())
 		}
 		
 		totalStockValue = totalStockValue.Add(position.MarketValue)
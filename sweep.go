@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// sweepExcludedFlags 不能扫描的参数：输出、日志和运行标识与回测结果无关
+var sweepExcludedFlags = map[string]bool{
+	"param": true, "lang": true, "log-level": true, "log-format": true, "log-file": true,
+	"output-dir": true, "output-format": true, "md-template": true, "assets-dir": true,
+	"db": true, "run-id": true, "benchmark": true, "top-drawdowns": true,
+}
+
+// sweepParam 扫描的参数及其取值
+type sweepParam struct {
+	Name   string   // run 子命令的参数名（不含 -）
+	Values []string // 取值，按命令行中的顺序
+}
+
+// sweepParams -param 参数，格式为 name=v1,v2,...，可重复指定
+type sweepParams []sweepParam
+
+// String 实现 flag.Value
+func (params *sweepParams) String() string {
+	parts := make([]string, 0, len(*params))
+	for _, param := range *params {
+		parts = append(parts, param.Name+"="+strings.Join(param.Values, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Set 实现 flag.Value
+func (params *sweepParams) Set(value string) error {
+	name, list, ok := strings.Cut(value, "=")
+	name = strings.TrimPrefix(strings.TrimSpace(name), "-")
+	if !ok || name == "" || strings.TrimSpace(list) == "" {
+		return fmt.Errorf(T("扫描参数格式应为 name=v1,v2,...: %s"), value)
+	}
+	for _, param := range *params {
+		if param.Name == name {
+			return fmt.Errorf(T("重复的扫描参数: %s"), name)
+		}
+	}
+
+	param := sweepParam{Name: name}
+	for _, v := range strings.Split(list, ",") {
+		param.Values = append(param.Values, strings.TrimSpace(v))
+	}
+	*params = append(*params, param)
+	return nil
+}
+
+// combinations 返回所有参数取值的组合（笛卡尔积），后面的参数变化最快
+func (params sweepParams) combinations() [][]string {
+	combos := [][]string{{}}
+	for _, param := range params {
+		var next [][]string
+		for _, combo := range combos {
+			for _, value := range param.Values {
+				extended := append(append([]string{}, combo...), value)
+				next = append(next, extended)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// SweepResult 参数扫描中单个组合的回测结果
+type SweepResult struct {
+	Index      int                 // 组合序号，从 1 开始
+	Values     []string            // 与扫描参数一一对应的取值
+	RunDir     string              // 保存 run.json 的目录
+	Metrics    *PerformanceMetrics // 绩效指标
+	FinalValue decimal.Decimal     // 期末总价值
+}
+
+// sweepRunDir 单个组合的运行目录：输出目录/sweep/序号
+func sweepRunDir(outputDir string, index int) string {
+	return filepath.Join(outputDir, "sweep", fmt.Sprintf("%03d", index))
+}
+
+// WriteSweepReport 将扫描结果写入输出目录下的 sweep.csv，比例为小数形式
+func WriteSweepReport(outputDir string, params sweepParams, results []*SweepResult) (string, error) {
+	filePath := filepath.Join(outputDir, "sweep.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf(T("创建文件 %s 失败: %v"), filePath, err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	header := []string{"run"}
+	for _, param := range params {
+		header = append(header, param.Name)
+	}
+	header = append(header, "final_value", "total_return", "annualized_return", "max_drawdown",
+		"volatility", "sharpe_ratio", "win_rate", "total_trades", "run_dir")
+	if err := writer.Write(header); err != nil {
+		return "", fmt.Errorf(T("写入标题失败: %v"), err)
+	}
+
+	for _, result := range results {
+		metrics := result.Metrics
+		row := append([]string{strconv.Itoa(result.Index)}, result.Values...)
+		row = append(row,
+			result.FinalValue.StringFixed(2),
+			metrics.TotalReturn.StringFixed(6),
+			metrics.AnnualizedReturn.StringFixed(6),
+			metrics.MaxDrawdown.StringFixed(6),
+			metrics.Volatility.StringFixed(6),
+			metrics.SharpeRatio.StringFixed(4),
+			metrics.WinRate.StringFixed(4),
+			strconv.Itoa(metrics.TotalTrades),
+			filepath.ToSlash(result.RunDir))
+		if err := writer.Write(row); err != nil {
+			return "", fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf(T("写入文件 %s 失败: %v"), filePath, err)
+	}
+	return filePath, nil
+}
+
+// PrintSweepResults 输出扫描结果表
+func PrintSweepResults(w io.Writer, params sweepParams, results []*SweepResult) {
+	hundred := decimal.NewFromInt(100)
+
+	fmt.Fprintf(w, "%-4s", "#")
+	for _, param := range params {
+		fmt.Fprintf(w, " %-16s", param.Name)
+	}
+	fmt.Fprintf(w, " %14s %10s %10s %10s %8s %8s\n",
+		T("Final Value"), T("Return %"), T("Annual %"), T("Max DD %"), T("Sharpe"), T("Trades"))
+
+	for _, result := range results {
+		metrics := result.Metrics
+		fmt.Fprintf(w, "%-4d", result.Index)
+		for _, value := range result.Values {
+			fmt.Fprintf(w, " %-16s", value)
+		}
+		fmt.Fprintf(w, " %14s %10s %10s %10s %8s %8d\n",
+			result.FinalValue.StringFixed(2),
+			metrics.TotalReturn.Mul(hundred).StringFixed(2),
+			metrics.AnnualizedReturn.Mul(hundred).StringFixed(2),
+			metrics.MaxDrawdown.Mul(hundred).StringFixed(2),
+			metrics.SharpeRatio.StringFixed(2), metrics.TotalTrades)
+	}
+}
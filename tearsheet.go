@@ -0,0 +1,480 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/render"
+	"github.com/shopspring/decimal"
+)
+
+// echartsAssets 内置的 go-echarts 资源（echarts.min.js、themes/westeros.js），单页报告默认内联这些脚本；
+// 路径与 go-echarts 资源地址一致，可用 go generate 从 go-echarts 资源站更新
+//
+//go:generate curl -fsSL --create-dirs -o assets/echarts.min.js https://go-echarts.github.io/go-echarts-assets/assets/echarts.min.js
+//go:generate curl -fsSL --create-dirs -o assets/themes/westeros.js https://go-echarts.github.io/go-echarts-assets/assets/themes/westeros.js
+//go:embed assets
+var echartsAssets embed.FS
+
+// tearSheetChart 嵌入报告的单个图表
+type tearSheetChart struct {
+	Element template.HTML // 图表容器
+	Script  template.HTML // 初始化脚本
+}
+
+// tearSheetTable 报告中的表格
+type tearSheetTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+}
+
+// tearSheetSection 报告中的一个章节
+type tearSheetSection struct {
+	Title     string
+	Charts    []tearSheetChart
+	Tables    []tearSheetTable
+	Collapsed bool // 默认折叠（用于较长的表格）
+}
+
+// tearSheetMetric 策略与基准对比的绩效指标
+type tearSheetMetric struct {
+	Name      string
+	Strategy  string
+	Benchmark string
+}
+
+// tearSheetData 报告模板数据
+type tearSheetData struct {
+	Lang        string // 页面语言
+	Title       string
+	Period      string
+	GeneratedAt string
+	Benchmark   string // 基准代码，未加载基准时为空
+	Summary     []tearSheetMetric
+	Metrics     []tearSheetMetric
+	Sections    []tearSheetSection
+	Scripts     []tearSheetScript // 按加载顺序排列的 JS 资源
+}
+
+// tearSheetScript JS 资源，Src 为远程地址，否则内联 Inline
+type tearSheetScript struct {
+	Src    string
+	Inline template.JS
+}
+
+// GenerateTearSheet 生成单页 HTML 报告 report.html，包含绩效指标、净值与基准对比、回撤、月度收益、持仓、归因和交易记录
+func (cg *ChartGenerator) GenerateTearSheet(reports []*MonthlyReport) error {
+	if len(reports) == 0 {
+		return errors.New(T("no report data available"))
+	}
+
+	var benchmark *BenchmarkCurve
+	if cg.config.Benchmark != "" {
+		curve, err := LoadBenchmarkCurve(cg.config, reports)
+		if err != nil {
+			slog.Warn(T("加载基准失败，报告中不显示基准"), "benchmark", cg.config.Benchmark, "err", err)
+		} else {
+			benchmark = curve
+		}
+	}
+
+	lastReport := reports[len(reports)-1]
+	initialCapital := decimal.NewFromFloat(cg.config.InitialCapital)
+	attribution := AnalyzeAttribution(reports, initialCapital)
+
+	// 使用 go-echarts 页面组件汇总各图表的 JS 资源
+	equityChart := cg.equityCurveChart(reports, benchmark)
+	underwater := cg.underwaterChart(reports)
+	heatmap := cg.monthlyReturnsHeatmap(reports)
+	distribution := cg.positionDistributionChart(lastReport)
+	attributionBar := cg.attributionChart(reports)
+
+	page := components.NewPage()
+	page.SetPageTitle(T("Tech Titans Tear Sheet"))
+	page.AddCharts(equityChart, underwater, heatmap, distribution, attributionBar)
+
+	var sectorChart tearSheetChart
+	hasSectors := len(sortedSectors(reports)) > 0
+	if hasSectors {
+		sectorLine := cg.sectorAllocationChart(reports)
+		page.AddCharts(sectorLine)
+		sectorChart = snippetChart(sectorLine.RenderSnippet())
+	}
+	page.Validate()
+
+	data := tearSheetData{
+		Lang:  Language(),
+		Title: T("Tech Titans Tear Sheet"),
+		Period: fmt.Sprintf("%s - %s",
+			cg.config.StartDate.Format("2006-01-02"), cg.config.EndDate.Format("2006-01-02")),
+		GeneratedAt: time.Now().Format("2006-01-02 15:04"),
+		Summary: []tearSheetMetric{
+			{Name: T("Initial Capital"), Strategy: "$" + initialCapital.StringFixed(2)},
+			{Name: T("Final Value"), Strategy: "$" + lastReport.TotalValue.StringFixed(2)},
+			{Name: T("Cash"), Strategy: "$" + lastReport.Cash.StringFixed(2)},
+			{Name: T("Positions"), Strategy: strconv.Itoa(len(lastReport.Positions))},
+			{Name: T("Months"), Strategy: strconv.Itoa(len(reports))},
+		},
+	}
+	if benchmark != nil {
+		data.Benchmark = benchmark.Symbol
+	}
+	data.Metrics = tearSheetMetrics(reports, benchmark)
+	data.Scripts = cg.tearSheetScripts(page)
+
+	holdings := tearSheetSection{
+		Title:  T("Current Holdings"),
+		Charts: []tearSheetChart{snippetChart(distribution.RenderSnippet())},
+		Tables: []tearSheetTable{holdingsTable(lastReport)},
+	}
+	if hasSectors {
+		holdings.Charts = append(holdings.Charts, sectorChart)
+	}
+
+	data.Sections = []tearSheetSection{
+		{
+			Title:  T("Equity Curve"),
+			Charts: []tearSheetChart{snippetChart(equityChart.RenderSnippet())},
+		},
+		{
+			Title:  T("Drawdowns"),
+			Charts: []tearSheetChart{snippetChart(underwater.RenderSnippet())},
+			Tables: []tearSheetTable{drawdownTable(reports, cg.config.TopDrawdowns)},
+		},
+		{
+			Title:  T("Monthly Returns"),
+			Charts: []tearSheetChart{snippetChart(heatmap.RenderSnippet())},
+		},
+		holdings,
+		{
+			Title:  T("Attribution"),
+			Charts: []tearSheetChart{snippetChart(attributionBar.RenderSnippet())},
+			Tables: []tearSheetTable{attributionTable(attribution)},
+		},
+		{
+			Title:     T("Trade Log"),
+			Tables:    []tearSheetTable{tradeLogTable(reports)},
+			Collapsed: true,
+		},
+	}
+
+	tpl, err := template.New("tearsheet").Funcs(template.FuncMap{"T": T}).Parse(tearSheetTemplate)
+	if err != nil {
+		return fmt.Errorf(T("failed to parse tear sheet template: %v"), err)
+	}
+
+	filePath := filepath.Join(cg.config.OutputDir, "report.html")
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tpl.Execute(f, data); err != nil {
+		return fmt.Errorf(T("failed to render tear sheet: %v"), err)
+	}
+
+	slog.Info(T("Tear sheet generated"), "path", filePath)
+	return nil
+}
+
+// tearSheetScripts 返回页面所需的 JS 资源：默认内联，设置了本地资源目录时优先使用其中的文件，
+// 否则使用内置资源；指定 AssetsCDN 或资源不可用时从 CDN 加载
+func (cg *ChartGenerator) tearSheetScripts(page *components.Page) []tearSheetScript {
+	var scripts []tearSheetScript
+	for _, asset := range page.JSAssets.Values {
+		if cg.config.AssetsCDN {
+			scripts = append(scripts, tearSheetScript{Src: asset})
+			continue
+		}
+		name := strings.TrimPrefix(asset, page.AssetsHost)
+		content, err := cg.readAsset(name)
+		if err != nil {
+			slog.Warn(T("本地资源不可用，改为远程加载"), "asset", name, "err", err)
+			scripts = append(scripts, tearSheetScript{Src: asset})
+			continue
+		}
+		// 避免脚本内容中的 </script> 提前结束标签
+		inline := strings.ReplaceAll(string(content), "</script", "<\\/script")
+		scripts = append(scripts, tearSheetScript{Inline: template.JS(inline)})
+	}
+	return scripts
+}
+
+// readAsset 读取 go-echarts 资源：本地资源目录中存在时使用该文件，否则使用内置资源
+func (cg *ChartGenerator) readAsset(name string) ([]byte, error) {
+	if cg.config.AssetsDir != "" {
+		content, err := os.ReadFile(filepath.Join(cg.config.AssetsDir, filepath.FromSlash(name)))
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return echartsAssets.ReadFile(path.Join("assets", name))
+}
+
+// snippetChart 将 go-echarts 图表片段转换为模板数据
+func snippetChart(snippet render.ChartSnippet) tearSheetChart {
+	return tearSheetChart{
+		Element: template.HTML(snippet.Element),
+		Script:  template.HTML(snippet.Script),
+	}
+}
+
+// tearSheetMetrics 计算策略及基准的绩效指标对比
+func tearSheetMetrics(reports []*MonthlyReport, benchmark *BenchmarkCurve) []tearSheetMetric {
+	strategy := CalculatePerformanceMetrics(reports)
+	var benchmarkMetrics *PerformanceMetrics
+	if benchmark != nil {
+		benchmarkMetrics = CalculatePerformanceMetrics(benchmark.Reports)
+	}
+
+	percent := func(value decimal.Decimal) string {
+		return value.Mul(decimal.NewFromInt(100)).StringFixed(2) + "%"
+	}
+	row := func(name string, value func(metrics *PerformanceMetrics) string) tearSheetMetric {
+		metric := tearSheetMetric{Name: name, Strategy: value(strategy)}
+		if benchmarkMetrics != nil {
+			metric.Benchmark = value(benchmarkMetrics)
+		}
+		return metric
+	}
+
+	return []tearSheetMetric{
+		row(T("Total Return"), func(m *PerformanceMetrics) string { return percent(m.TotalReturn) }),
+		row(T("Annualized Return"), func(m *PerformanceMetrics) string { return percent(m.AnnualizedReturn) }),
+		row(T("Max Drawdown"), func(m *PerformanceMetrics) string { return percent(m.MaxDrawdown) }),
+		row(T("Volatility (ann.)"), func(m *PerformanceMetrics) string { return percent(m.Volatility) }),
+		row(T("Sharpe Ratio"), func(m *PerformanceMetrics) string { return m.SharpeRatio.StringFixed(2) }),
+		row(T("Win Rate (monthly)"), func(m *PerformanceMetrics) string { return percent(m.WinRate) }),
+		row(T("Average Monthly Return"), func(m *PerformanceMetrics) string { return percent(m.AverageReturn) }),
+		{Name: T("Total Trades"), Strategy: strconv.Itoa(strategy.TotalTrades)},
+	}
+}
+
+// holdingsTable 期末持仓表，按市值排序
+func holdingsTable(report *MonthlyReport) tearSheetTable {
+	table := tearSheetTable{
+		Title: fmt.Sprintf(T("Holdings as of %s"), report.Date.Format("2006-01-02")),
+		Headers: []string{
+			"Symbol", "Buy Date", "Buy Price", "Price", "Shares",
+			"Market Value", "P&L", "P&L %", "Weight %",
+		},
+	}
+
+	var positions []*Position
+	for _, position := range report.Positions {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].MarketValue.GreaterThan(positions[j].MarketValue)
+	})
+
+	for _, position := range positions {
+		table.Rows = append(table.Rows, []string{
+			position.Symbol,
+			position.BuyDate.Format("2006-01-02"),
+			position.BuyPrice.StringFixed(2),
+			position.CurrentPrice.StringFixed(2),
+			position.Shares.String(),
+			position.MarketValue.StringFixed(2),
+			position.PnL.StringFixed(2),
+			position.PnLPercent.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			position.Weight.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		})
+	}
+	return table
+}
+
+// drawdownTable 回撤幅度最大的前 topN 个区间（0 表示全部）
+func drawdownTable(reports []*MonthlyReport, topN int) tearSheetTable {
+	table := tearSheetTable{
+		Title:   T("Worst Drawdowns"),
+		Headers: []string{"Rank", "Peak Date", "Trough Date", "Recovery Date", "Depth %", "Total Days"},
+	}
+
+	lastDate := reports[len(reports)-1].Date
+	for i, episode := range AnalyzeDrawdowns(reports) {
+		if topN > 0 && i >= topN {
+			break
+		}
+		recoveryDate := "-"
+		endDate := lastDate
+		if episode.Recovered {
+			recoveryDate = episode.RecoveryDate.Format("2006-01-02")
+			endDate = episode.RecoveryDate
+		}
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(i + 1),
+			episode.PeakDate.Format("2006-01-02"),
+			episode.TroughDate.Format("2006-01-02"),
+			recoveryDate,
+			episode.Depth.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			strconv.Itoa(durationDays(episode.PeakDate, endDate)),
+		})
+	}
+	return table
+}
+
+// attributionTable 累计贡献最大和最小的各10只股票
+func attributionTable(result *AttributionResult) tearSheetTable {
+	table := tearSheetTable{
+		Title:   T("Top and Bottom Contributors"),
+		Headers: []string{"Rank", "Symbol", "Total P&L", "Contribution %", "Periods Held", "Average Weight %", "Status"},
+	}
+
+	maxShow := 10
+	for i, attribution := range result.Symbols {
+		if i >= maxShow && i < len(result.Symbols)-maxShow {
+			continue
+		}
+		status := T("Closed")
+		if attribution.Open {
+			status = T("Open")
+		}
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(i + 1),
+			attribution.Symbol,
+			attribution.PnL.StringFixed(2),
+			attribution.Contribution.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			strconv.Itoa(attribution.PeriodsHeld),
+			attribution.AverageWeight.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			status,
+		})
+	}
+	return table
+}
+
+// tradeLogTable 全部交易记录，按时间先后排列
+func tradeLogTable(reports []*MonthlyReport) tearSheetTable {
+	table := tearSheetTable{
+		Headers: []string{"Date", "Action", "Symbol", "Shares", "Price", "Amount", "Reason"},
+	}
+	for _, report := range reports {
+		for _, action := range report.TradingActions {
+			table.Rows = append(table.Rows, []string{
+				action.Date.Format("2006-01-02"),
+				action.Action,
+				action.Symbol,
+				action.Shares.String(),
+				action.Price.StringFixed(2),
+				action.Amount.StringFixed(2),
+				TradeReasonText(action.Reason),
+			})
+		}
+	}
+	table.Title = fmt.Sprintf(T("%d Trades"), len(table.Rows))
+	return table
+}
+
+// tearSheetTemplate 单页报告模板
+const tearSheetTemplate = `<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+{{- range .Scripts }}
+{{- if .Src }}
+<script src="{{ .Src }}"></script>
+{{- else }}
+<script>{{ .Inline }}</script>
+{{- end }}
+{{- end }}
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #333; margin: 0; background: #f5f6f8; }
+header { background: #2f4554; color: #fff; padding: 20px 32px; }
+header h1 { margin: 0 0 4px; font-size: 24px; }
+header p { margin: 0; opacity: 0.8; }
+main { max-width: 1280px; margin: 0 auto; padding: 16px 32px 48px; }
+section { background: #fff; border-radius: 6px; padding: 16px 24px; margin-top: 16px; box-shadow: 0 1px 3px rgba(0, 0, 0, 0.08); }
+h2 { font-size: 18px; margin: 0 0 12px; border-bottom: 1px solid #e5e5e5; padding-bottom: 8px; }
+h3 { font-size: 15px; margin: 16px 0 8px; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; }
+.card { flex: 1 1 140px; background: #f9fafb; border: 1px solid #e5e7eb; border-radius: 4px; padding: 10px 14px; }
+.card .label { font-size: 12px; color: #6b7280; }
+.card .value { font-size: 18px; font-weight: 600; }
+.charts { display: flex; flex-wrap: wrap; justify-content: center; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #eee; text-align: right; white-space: nowrap; }
+th:first-child, td:first-child { text-align: left; }
+th { background: #f3f4f6; position: sticky; top: 0; }
+.scroll { max-height: 480px; overflow: auto; }
+summary { cursor: pointer; font-weight: 600; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{ .Title }}</h1>
+<p>{{ .Period }}{{ if .Benchmark }} · {{ T "Benchmark" }}: {{ .Benchmark }}{{ end }} · {{ T "Generated" }} {{ .GeneratedAt }}</p>
+</header>
+<main>
+<section>
+<h2>{{ T "Summary" }}</h2>
+<div class="cards">
+{{- range .Summary }}
+<div class="card"><div class="label">{{ .Name }}</div><div class="value">{{ .Strategy }}</div></div>
+{{- end }}
+</div>
+{{- if .Metrics }}
+<h3>{{ T "Performance Metrics" }}</h3>
+<table>
+<tr><th>{{ T "Metric" }}</th><th>{{ T "Strategy" }}</th>{{ if .Benchmark }}<th>{{ .Benchmark }}</th>{{ end }}</tr>
+{{- $benchmark := .Benchmark }}
+{{- range .Metrics }}
+<tr><td>{{ .Name }}</td><td>{{ .Strategy }}</td>{{ if $benchmark }}<td>{{ if .Benchmark }}{{ .Benchmark }}{{ else }}-{{ end }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- end }}
+</section>
+{{- range .Sections }}
+<section>
+<h2>{{ .Title }}</h2>
+{{- if .Charts }}
+<div class="charts">
+{{- range .Charts }}
+{{ .Element }}
+{{ .Script }}
+{{- end }}
+</div>
+{{- end }}
+{{- $collapsed := .Collapsed }}
+{{- range .Tables }}
+{{- if $collapsed }}
+<details>
+<summary>{{ .Title }}</summary>
+{{- else }}
+<h3>{{ .Title }}</h3>
+{{- end }}
+<div class="scroll">
+<table>
+<tr>{{ range .Headers }}<th>{{ T . }}</th>{{ end }}</tr>
+{{- range .Rows }}
+<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+</div>
+{{- if $collapsed }}
+</details>
+{{- end }}
+{{- end }}
+</section>
+{{- end }}
+</main>
+</body>
+</html>
+`
@@ -28,7 +28,7 @@ type TradeSignal struct {
 // Position 持仓信息
 type Position struct {
 	Symbol       string          // 股票代码
-	Shares       int             // 持股数量
+	Shares       decimal.Decimal // 持股数量（开启碎股模式时可为小数，空头为负数）
 	BuyPrice     decimal.Decimal // 买入价格
 	BuyDate      time.Time       // 买入日期
 	CurrentPrice decimal.Decimal // 当前价格
@@ -41,10 +41,12 @@ type Position struct {
 
 // Portfolio 投资组合
 type Portfolio struct {
-	Cash      decimal.Decimal            // 现金余额
-	Positions map[string]*Position       // 持仓映射
-	Value     decimal.Decimal            // 总价值
-	Date      time.Time                  // 日期
+	Cash            decimal.Decimal            // 现金余额
+	Positions       map[string]*Position       // 持仓映射
+	Value           decimal.Decimal            // 总价值
+	Date            time.Time                  // 日期
+	AllocationRatio decimal.Decimal            // 上次调仓使用的建仓比例，用于判断比例是否上调
+	Sidelined       map[string]decimal.Decimal // 减仓时清空但未被剔除的多头及清空前的股数，建仓比例上调时买回
 }
 
 // MonthlyReport 月度报告
@@ -53,6 +55,17 @@ type MonthlyReport struct {
 	TotalValue     decimal.Decimal          // 总价值
 	Cash           decimal.Decimal          // 现金
 	StockValue     decimal.Decimal          // 股票市值
+	Borrowed       decimal.Decimal          // 融资借款金额
+	InterestCharged decimal.Decimal         // 当月计提的融资利息
+	Leverage       decimal.Decimal          // 杠杆率（总敞口 / 净值）
+	LongValue      decimal.Decimal          // 多头市值
+	ShortValue     decimal.Decimal          // 空头市值（正数）
+	BorrowFee      decimal.Decimal          // 当月计提的融券费用
+	Regime         string                   // 市场状态（risk-on / risk-off），未启用时为空
+	AllocationRatio decimal.Decimal         // 当月目标建仓比例
+	ExAnteVolatility decimal.Decimal        // 波动率目标模式下的事前预估年化波动率
+	SectorWeights  map[string]decimal.Decimal // 板块权重，未加载元数据时为空
+	SignalIssues   []SignalIssue            // 当月信号校验问题
 	MonthlyReturn  decimal.Decimal          // 月度收益率
 	CumulativeReturn decimal.Decimal        // 累计收益率
 	Positions      map[string]*Position     // 持仓详情
@@ -63,11 +76,52 @@ type MonthlyReport struct {
 type TradingAction struct {
 	Date   time.Time       // 交易日期
 	Symbol string          // 股票代码
-	Action string          // 行为类型："BUY" 或 "SELL"
-	Shares int             // 股数
+	Action string          // 行为类型："BUY"、"SELL"、"SHORT" 或 "COVER"
+	Shares decimal.Decimal // 股数
 	Price  decimal.Decimal // 价格
 	Amount decimal.Decimal // 金额
-	Reason string          // 交易原因
+	Reason string          // 交易原因代码，见 TradeReason 常量，输出时用 TradeReasonText 翻译
+}
+
+// 交易原因代码，保存在运行结果和数据库中，不随界面语言变化
+const (
+	TradeReasonIncluded   = "included"    // 股票被纳入
+	TradeReasonExcluded   = "excluded"    // 股票被剔除
+	TradeReasonMarginCall = "margin-call" // 追加保证金强制平仓
+	TradeReasonVolTarget  = "vol-target"  // 波动率目标减仓
+	TradeReasonRiskOff    = "risk-off"    // 市场 risk-off 减仓
+	TradeReasonScaleUp    = "scale-up"    // 建仓比例上调后加仓
+)
+
+// tradeReasonLabels 交易原因代码对应的消息
+var tradeReasonLabels = map[string]string{
+	TradeReasonIncluded:   "股票被纳入",
+	TradeReasonExcluded:   "股票被剔除",
+	TradeReasonMarginCall: "追加保证金强制平仓",
+	TradeReasonVolTarget:  "波动率目标减仓",
+	TradeReasonRiskOff:    "市场 risk-off 减仓",
+	TradeReasonScaleUp:    "建仓比例上调加仓",
+}
+
+// TradeReasonText 返回交易原因在当前语言下的描述；较早保存的运行中原因为中文描述，直接翻译
+func TradeReasonText(reason string) string {
+	if label, ok := tradeReasonLabels[reason]; ok {
+		return T(label)
+	}
+	return T(reason)
+}
+
+// SignalIssue 信号价格与股价文件或成交价不一致的记录
+type SignalIssue struct {
+	Date        time.Time       // 信号日期
+	Symbol      string          // 股票代码
+	Status      string          // "纳入" 或 "剔除"
+	SignalPrice string          // 信号中的原始 price 列
+	FilePrice   decimal.Decimal // 股价文件中信号日前最后一个收盘价
+	FillPrice   decimal.Decimal // 当月首个交易日成交价
+	Deviation   decimal.Decimal // 相对参考价格的偏差
+	Issue       string          // 问题类型
+	Skipped     bool            // 是否因此跳过买入
 }
 
 // PerformanceMetrics 绩效指标
@@ -80,4 +134,44 @@ type PerformanceMetrics struct {
 	WinRate           decimal.Decimal // 胜率
 	AverageReturn     decimal.Decimal // 平均收益率
 	TotalTrades       int             // 总交易次数
-}
\ No newline at end of file
+}
+// DrawdownEpisode 回撤区间
+type DrawdownEpisode struct {
+	PeakDate     time.Time       // 峰值日期
+	TroughDate   time.Time       // 谷底日期
+	RecoveryDate time.Time       // 恢复日期（未恢复时为零值）
+	PeakValue    decimal.Decimal // 峰值
+	TroughValue  decimal.Decimal // 谷底值
+	Depth        decimal.Decimal // 回撤幅度（负数）
+	Recovered    bool            // 是否已恢复至前高
+}
+
+// PeriodAttribution 单个月度区间的收益归因
+type PeriodAttribution struct {
+	Date              time.Time                  // 区间结束的报告日期
+	PortfolioReturn   decimal.Decimal            // 组合收益率
+	Contributions     map[string]decimal.Decimal // 各股票贡献（期初权重 × 区间收益率）
+	StockContribution decimal.Decimal            // 股票贡献合计
+	CashWeight        decimal.Decimal            // 期初现金权重
+	CashDrag          decimal.Decimal            // 现金拖累（现金权重 × 持仓收益率的机会成本，负数表示拖累）
+	Costs             decimal.Decimal            // 融资利息和融券费用（负数）
+	Residual          decimal.Decimal            // 未解释部分（如缺失价格数据）
+}
+
+// SymbolAttribution 单只股票在整个回测期间的累计贡献
+type SymbolAttribution struct {
+	Symbol        string          // 股票代码
+	PnL           decimal.Decimal // 累计盈亏金额
+	Contribution  decimal.Decimal // 累计贡献（累计盈亏 / 初始资金）
+	PeriodsHeld   int             // 持有的月度区间数
+	AverageWeight decimal.Decimal // 持有期间的平均权重
+	Open          bool            // 期末是否仍持有
+}
+
+// SymbolMetadata 股票分类信息
+type SymbolMetadata struct {
+	Symbol    string `csv:"symbol"`
+	Sector    string `csv:"sector"`     // 板块，如 Semiconductors、Software
+	Industry  string `csv:"industry"`   // 细分行业，如 Semiconductor Equipment
+	MarketCap string `csv:"market_cap"` // 市值分档：Mega、Large、Mid、Small
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 校验问题级别
+const (
+	ValidationError   = "error"   // 会导致回测跳过交易或无法运行
+	ValidationWarning = "warning" // 回测可以运行，但结果可能与预期不同
+)
+
+// ValidationIssue 配置或输入数据的校验问题
+type ValidationIssue struct {
+	Level   string // 问题级别
+	Month   string // 所属月份（YYYY-MM），与月份无关时为空
+	Symbol  string // 股票或指数代码，与股票无关时为空
+	Message string // 问题描述
+}
+
+// ValidateInputs 在不运行回测的情况下检查回测区间内每个月的交易信号、信号股票的股价数据、
+// 信号参考价格以及基准指数、市场状态指数和股票分类元数据；数据源无法创建时返回错误
+func ValidateInputs(config *Config) ([]ValidationIssue, error) {
+	dataLoader, err := newDataLoader(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []ValidationIssue
+	add := func(level string, month time.Time, symbol, format string, args ...interface{}) {
+		issue := ValidationIssue{Level: level, Symbol: symbol, Message: fmt.Sprintf(format, args...)}
+		if !month.IsZero() {
+			issue.Month = month.Format("2006-01")
+		}
+		issues = append(issues, issue)
+	}
+
+	// 股票分类元数据，设置板块上限时必须能加载
+	var metadata map[string]*SymbolMetadata
+	var metadataErr error
+	if config.MetadataFile != "" {
+		metadata, metadataErr = LoadSymbolMetadata(config.MetadataFile)
+	}
+
+	// 每只股票的股价加载错误只报告一次
+	priceCache := make(map[string]map[string]*StockPrice)
+	priceErrors := make(map[string]bool)
+	tolerance := decimal.NewFromFloat(config.SignalPriceTolerance)
+
+	for date := config.StartDate; !date.After(config.EndDate); date = time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC) {
+		signals, err := dataLoader.LoadTradeSignals(date)
+		if err != nil {
+			add(ValidationError, date, "", T("无法加载交易信号: %v"), err)
+			continue
+		}
+		if len(signals) == 0 {
+			add(ValidationWarning, date, "", T("没有交易信号"))
+			continue
+		}
+
+		for _, signal := range signals {
+			if signal.Status != "纳入" && signal.Status != "剔除" {
+				add(ValidationWarning, date, signal.Symbol, T("未知的信号状态: %s"), signal.Status)
+				continue
+			}
+
+			// 纳入股票缺少股价时无法买入；剔除股票仅在持有时需要股价
+			level := ValidationError
+			if signal.Status == "剔除" {
+				level = ValidationWarning
+			}
+
+			stockPrices, cached := priceCache[signal.Symbol]
+			if !cached {
+				stockPrices, err = dataLoader.LoadStockPrice(signal.Symbol)
+				if err != nil {
+					stockPrices = nil
+					if !priceErrors[signal.Symbol] {
+						priceErrors[signal.Symbol] = true
+						// 元数据中已分类的股票缺少股价时视为已知的数据缺口，回测跳过买入
+						if _, known := metadata[signal.Symbol]; known {
+							add(ValidationWarning, date, signal.Symbol, T("元数据中的股票缺少股价数据，回测将跳过买入: %v"), err)
+						} else {
+							add(level, date, signal.Symbol, T("无法加载股价数据: %v"), err)
+						}
+					}
+				}
+				priceCache[signal.Symbol] = stockPrices
+			}
+			if stockPrices == nil {
+				continue
+			}
+
+			if _, err := dataLoader.GetFirstTradingDay(date.Year(), int(date.Month()), stockPrices); err != nil {
+				add(level, date, signal.Symbol, T("当月没有股价数据"))
+				continue
+			}
+
+			// 信号参考价格应与信号日前最后一个收盘价一致
+			if !tolerance.IsPositive() || strings.TrimSpace(signal.Price) == "" {
+				continue
+			}
+			reference, ok := signal.ReferencePrice()
+			if !ok {
+				add(ValidationWarning, date, signal.Symbol, T("信号参考价格无效: %s"), signal.Price)
+				continue
+			}
+			if closeKey, ok := lastDateBefore(stockPrices, date); ok {
+				filePrice := stockPrices[closeKey].Close
+				deviation := filePrice.Div(reference).Sub(decimal.NewFromInt(1))
+				if deviation.Abs().GreaterThan(tolerance) {
+					add(ValidationWarning, date, signal.Symbol, T("信号参考价格 %s 与收盘价 %s 偏差 %s%%"),
+						reference.String(), filePrice.String(), deviation.Mul(decimal.NewFromInt(100)).StringFixed(2))
+				}
+			}
+		}
+	}
+
+	// 基准指数和市场状态指数
+	indexSource, err := newIndexPriceSource(config)
+	if err != nil {
+		return nil, err
+	}
+	checkIndex := func(symbol, level string) {
+		if _, err := indexSource.LoadStockPrice(symbol); err != nil {
+			add(level, time.Time{}, symbol, T("无法加载指数数据: %v"), err)
+		}
+	}
+	if config.RegimeEnabled {
+		checkIndex(config.RegimeSymbol, ValidationError)
+	}
+	if config.Benchmark != "" && (!config.RegimeEnabled || config.Benchmark != config.RegimeSymbol) {
+		checkIndex(config.Benchmark, ValidationWarning)
+	}
+
+	if metadataErr != nil {
+		level := ValidationWarning
+		if config.SectorCap > 0 {
+			level = ValidationError
+		}
+		add(level, time.Time{}, "", T("加载股票分类元数据失败: %v"), metadataErr)
+	}
+
+	return issues, nil
+}
+
+// PrintValidationIssues 输出校验问题列表和汇总，返回错误和警告数量
+func PrintValidationIssues(w io.Writer, issues []ValidationIssue) (int, int) {
+	errorCount, warningCount := 0, 0
+	if len(issues) > 0 {
+		fmt.Fprintf(w, "%-8s %-8s %-10s %s\n", T("Level"), T("Month"), T("Symbol"), T("Message"))
+	}
+	for _, issue := range issues {
+		if issue.Level == ValidationError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+		fmt.Fprintf(w, "%-8s %-8s %-10s %s\n", T(issue.Level), issue.Month, issue.Symbol, issue.Message)
+	}
+	fmt.Fprintf(w, T("\n校验完成: %d 个错误, %d 个警告\n"), errorCount, warningCount)
+	return errorCount, warningCount
+}
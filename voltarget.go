@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 建仓比例模式
+const (
+	AllocationModeFixed     = "fixed"      // 固定比例（AllocationRatio）
+	AllocationModeVolTarget = "vol-target" // 按目标波动率缩放总仓位
+)
+
+// tradingDaysPerYear 年化使用的交易日数
+const tradingDaysPerYear = 252
+
+// VolTargetState 某个月的波动率目标计算结果
+type VolTargetState struct {
+	EstimatedVolatility decimal.Decimal // 基于持仓历史日收益率的事前年化波动率
+	TargetVolatility    decimal.Decimal // 目标年化波动率
+	Exposure            decimal.Decimal // 目标总仓位比例（已按上下限截断）
+}
+
+// evaluateVolTarget 计算当月目标仓位，未启用波动率目标模式时返回 nil
+// 使用当前持仓（按市值加权）的历史日收益率估算组合波动率；空仓时使用当月纳入股票等权估算
+func (strategy *TradingStrategy) evaluateVolTarget(portfolio *Portfolio, signals []*TradeSignal, date time.Time) *VolTargetState {
+	if strategy.config.AllocationMode != AllocationModeVolTarget {
+		return nil
+	}
+
+	weights := make(map[string]decimal.Decimal)
+	for symbol, position := range portfolio.Positions {
+		if position.MarketValue.IsPositive() {
+			weights[symbol] = position.MarketValue
+		}
+	}
+	if len(weights) == 0 {
+		for _, signal := range signals {
+			if signal.Status == "纳入" {
+				weights[signal.Symbol] = decimal.NewFromInt(1)
+			}
+		}
+	}
+
+	state := &VolTargetState{
+		TargetVolatility: decimal.NewFromFloat(strategy.config.TargetVolatility),
+		Exposure:         decimal.NewFromFloat(strategy.config.MaxExposure),
+	}
+
+	volatility, err := strategy.estimatePortfolioVolatility(weights, date)
+	if err != nil {
+		slog.Warn(T("估算组合波动率失败，使用最大仓位"), "month", date.Format("2006-01"), "err", err)
+		return state
+	}
+	state.EstimatedVolatility = volatility
+
+	if volatility.IsPositive() {
+		exposure := state.TargetVolatility.Div(volatility)
+		minExposure := decimal.NewFromFloat(strategy.config.MinExposure)
+		maxExposure := decimal.NewFromFloat(strategy.config.MaxExposure)
+		if exposure.LessThan(minExposure) {
+			exposure = minExposure
+		}
+		if exposure.GreaterThan(maxExposure) {
+			exposure = maxExposure
+		}
+		state.Exposure = exposure
+	}
+
+	slog.Info(T("波动率目标"), "month", date.Format("2006-01"),
+		"estimated_pct", volatility.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		"target_pct", state.TargetVolatility.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		"exposure_pct", state.Exposure.Mul(decimal.NewFromInt(100)).StringFixed(2))
+
+	return state
+}
+
+// exAnteVolatility 返回报告中使用的事前波动率，未启用时为零
+func exAnteVolatility(state *VolTargetState) decimal.Decimal {
+	if state == nil {
+		return decimal.Zero
+	}
+	return state.EstimatedVolatility
+}
+
+// estimatePortfolioVolatility 根据权重和当月首个交易日之前的日收益率估算年化波动率
+func (strategy *TradingStrategy) estimatePortfolioVolatility(weights map[string]decimal.Decimal, date time.Time) (decimal.Decimal, error) {
+	if len(weights) == 0 {
+		return decimal.Zero, errors.New(T("没有可用于估算的持仓"))
+	}
+
+	lookback := strategy.config.VolLookback
+	cutoff := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC).Format("20060102")
+
+	// 按日期汇总加权日收益率
+	totalWeight := decimal.Zero
+	for _, weight := range weights {
+		totalWeight = totalWeight.Add(weight)
+	}
+
+	symbols := make([]string, 0, len(weights))
+	for symbol := range weights {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	portfolioReturns := make(map[string]float64)
+	coveredWeight := make(map[string]float64)
+	for _, symbol := range symbols {
+		weight := weights[symbol]
+		returns, err := strategy.trailingDailyReturns(symbol, cutoff, lookback)
+		if err != nil {
+			slog.Warn(T("无法获取历史收益率"), "symbol", symbol, "err", err)
+			continue
+		}
+		w := weight.Div(totalWeight).InexactFloat64()
+		for dateKey, r := range returns {
+			portfolioReturns[dateKey] += w * r
+			coveredWeight[dateKey] += w
+		}
+	}
+
+	// 对部分股票缺失数据的交易日按已覆盖权重归一化
+	dates := make([]string, 0, len(portfolioReturns))
+	for dateKey := range portfolioReturns {
+		dates = append(dates, dateKey)
+	}
+	sort.Strings(dates)
+
+	var series []float64
+	for _, dateKey := range dates {
+		if coveredWeight[dateKey] > 0 {
+			series = append(series, portfolioReturns[dateKey]/coveredWeight[dateKey])
+		}
+	}
+	if len(series) < 2 {
+		return decimal.Zero, errors.New(T("历史数据不足"))
+	}
+
+	return decimal.NewFromFloat(annualizedStdDev(series, tradingDaysPerYear)), nil
+}
+
+// trailingDailyReturns 返回指定股票在截止日期之前最近 lookback 个交易日的日收益率
+func (strategy *TradingStrategy) trailingDailyReturns(symbol, cutoff string, lookback int) (map[string]float64, error) {
+	stockPrices, err := strategy.dataLoader.LoadStockPrice(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []string
+	for dateKey := range stockPrices {
+		if dateKey < cutoff {
+			dates = append(dates, dateKey)
+		}
+	}
+	sort.Strings(dates)
+	if len(dates) > lookback+1 {
+		dates = dates[len(dates)-lookback-1:]
+	}
+
+	returns := make(map[string]float64)
+	for i := 1; i < len(dates); i++ {
+		previous := stockPrices[dates[i-1]].Close
+		if previous.IsZero() {
+			continue
+		}
+		returns[dates[i]] = stockPrices[dates[i]].Close.Div(previous).Sub(decimal.NewFromInt(1)).InexactFloat64()
+	}
+
+	return returns, nil
+}
+
+// annualizedStdDev 计算样本标准差并按周期数年化
+func annualizedStdDev(values []float64, periodsPerYear int) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values) - 1)
+
+	return math.Sqrt(variance) * math.Sqrt(float64(periodsPerYear))
+}
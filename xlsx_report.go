@@ -0,0 +1,351 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// Excel 数字格式，金额、比例以数值写入单元格，由格式控制显示
+var (
+	xlsxCurrencyFormat = "$#,##0.00"
+	xlsxPercentFormat  = "0.00%"
+	xlsxNumberFormat   = "#,##0.00"
+	xlsxDateFormat     = "yyyy-mm-dd"
+)
+
+// xlsxStyles 工作簿中使用的单元格样式
+type xlsxStyles struct {
+	header   int
+	text     int
+	date     int
+	currency int
+	percent  int
+	number   int
+	shares   int
+	integer  int
+}
+
+// xlsxColumn 工作表的一列
+type xlsxColumn struct {
+	Header string
+	Width  float64
+}
+
+// GenerateExcelReport 将整个运行写入 run.xlsx：
+// Summary（绩效指标）、Monthly（月度业绩）、每月一个 Holdings YYYY-MM 持仓表、Trades（交易明细）、Final Positions（期末持仓）
+func (rg *ReportGenerator) GenerateExcelReport(reports []*MonthlyReport) error {
+	if len(reports) == 0 {
+		return errors.New(T("没有报告数据"))
+	}
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	styles, err := newXLSXStyles(file)
+	if err != nil {
+		return fmt.Errorf(T("创建 Excel 样式失败: %v"), err)
+	}
+
+	// 默认工作表改名为 Summary，保证其为第一个工作表
+	if err := file.SetSheetName(file.GetSheetName(0), "Summary"); err != nil {
+		return fmt.Errorf(T("创建工作表失败: %v"), err)
+	}
+	if err := rg.writeSummarySheet(file, styles, reports); err != nil {
+		return err
+	}
+	if err := rg.writeMonthlySheet(file, styles, reports); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		sheet := fmt.Sprintf("Holdings %s", report.Date.Format("2006-01"))
+		if err := rg.writeHoldingsSheet(file, styles, sheet, report, true); err != nil {
+			return err
+		}
+	}
+	if err := rg.writeTradesSheet(file, styles, reports); err != nil {
+		return err
+	}
+	if err := rg.writeHoldingsSheet(file, styles, "Final Positions", reports[len(reports)-1], false); err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(rg.config.OutputDir, "run.xlsx")
+	if err := file.SaveAs(filePath); err != nil {
+		return fmt.Errorf(T("保存 Excel 报告失败: %v"), err)
+	}
+
+	slog.Info(T("Excel 报告已生成"), "path", filePath)
+	return nil
+}
+
+// writeSummarySheet 写入绩效指标，指标名称随 -lang 切换
+func (rg *ReportGenerator) writeSummarySheet(file *excelize.File, styles *xlsxStyles, reports []*MonthlyReport) error {
+	metrics := CalculatePerformanceMetrics(reports)
+	lastReport := reports[len(reports)-1]
+
+	rows := [][]excelize.Cell{
+		{styles.textCell(T("Run ID")), styles.textCell(rg.config.RunID)},
+		{styles.textCell(T("Start Date")), styles.dateCell(rg.config.StartDate)},
+		{styles.textCell(T("End Date")), styles.dateCell(rg.config.EndDate)},
+		{styles.textCell(T("Months")), styles.intCell(len(reports))},
+		{styles.textCell(T("Initial Capital")), styles.currencyCell(decimal.NewFromFloat(rg.config.InitialCapital))},
+		{styles.textCell(T("Final Value")), styles.currencyCell(lastReport.TotalValue)},
+		{styles.textCell(T("Cash Balance")), styles.currencyCell(lastReport.Cash)},
+		{styles.textCell(T("Total Stock Value")), styles.currencyCell(lastReport.StockValue)},
+		{styles.textCell(T("Total Return")), styles.percentCell(metrics.TotalReturn)},
+		{styles.textCell(T("Annualized Return")), styles.percentCell(metrics.AnnualizedReturn)},
+		{styles.textCell(T("Max Drawdown")), styles.percentCell(metrics.MaxDrawdown)},
+		{styles.textCell(T("Volatility")), styles.percentCell(metrics.Volatility)},
+		{styles.textCell(T("Sharpe Ratio")), styles.numberCell(metrics.SharpeRatio)},
+		{styles.textCell(T("Win Rate")), styles.percentCell(metrics.WinRate)},
+		{styles.textCell(T("Average Monthly Return")), styles.percentCell(metrics.AverageReturn)},
+		{styles.textCell(T("Total Trades")), styles.intCell(metrics.TotalTrades)},
+	}
+
+	columns := []xlsxColumn{{"Metric", 26}, {"Value", 20}}
+	return writeXLSXSheet(file, styles, "Summary", columns, rows)
+}
+
+// writeMonthlySheet 写入月度业绩，列与 performance_summary.csv 一致
+func (rg *ReportGenerator) writeMonthlySheet(file *excelize.File, styles *xlsxStyles, reports []*MonthlyReport) error {
+	columns := []xlsxColumn{
+		{"Date", 12}, {"Total Value", 16}, {"Cash", 16}, {"Stock Value", 16},
+		{"Monthly Return", 16}, {"Cumulative Return", 18}, {"Number of Positions", 20},
+	}
+	fields := rg.summaryFields()
+	for _, field := range fields {
+		columns = append(columns, xlsxColumn{field.name, field.width})
+	}
+
+	var rows [][]excelize.Cell
+	for _, report := range reports {
+		row := []excelize.Cell{
+			styles.dateCell(report.Date),
+			styles.currencyCell(report.TotalValue),
+			styles.currencyCell(report.Cash),
+			styles.currencyCell(report.StockValue),
+			styles.percentCell(report.MonthlyReturn),
+			styles.percentCell(report.CumulativeReturn),
+			styles.intCell(len(report.Positions)),
+		}
+		for _, field := range fields {
+			row = append(row, styles.summaryCell(field, report))
+		}
+		rows = append(rows, row)
+	}
+
+	return writeXLSXSheet(file, styles, "Monthly", columns, rows)
+}
+
+// writeHoldingsSheet 写入单月持仓，按市值从大到小排序，withActions 为 true 时附带当月交易行为
+func (rg *ReportGenerator) writeHoldingsSheet(file *excelize.File, styles *xlsxStyles, sheet string, report *MonthlyReport, withActions bool) error {
+	columns := []xlsxColumn{
+		{"Symbol", 10}, {"Buy Date", 12}, {"Buy Price", 12}, {"Current Price", 14},
+		{"Shares", 12}, {"Market Value", 16}, {"Cost Basis", 16}, {"P&L", 14},
+		{"P&L %", 10}, {"Weight", 10},
+	}
+	if withActions {
+		columns = append(columns, xlsxColumn{"Trading Actions", 40})
+	}
+
+	var positions []*Position
+	for _, position := range report.Positions {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].MarketValue.GreaterThan(positions[j].MarketValue)
+	})
+
+	var rows [][]excelize.Cell
+	for _, position := range positions {
+		row := []excelize.Cell{
+			styles.textCell(position.Symbol),
+			styles.dateCell(position.BuyDate),
+			styles.currencyCell(position.BuyPrice),
+			styles.currencyCell(position.CurrentPrice),
+			styles.sharesCell(position.Shares),
+			styles.currencyCell(position.MarketValue),
+			styles.currencyCell(position.CostBasis),
+			styles.currencyCell(position.PnL),
+			styles.percentCell(position.PnLPercent),
+			styles.percentCell(position.Weight),
+		}
+		if withActions {
+			row = append(row, styles.textCell(rg.formatTradingActions(report.TradingActions, position.Symbol)))
+		}
+		rows = append(rows, row)
+	}
+
+	return writeXLSXSheet(file, styles, sheet, columns, rows)
+}
+
+// writeTradesSheet 写入全部交易明细
+func (rg *ReportGenerator) writeTradesSheet(file *excelize.File, styles *xlsxStyles, reports []*MonthlyReport) error {
+	columns := []xlsxColumn{
+		{"Date", 12}, {"Symbol", 10}, {"Action", 8}, {"Shares", 12},
+		{"Price", 12}, {"Amount", 16}, {"Reason", 40},
+	}
+
+	var rows [][]excelize.Cell
+	for _, report := range reports {
+		for _, action := range report.TradingActions {
+			rows = append(rows, []excelize.Cell{
+				styles.dateCell(action.Date),
+				styles.textCell(action.Symbol),
+				styles.textCell(action.Action),
+				styles.sharesCell(action.Shares),
+				styles.currencyCell(action.Price),
+				styles.currencyCell(action.Amount),
+				styles.textCell(TradeReasonText(action.Reason)),
+			})
+		}
+	}
+
+	return writeXLSXSheet(file, styles, "Trades", columns, rows)
+}
+
+// writeXLSXSheet 创建工作表并写入标题行和数据行，冻结标题行
+func writeXLSXSheet(file *excelize.File, styles *xlsxStyles, sheet string, columns []xlsxColumn, rows [][]excelize.Cell) error {
+	if index, _ := file.GetSheetIndex(sheet); index < 0 {
+		if _, err := file.NewSheet(sheet); err != nil {
+			return fmt.Errorf(T("创建工作表 %s 失败: %v"), sheet, err)
+		}
+	}
+
+	writer, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return fmt.Errorf(T("创建工作表 %s 失败: %v"), sheet, err)
+	}
+
+	for i, column := range columns {
+		if err := writer.SetColWidth(i+1, i+1, column.Width); err != nil {
+			return fmt.Errorf(T("设置工作表 %s 列宽失败: %v"), sheet, err)
+		}
+	}
+	if err := writer.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf(T("设置工作表 %s 冻结窗格失败: %v"), sheet, err)
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = excelize.Cell{StyleID: styles.header, Value: column.Header}
+	}
+	if err := writer.SetRow("A1", header); err != nil {
+		return fmt.Errorf(T("写入工作表 %s 标题失败: %v"), sheet, err)
+	}
+
+	for i, row := range rows {
+		values := make([]interface{}, len(row))
+		for j, cell := range row {
+			values[j] = cell
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := writer.SetRow(cell, values); err != nil {
+			return fmt.Errorf(T("写入工作表 %s 数据失败: %v"), sheet, err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf(T("写入工作表 %s 失败: %v"), sheet, err)
+	}
+	return nil
+}
+
+// newXLSXStyles 注册工作簿使用的单元格样式
+func newXLSXStyles(file *excelize.File) (*xlsxStyles, error) {
+	styles := &xlsxStyles{}
+	definitions := []struct {
+		id    *int
+		style *excelize.Style
+	}{
+		{&styles.header, &excelize.Style{
+			Font: &excelize.Font{Bold: true},
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+		}},
+		{&styles.text, &excelize.Style{}},
+		{&styles.date, &excelize.Style{CustomNumFmt: &xlsxDateFormat}},
+		{&styles.currency, &excelize.Style{CustomNumFmt: &xlsxCurrencyFormat}},
+		{&styles.percent, &excelize.Style{CustomNumFmt: &xlsxPercentFormat}},
+		{&styles.number, &excelize.Style{CustomNumFmt: &xlsxNumberFormat}},
+		{&styles.shares, &excelize.Style{NumFmt: 0}},  // General，整数股不显示小数点
+		{&styles.integer, &excelize.Style{NumFmt: 3}}, // #,##0
+	}
+	for _, definition := range definitions {
+		id, err := file.NewStyle(definition.style)
+		if err != nil {
+			return nil, err
+		}
+		*definition.id = id
+	}
+	return styles, nil
+}
+
+// textCell 文本单元格
+func (styles *xlsxStyles) textCell(value string) excelize.Cell {
+	return excelize.Cell{StyleID: styles.text, Value: value}
+}
+
+// dateCell 日期单元格，零值日期输出为空
+func (styles *xlsxStyles) dateCell(value time.Time) excelize.Cell {
+	if value.IsZero() {
+		return excelize.Cell{StyleID: styles.text}
+	}
+	return excelize.Cell{StyleID: styles.date, Value: value}
+}
+
+// currencyCell 金额单元格
+func (styles *xlsxStyles) currencyCell(value decimal.Decimal) excelize.Cell {
+	return excelize.Cell{StyleID: styles.currency, Value: xlsxFloat(value)}
+}
+
+// percentCell 比例单元格，0.1 显示为 10.00%
+func (styles *xlsxStyles) percentCell(value decimal.Decimal) excelize.Cell {
+	return excelize.Cell{StyleID: styles.percent, Value: xlsxFloat(value)}
+}
+
+// numberCell 保留两位小数的数值单元格
+func (styles *xlsxStyles) numberCell(value decimal.Decimal) excelize.Cell {
+	return excelize.Cell{StyleID: styles.number, Value: xlsxFloat(value)}
+}
+
+// sharesCell 股数单元格，碎股按实际小数位显示
+func (styles *xlsxStyles) sharesCell(value decimal.Decimal) excelize.Cell {
+	return excelize.Cell{StyleID: styles.shares, Value: xlsxFloat(value)}
+}
+
+// summaryCell 按月度汇总字段的数值类型生成单元格
+func (styles *xlsxStyles) summaryCell(field summaryField, report *MonthlyReport) excelize.Cell {
+	switch field.kind {
+	case summaryText:
+		return styles.textCell(field.text(report))
+	case summaryCurrency:
+		return styles.currencyCell(field.value(report))
+	case summaryPercent:
+		return styles.percentCell(field.value(report))
+	default:
+		return styles.numberCell(field.value(report))
+	}
+}
+
+// intCell 整数单元格
+func (styles *xlsxStyles) intCell(value int) excelize.Cell {
+	return excelize.Cell{StyleID: styles.integer, Value: value}
+}
+
+// xlsxFloat 将 decimal 转换为 Excel 单元格使用的浮点数
+func xlsxFloat(value decimal.Decimal) float64 {
+	result, _ := value.Float64()
+	return result
+}